@@ -21,22 +21,24 @@ package main
 // THE SOFTWARE.
 
 import (
-	"bytes"
+	"bufio"
 	"context"
-	"encoding/binary"
 	"fmt"
 	"io"
 	"log"
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 
 	pb "github.com/bhojpur/speech/pkg/api/v1/stream"
+	"github.com/bhojpur/speech/pkg/api/v1/stream/client"
 	"github.com/bhojpur/speech/pkg/portaudio"
 	"github.com/bhojpur/speech/pkg/utils"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
 
@@ -64,42 +66,194 @@ func main() {
 	}
 
 	defer conn.Close()
-	client := pb.NewStreamerClient(conn)
 
-	stream, err := client.Audio(context.Background(), &emptypb.Empty{})
+	track, err := parseTrack(os.Getenv("TRACK"))
 	if err != nil {
-		log.Fatal("Bhojpur Speech: audio client error: ", err)
+		log.Fatal("Bhojpur Speech: invalid track: ", err)
+	}
+	rate, err := strconv.ParseInt(utils.GetenvDefault("RATE", "0"), 10, 64)
+	if err != nil {
+		log.Fatal("Bhojpur Speech: invalid rate: ", err)
+	}
+	channels, err := strconv.ParseInt(utils.GetenvDefault("CHANNELS", "0"), 10, 64)
+	if err != nil {
+		log.Fatal("Bhojpur Speech: invalid channels: ", err)
+	}
+	format, ok := pb.PayloadFormat_value[strings.ToUpper(utils.GetenvDefault("FORMAT", pb.PayloadFormat_LINEAR16_PCM.String()))]
+	if !ok {
+		log.Fatal("Bhojpur Speech: invalid format: ", os.Getenv("FORMAT"))
+	}
+	minDelay, err := time.ParseDuration(utils.GetenvDefault("MIN_DELAY", client.DefaultMinDelay.String()))
+	if err != nil {
+		log.Fatal("Bhojpur Speech: invalid minimum delay: ", err)
+	}
+	maxDelay, err := time.ParseDuration(utils.GetenvDefault("MAX_DELAY", client.DefaultMaxDelay.String()))
+	if err != nil {
+		log.Fatal("Bhojpur Speech: invalid maximum delay: ", err)
 	}
 
 	portaudio.Initialize()
 	defer portaudio.Terminate()
-	out := make([]int16, 8192)
-	var portAudioStream *portaudio.Stream
-
-	for {
-		time.Sleep(50 * time.Millisecond)
-		utils.CallClear()
-		res, err := stream.Recv()
-		if err == io.EOF {
-			return
+	player := client.New(pb.NewStreamerClient(conn), &portAudioSink{},
+		client.WithJitter(minDelay, maxDelay),
+		client.WithEvents(logEvent),
+	)
+
+	if radio, _ := strconv.ParseBool(os.Getenv("RADIO")); radio {
+		// the radio plays the same audio for all its listeners
+		err = player.Listen(context.Background(), &pb.ListenRequest{Format: pb.PayloadFormat(format)})
+	} else {
+		log.Println("Bhojpur Speech: commands are p (pause), r (resume), s <seconds> (seek), t <name|index> (track) and q (quit)")
+		ctx, quit := context.WithCancel(context.Background())
+		defer quit()
+		err = player.Play(ctx, &pb.PlayConfig{
+			Track:    track,
+			Rate:     rate,
+			Channels: channels,
+			Format:   pb.PayloadFormat(format),
+		}, controls(os.Stdin, quit))
+		if ctx.Err() != nil {
+			// stopped by the quit command
+			err = nil
 		}
-		if err != nil {
-			log.Fatal("Bhojpur Speech: cannot receive response: ", err)
+	}
+	if err != nil {
+		log.Println("Bhojpur Speech: audio client error: ", err)
+	}
+}
+
+// logEvent reports the events of the player.
+func logEvent(e client.Event) {
+	data := e.Data
+	switch e.Type {
+	case client.Connected:
+		log.Printf("Bhojpur Speech: now playing %d - %s %v / %v", data.GetSequence(), data.GetFilename(),
+			data.GetPosition().AsDuration().Round(time.Second), data.GetDuration().AsDuration().Round(time.Second))
+	case client.Reconnecting:
+		log.Printf("Bhojpur Speech: connection lost (%v), reconnecting in %v", e.Err, e.Delay)
+	case client.Dropped:
+		log.Printf("Bhojpur Speech: missed %d chunks", data.GetDropped())
+	case client.Underrun:
+		log.Println("Bhojpur Speech: buffer underrun")
+	case client.FormatChanged:
+		log.Printf("Bhojpur Speech: playing %d Hz, %d channels", e.Rate, e.Channels)
+	case client.EndOfTrack:
+		log.Printf("Bhojpur Speech: end of %d - %s", data.GetSequence(), data.GetFilename())
+	case client.Paused:
+		log.Printf("Bhojpur Speech: paused at %v", data.GetPosition().AsDuration().Round(time.Second))
+	}
+}
+
+// controls reads the commands typed on r. The channel is closed when the
+// commands end, letting the call end with the track, while the quit command
+// calls quit to stop the playback at once.
+func controls(r io.Reader, quit func()) <-chan *pb.PlayRequest {
+	requests := make(chan *pb.PlayRequest)
+	go func() {
+		defer close(requests)
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			fields := strings.Fields(scanner.Text())
+			if len(fields) == 0 {
+				continue
+			}
+			req := &pb.PlayRequest{}
+			switch fields[0] {
+			case "p":
+				req.Request = &pb.PlayRequest_Pause{Pause: &emptypb.Empty{}}
+			case "r":
+				req.Request = &pb.PlayRequest_Resume{Resume: &emptypb.Empty{}}
+			case "s":
+				if len(fields) < 2 {
+					continue
+				}
+				seconds, err := strconv.ParseFloat(fields[1], 64)
+				if err != nil {
+					log.Printf("Bhojpur Speech: invalid position: %v", err)
+					continue
+				}
+				req.Request = &pb.PlayRequest_Seek{Seek: durationpb.New(time.Duration(seconds * float64(time.Second)))}
+			case "t":
+				track, err := parseTrack(strings.Join(fields[1:], " "))
+				if err != nil {
+					log.Printf("Bhojpur Speech: invalid track: %v", err)
+					continue
+				}
+				req.Request = &pb.PlayRequest_Select{Select: track}
+			case "q":
+				quit()
+				return
+			default:
+				continue
+			}
+			requests <- req
+		}
+	}()
+	return requests
+}
+
+// parseTrack selects a track by index when the selection is a number, by
+// name otherwise.
+func parseTrack(selection string) (*pb.Track, error) {
+	if selection == "" {
+		return &pb.Track{}, nil
+	}
+	if index, err := strconv.Atoi(selection); err == nil {
+		if index < 1 {
+			return nil, fmt.Errorf("index %d is not positive", index)
 		}
-		log.Printf("Bhojpur Speech: client playing: %d - %s", res.GetSequence(), res.GetFilename())
+		return &pb.Track{Index: int32(index)}, nil
+	}
+	return &pb.Track{Name: selection}, nil
+}
 
-		// log.Printf("audio data: ", res.GetData())
+// portAudioSink plays the audio on the default output device. PortAudio
+// writes buffers of a fixed size, which the samples fill in turn.
+type portAudioSink struct {
+	stream *portaudio.Stream
+	out    []int16
+	filled int
+}
 
-		if portAudioStream == nil {
-			portAudioStream, err = portaudio.OpenDefaultStream(0, int(res.GetChannels()), float64(res.GetRate()), len(out), &out)
-			utils.Chk(err)
-			defer portAudioStream.Close()
+func (s *portAudioSink) Open(rate, channels int) error {
+	s.out = make([]int16, channels*rate*int(client.DefaultFrame/time.Millisecond)/1000)
+	s.filled = 0
+	stream, err := portaudio.OpenDefaultStream(0, channels, float64(rate), len(s.out)/channels, &s.out)
+	if err != nil {
+		return err
+	}
+	if err := stream.Start(); err != nil {
+		stream.Close()
+		return err
+	}
+	s.stream = stream
+	return nil
+}
 
-			utils.Chk(portAudioStream.Start())
-			defer portAudioStream.Stop()
+func (s *portAudioSink) Write(samples []int16) error {
+	for len(samples) > 0 {
+		n := copy(s.out[s.filled:], samples)
+		samples = samples[n:]
+		s.filled += n
+		if s.filled == len(s.out) {
+			if err := s.stream.Write(); err != nil {
+				return err
+			}
+			s.filled = 0
 		}
+	}
+	return nil
+}
 
-		utils.Chk(binary.Read(bytes.NewBuffer(res.GetData()), binary.LittleEndian, out))
-		utils.Chk(portAudioStream.Write())
+// Close plays the rest of the samples, padded with silence, and stops.
+func (s *portAudioSink) Close() error {
+	if s.filled > 0 {
+		for i := s.filled; i < len(s.out); i++ {
+			s.out[i] = 0
+		}
+		s.stream.Write()
+		s.filled = 0
 	}
+	s.stream.Stop()
+	return s.stream.Close()
 }
@@ -24,6 +24,7 @@ import (
 	"bufio"
 	"encoding/binary"
 	"fmt"
+	"math"
 	"os"
 	"os/exec"
 	"os/signal"
@@ -31,6 +32,7 @@ import (
 	"time"
 
 	"github.com/bhojpur/speech/pkg/portaudio"
+	"github.com/bhojpur/speech/pkg/vad"
 	"github.com/coder/flog"
 	"github.com/spf13/pflag"
 	"go.coder.com/cli"
@@ -38,7 +40,15 @@ import (
 
 var signals = []os.Signal{syscall.SIGHUP, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT}
 
-type recordCmd struct{ outFile string }
+// sampleRate is the rate of the recording, written in the AIFF header as an
+// 80-bit float.
+const sampleRate = 44100
+
+type recordCmd struct {
+	outFile    string
+	vad        bool
+	minSilence time.Duration
+}
 
 // Spec returns a command spec containing a description of it's usage.
 func (cmd *recordCmd) Spec() cli.CommandSpec {
@@ -52,9 +62,12 @@ func (cmd *recordCmd) Spec() cli.CommandSpec {
 // RegisterFlags initializes how a flag set is processed for a particular command.
 func (cmd *recordCmd) RegisterFlags(fl *pflag.FlagSet) {
 	fl.StringVarP(&cmd.outFile, "out", "o", cmd.outFile, "Name the output file.")
+	fl.BoolVar(&cmd.vad, "vad", cmd.vad, "Stop recording when the speech ends.")
+	fl.DurationVar(&cmd.minSilence, "silence", vad.DefaultMinSilence, "Silence ending the speech with --vad.")
 }
 
-// Run starts recording microphone audio and stops when input is received from stdin.
+// Run starts recording microphone audio and stops when input is received from stdin,
+// or when the speech ends with --vad.
 func (cmd *recordCmd) Run(fl *pflag.FlagSet) {
 	flog.Info("Bhojpur Speech recorder")
 	flog.Info("Copyright (c) 2018 by Bhojpur Consulting Private Limtied, India.")
@@ -154,7 +167,7 @@ func (cmd *recordCmd) Run(fl *pflag.FlagSet) {
 
 	in := make([]int32, 64)
 
-	stream, err := portaudio.OpenDefaultStream(1, 0, 44100, len(in), in)
+	stream, err := portaudio.OpenDefaultStream(1, 0, sampleRate, len(in), in)
 	if err != nil {
 		flog.Error("failed to open audio stream : %v", err)
 		fl.Usage()
@@ -201,6 +214,13 @@ func (cmd *recordCmd) Run(fl *pflag.FlagSet) {
 
 	flog.Info("press enter to stop speech recording")
 
+	var detector *vad.Detector
+	if cmd.vad {
+		detector = vad.New(sampleRate, vad.WithMinSilence(cmd.minSilence))
+		flog.Info("recording stops when the speech ends")
+	}
+	samples := make([]int16, len(in))
+
 recording:
 	for {
 		select {
@@ -217,6 +237,21 @@ recording:
 				flog.Error("failed to write audio data to file as binary : %v", err)
 			}
 			numSamples += len(in)
+
+			if detector == nil {
+				continue
+			}
+			for i, sample := range in {
+				samples[i] = int16(sample >> 16)
+			}
+			ended := false
+			for _, event := range detector.Write(samples) {
+				flog.Info("%v at %v", event.Type, event.Offset)
+				ended = ended || event.Type == vad.SpeechEnd
+			}
+			if ended {
+				break recording
+			}
 		}
 	}
 
@@ -250,7 +285,6 @@ func writeFormChunk(f *os.File) error {
 }
 
 func writeCommonChunk(f *os.File) error {
-	sr := []byte{0x40, 0x0e, 0xac, 0x44, 0, 0, 0, 0, 0, 0}
 
 	// header
 	if _, err := f.WriteString("COMM"); err != nil {
@@ -272,13 +306,29 @@ func writeCommonChunk(f *os.File) error {
 	if err := binary.Write(f, binary.BigEndian, int16(32)); err != nil {
 		return err
 	}
-	//80-bit sample rate 44100
-	if _, err := f.Write(sr); err != nil {
+	// 80-bit sample rate
+	sr := float80(sampleRate)
+	if _, err := f.Write(sr[:]); err != nil {
 		return err
 	}
 	return nil
 }
 
+// float80 encodes a positive number as the big-endian 80-bit IEEE 754
+// extended precision float of the AIFF headers.
+func float80(f float64) [10]byte {
+	var b [10]byte
+	if f <= 0 {
+		return b
+	}
+	// f = frac * 2^exp with frac in [0.5, 1), the mantissa keeps the
+	// integer bit explicitly
+	frac, exp := math.Frexp(f)
+	binary.BigEndian.PutUint16(b[:2], uint16(exp-1+16383))
+	binary.BigEndian.PutUint64(b[2:], uint64(math.Ldexp(frac, 64)))
+	return b
+}
+
 func writeSoundChunk(f *os.File) error {
 	// header
 	if _, err := f.WriteString("SSND"); err != nil {
@@ -21,19 +21,27 @@ package main
 // THE SOFTWARE.
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strconv"
+	"syscall"
+	"time"
 
 	pb "github.com/bhojpur/speech/pkg/api/v1/stream"
+	"github.com/bhojpur/speech/pkg/auth"
+	"github.com/bhojpur/speech/pkg/metrics"
 	"github.com/bhojpur/speech/pkg/utils"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
 )
 
+// shutdownGrace is the time given to the calls to end on shutdown.
+const shutdownGrace = 10 * time.Second
+
 func main() {
 	log.Println("Bhojpur Speech streaming server (MP3)")
 	log.Println("Copyright (c) 2018 by Bhojpur Consulting Private Limited, India.")
@@ -42,7 +50,10 @@ func main() {
 	wd, _ := os.Getwd()
 	certFile := filepath.Join(wd, "ssl", "cert.pem")
 	keyFile := filepath.Join(wd, "ssl", "private.key")
-	creds, _ := credentials.NewServerTLSFromFile(certFile, keyFile)
+	serverOpts, _, usage, err := auth.FromEnv(certFile, keyFile, auth.WithPublicServices(metrics.Services...))
+	if err != nil {
+		log.Fatalf("server engine failed to set up authentication: %v", err)
+	}
 
 	serverAddr := fmt.Sprintf(
 		"%s:%s",
@@ -54,9 +65,64 @@ func main() {
 		log.Fatalf("server engine failed to listen: %v", err)
 	}
 
-	grpcServer := grpc.NewServer(grpc.Creds(creds))
-	pb.RegisterStreamerServer(grpcServer, pb.NewServer())
+	streamOpts := []pb.Option{pb.WithLibrary(utils.GetenvDefault("LIBRARY", pb.DefaultLibrary))}
+	if radio, _ := strconv.ParseBool(os.Getenv("RADIO")); radio {
+		// all the listeners hear the library at the same time
+		buffer, err := strconv.Atoi(utils.GetenvDefault("RADIO_BUFFER", strconv.Itoa(pb.DefaultRadioBuffer)))
+		if err != nil {
+			log.Fatalf("server engine has invalid radio buffer: %v", err)
+		}
+		policy := pb.DropOldest
+		switch drop := utils.GetenvDefault("RADIO_DROP", "oldest"); drop {
+		case "oldest":
+		case "newest":
+			policy = pb.DropNewest
+		default:
+			log.Fatalf("server engine has invalid radio drop policy %q", drop)
+		}
+		streamOpts = append(streamOpts, pb.WithRadio(pb.DefaultRadioRate, 2, buffer, policy))
+	}
+
+	if recordings := os.Getenv("RECORDINGS"); recordings != "" {
+		// the clients upload their recordings to the directory
+		maxUpload, err := strconv.ParseInt(utils.GetenvDefault("MAX_UPLOAD", strconv.Itoa(pb.DefaultMaxUpload)), 10, 64)
+		if err != nil {
+			log.Fatalf("server engine has invalid upload limit: %v", err)
+		}
+		streamOpts = append(streamOpts, pb.WithRecordings(recordings, maxUpload))
+	}
+
+	serverOpts = append(serverOpts, grpc.ChainStreamInterceptor(metrics.StreamInterceptor()))
+	grpcServer := grpc.NewServer(serverOpts...)
+	// on the way out, the streams stop before the usage they charged is
+	// saved
+	defer usage.Close()
+	streamServer := pb.NewServer(streamOpts...)
+	defer streamServer.Close()
+	pb.RegisterStreamerServer(grpcServer, streamServer)
+	metrics.Register(grpcServer)
+
+	metricsAddr := utils.GetenvDefault("METRICS_ADDR", "localhost:9100")
+	go func() {
+		log.Printf("server engine serving metrics on http://%s%s\n", metricsAddr, metrics.Path)
+		if err := metrics.ListenAndServe(metricsAddr); err != nil {
+			log.Fatalf("server engine failed to serve metrics: %v", err)
+		}
+	}()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		log.Println("server engine shutting down")
+		// the calls still running after the grace period are cut off
+		timer := time.AfterFunc(shutdownGrace, grpcServer.Stop)
+		defer timer.Stop()
+		grpcServer.GracefulStop()
+	}()
 
 	log.Printf("server engine listening on gRPC %s\n", serverAddr)
-	grpcServer.Serve(listen)
+	if err := grpcServer.Serve(listen); err != nil {
+		log.Printf("server engine failed to serve: %v", err)
+	}
 }
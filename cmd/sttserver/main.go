@@ -0,0 +1,259 @@
+package main
+
+// Copyright (c) 2018 Bhojpur Consulting Private Limited, India. All rights reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/bhojpur/speech/pkg/api/v1/gateway"
+	pb "github.com/bhojpur/speech/pkg/api/v1/server"
+	"github.com/bhojpur/speech/pkg/asr"
+	_ "github.com/bhojpur/speech/pkg/asr/fake"
+	"github.com/bhojpur/speech/pkg/auth"
+	_ "github.com/bhojpur/speech/pkg/coqui"
+	"github.com/bhojpur/speech/pkg/metrics"
+	"github.com/bhojpur/speech/pkg/models"
+	"github.com/bhojpur/speech/pkg/service/moderation"
+	"github.com/bhojpur/speech/pkg/speaker"
+	"github.com/bhojpur/speech/pkg/utils"
+	"github.com/bhojpur/speech/pkg/vad"
+	_ "github.com/bhojpur/speech/pkg/vosk"
+	"google.golang.org/grpc"
+)
+
+// shutdownGrace is the time given to the calls to end on shutdown.
+const shutdownGrace = 10 * time.Second
+
+func main() {
+	log.Println("Bhojpur Speech recognition server")
+	log.Println("Copyright (c) 2018 by Bhojpur Consulting Private Limited, India.")
+	log.Printf("All rights reserved.\n")
+
+	wd, _ := os.Getwd()
+	certFile := filepath.Join(wd, "ssl", "cert.pem")
+	keyFile := filepath.Join(wd, "ssl", "private.key")
+	serverOpts, guard, usage, err := auth.FromEnv(certFile, keyFile, auth.WithPublicServices(metrics.Services...))
+	if err != nil {
+		log.Fatalf("server engine failed to set up authentication: %v", err)
+	}
+	clientCA := os.Getenv("CLIENT_CA")
+
+	var engine asr.Engine
+	var registry *models.Registry
+	engineName := utils.GetenvDefault("ENGINE", "vosk")
+	if modelsDir := os.Getenv("MODELS"); modelsDir != "" {
+		// models are loaded on demand and share a memory budget
+		budget, err := strconv.ParseInt(utils.GetenvDefault("MODELS_MEMORY_MB", "0"), 10, 64)
+		if err != nil {
+			log.Fatalf("server engine has invalid models memory budget: %v", err)
+		}
+		registry, err = models.New(modelsDir,
+			models.WithEngine(engineName),
+			models.WithMemoryBudget(budget<<20),
+			models.WithDefault(os.Getenv("DEFAULT_MODEL")),
+		)
+		if err != nil {
+			log.Fatalf("server engine failed to read models %s: %v", modelsDir, err)
+		}
+		defer registry.Close()
+		log.Printf("server engine found %d models in %s\n", len(registry.List()), modelsDir)
+	} else {
+		modelPath := utils.GetenvDefault("MODEL", "model")
+		var err error
+		start := time.Now()
+		engine, err = asr.Open(engineName, modelPath)
+		if err != nil {
+			log.Fatalf("server engine failed to load %s model %s: %v", engineName, modelPath, err)
+		}
+		metrics.Since(metrics.ModelLoadSeconds.WithLabelValues(engine.Name()), start)
+		defer engine.Close()
+	}
+
+	serverAddr := fmt.Sprintf(
+		"%s:%s",
+		utils.GetenvDefault("HOST", "localhost"),
+		utils.GetenvDefault("PORT", strconv.Itoa(pb.PORT)),
+	)
+	listen, err := net.Listen("tcp", serverAddr)
+	if err != nil {
+		log.Fatalf("server engine failed to listen: %v", err)
+	}
+
+	workers, err := strconv.Atoi(utils.GetenvDefault("WORKERS", "2"))
+	if err != nil {
+		log.Fatalf("server engine has invalid number of workers: %v", err)
+	}
+	maxMessageSize, err := strconv.Atoi(utils.GetenvDefault("MAX_MESSAGE_MB", "256"))
+	if err != nil {
+		log.Fatalf("server engine has invalid maximum message size: %v", err)
+	}
+
+	var speakers *speaker.Database
+	if speakersPath := os.Getenv("SPEAKERS"); speakersPath != "" {
+		identify, err := strconv.ParseFloat(utils.GetenvDefault("SPEAKER_IDENTIFY_THRESHOLD", fmt.Sprint(speaker.DefaultIdentifyThreshold)), 64)
+		if err != nil {
+			log.Fatalf("server engine has invalid speaker identification threshold: %v", err)
+		}
+		verify, err := strconv.ParseFloat(utils.GetenvDefault("SPEAKER_VERIFY_THRESHOLD", fmt.Sprint(speaker.DefaultVerifyThreshold)), 64)
+		if err != nil {
+			log.Fatalf("server engine has invalid speaker verification threshold: %v", err)
+		}
+		speakers, err = speaker.Open(speakersPath, speaker.WithIdentifyThreshold(identify), speaker.WithVerifyThreshold(verify))
+		if err != nil {
+			log.Fatalf("server engine failed to open speakers %s: %v", speakersPath, err)
+		}
+	}
+
+	opts := []pb.Option{
+		pb.WithRegistry(registry),
+		pb.WithSpeakers(speakers),
+		pb.WithWorkers(workers),
+	}
+	if networks := os.Getenv("WEBHOOK_NETWORKS"); networks != "" {
+		var allowed []*net.IPNet
+		for _, cidr := range strings.Split(networks, ",") {
+			_, network, err := net.ParseCIDR(strings.TrimSpace(cidr))
+			if err != nil {
+				log.Fatalf("server engine has invalid webhook network: %v", err)
+			}
+			allowed = append(allowed, network)
+		}
+		opts = append(opts, pb.WithWebhookNetworks(allowed...))
+	}
+	if useVAD, _ := strconv.ParseBool(os.Getenv("VAD")); useVAD {
+		minSilence, err := time.ParseDuration(utils.GetenvDefault("VAD_MIN_SILENCE", vad.DefaultMinSilence.String()))
+		if err != nil {
+			log.Fatalf("server engine has invalid minimum silence: %v", err)
+		}
+		opts = append(opts, pb.WithVoiceActivityDetection(vad.WithMinSilence(minSilence)))
+	}
+	if probe := os.Getenv("LANGUAGE_PROBE"); probe != "" {
+		duration, err := time.ParseDuration(probe)
+		if err != nil {
+			log.Fatalf("server engine has invalid language probe duration: %v", err)
+		}
+		var languages []string
+		if list := os.Getenv("PROBE_LANGUAGES"); list != "" {
+			languages = strings.Split(list, ",")
+		}
+		opts = append(opts, pb.WithLanguageProbe(duration, languages...))
+	}
+	if moderationDir := os.Getenv("MODERATION"); moderationDir != "" {
+		filters, err := moderation.LoadMaskFilters(moderationDir)
+		if err != nil {
+			log.Fatalf("server engine failed to read word lists %s: %v", moderationDir, err)
+		}
+		opts = append(opts, pb.WithModeration(filters))
+	}
+
+	// on the way out, the recognitions stop before the usage they charged
+	// is saved, and the models are released last
+	defer usage.Close()
+	sttServer := pb.NewServer(engine, opts...)
+	defer sttServer.Close()
+
+	// long-running recognitions carry whole recordings in a single message
+	serverOpts = append(serverOpts,
+		grpc.MaxRecvMsgSize(maxMessageSize<<20),
+		grpc.ChainStreamInterceptor(metrics.StreamInterceptor()),
+	)
+	grpcServer := grpc.NewServer(serverOpts...)
+	pb.RegisterSttServiceServer(grpcServer, sttServer)
+	metrics.Register(grpcServer)
+
+	metricsAddr := utils.GetenvDefault("METRICS_ADDR", "localhost:9101")
+	go func() {
+		log.Printf("server engine serving metrics on http://%s%s\n", metricsAddr, metrics.Path)
+		if err := metrics.ListenAndServe(metricsAddr); err != nil {
+			log.Fatalf("server engine failed to serve metrics: %v", err)
+		}
+	}()
+
+	// the gateway serves the clients unable to speak gRPC streams, through
+	// the same interceptors
+	gatewayOpts := []gateway.Option{gateway.WithMaxBodySize(int64(maxMessageSize) << 20)}
+	if guard != nil {
+		gatewayOpts = append(gatewayOpts,
+			gateway.WithUnaryInterceptors(guard.UnaryInterceptor()),
+			gateway.WithStreamInterceptors(guard.StreamInterceptor()),
+		)
+	}
+	gatewayOpts = append(gatewayOpts, gateway.WithStreamInterceptors(metrics.StreamInterceptor()))
+	gatewayServer := &http.Server{
+		Addr:    utils.GetenvDefault("GATEWAY_ADDR", fmt.Sprintf("localhost:%d", gateway.PORT)),
+		Handler: gateway.New(sttServer, gatewayOpts...),
+	}
+	// credentials must not travel in clear, plaintext is only served on
+	// request to unauthenticated clients
+	tlsConfig, err := auth.ServerTLSConfig(certFile, keyFile, clientCA)
+	plaintext, _ := strconv.ParseBool(os.Getenv("GATEWAY_PLAINTEXT"))
+	switch {
+	case err != nil && guard != nil:
+		log.Fatalf("server engine failed to load the gateway TLS credentials required by CLIENT_CA or API_KEYS: %v", err)
+	case err != nil && !plaintext:
+		log.Fatalf("server engine failed to load the gateway TLS credentials, set GATEWAY_PLAINTEXT=true to serve HTTP: %v", err)
+	}
+	go func() {
+		var err error
+		if tlsConfig == nil {
+			log.Printf("server engine listening on HTTP %s\n", gatewayServer.Addr)
+			err = gatewayServer.ListenAndServe()
+		} else {
+			gatewayServer.TLSConfig = tlsConfig
+			log.Printf("server engine listening on HTTPS %s\n", gatewayServer.Addr)
+			err = gatewayServer.ListenAndServeTLS("", "")
+		}
+		if err != http.ErrServerClosed {
+			log.Fatalf("server engine failed to serve the gateway: %v", err)
+		}
+	}()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		log.Println("server engine shutting down")
+		// the calls still running after the grace period are cut off
+		timer := time.AfterFunc(shutdownGrace, func() {
+			gatewayServer.Close()
+			grpcServer.Stop()
+		})
+		defer timer.Stop()
+		gatewayServer.Shutdown(context.Background())
+		grpcServer.GracefulStop()
+	}()
+
+	log.Printf("server engine listening on gRPC %s\n", serverAddr)
+	if err := grpcServer.Serve(listen); err != nil {
+		log.Printf("server engine failed to serve: %v", err)
+	}
+}
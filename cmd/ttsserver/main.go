@@ -0,0 +1,116 @@
+package main
+
+// Copyright (c) 2018 Bhojpur Consulting Private Limited, India. All rights reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"time"
+
+	pb "github.com/bhojpur/speech/pkg/api/v1/tts"
+	"github.com/bhojpur/speech/pkg/auth"
+	_ "github.com/bhojpur/speech/pkg/espeak"
+	"github.com/bhojpur/speech/pkg/metrics"
+	"github.com/bhojpur/speech/pkg/tts"
+	_ "github.com/bhojpur/speech/pkg/tts/fake"
+	"github.com/bhojpur/speech/pkg/utils"
+	"google.golang.org/grpc"
+)
+
+// shutdownGrace is the time given to the calls to end on shutdown.
+const shutdownGrace = 10 * time.Second
+
+func main() {
+	log.Println("Bhojpur Speech synthesis server")
+	log.Println("Copyright (c) 2018 by Bhojpur Consulting Private Limited, India.")
+	log.Printf("All rights reserved.\n")
+
+	wd, _ := os.Getwd()
+	certFile := filepath.Join(wd, "ssl", "cert.pem")
+	keyFile := filepath.Join(wd, "ssl", "private.key")
+	serverOpts, _, usage, err := auth.FromEnv(certFile, keyFile, auth.WithPublicServices(metrics.Services...))
+	if err != nil {
+		log.Fatalf("server engine failed to set up authentication: %v", err)
+	}
+
+	engineName := utils.GetenvDefault("ENGINE", "espeak")
+	engine, err := tts.Open(engineName, os.Getenv("ESPEAK_DATA"))
+	if err != nil {
+		log.Fatalf("server engine failed to open %s: %v", engineName, err)
+	}
+	defer engine.Close()
+
+	// on the way out, the usage is saved before the engine is closed
+	defer usage.Close()
+
+	maxTextLength, err := strconv.Atoi(utils.GetenvDefault("MAX_TEXT_LENGTH", strconv.Itoa(pb.DefaultMaxTextLength)))
+	if err != nil {
+		log.Fatalf("server engine has invalid maximum text length: %v", err)
+	}
+
+	serverAddr := fmt.Sprintf(
+		"%s:%s",
+		utils.GetenvDefault("HOST", pb.ADDR),
+		utils.GetenvDefault("PORT", strconv.Itoa(pb.PORT)),
+	)
+	listen, err := net.Listen("tcp", serverAddr)
+	if err != nil {
+		log.Fatalf("server engine failed to listen: %v", err)
+	}
+
+	serverOpts = append(serverOpts,
+		grpc.ChainStreamInterceptor(metrics.StreamInterceptor()),
+	)
+	grpcServer := grpc.NewServer(serverOpts...)
+	pb.RegisterTtsServiceServer(grpcServer, pb.NewServer(engine, pb.WithMaxTextLength(maxTextLength)))
+	metrics.Register(grpcServer)
+
+	metricsAddr := utils.GetenvDefault("METRICS_ADDR", "localhost:9102")
+	go func() {
+		log.Printf("server engine serving metrics on http://%s%s\n", metricsAddr, metrics.Path)
+		if err := metrics.ListenAndServe(metricsAddr); err != nil {
+			log.Fatalf("server engine failed to serve metrics: %v", err)
+		}
+	}()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		log.Println("server engine shutting down")
+		// the calls still running after the grace period are cut off
+		timer := time.AfterFunc(shutdownGrace, grpcServer.Stop)
+		defer timer.Stop()
+		grpcServer.GracefulStop()
+	}()
+
+	log.Printf("server engine listening on gRPC %s\n", serverAddr)
+	if err := grpcServer.Serve(listen); err != nil {
+		log.Printf("server engine failed to serve: %v", err)
+	}
+}
@@ -20,18 +20,25 @@ package main
 // OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
 // THE SOFTWARE.
 
-// It captures data from your default microphone until you press Enter, after which
-// it plays back the captured audio.
+// It captures data from your default microphone until you press Enter, or until the
+// speech ends with -vad, after which it plays back the captured audio.
 
 import (
-	"fmt"
+	"bufio"
+	"flag"
 	"log"
 	"os"
+	"time"
 
 	engine "github.com/bhojpur/speech/pkg/miniaudio"
+	"github.com/bhojpur/speech/pkg/vad"
 )
 
 func main() {
+	useVAD := flag.Bool("vad", false, "stop recording when the speech ends")
+	minSilence := flag.Duration("silence", vad.DefaultMinSilence, "silence ending the speech with -vad")
+	flag.Parse()
+
 	log.Println("Bhojpur Speech capture utility")
 	log.Println("Copyright (c) 2018 by Bhojpur Consulting Private Limited, India.")
 	log.Printf("All rights reserved.\n")
@@ -60,6 +67,13 @@ func main() {
 	var capturedSampleCount uint32
 	pCapturedSamples := make([]byte, 0)
 
+	// the speech end is signalled once, from the capture callback
+	var detector *vad.Detector
+	speechEnded := make(chan time.Duration, 1)
+	if *useVAD {
+		detector = vad.New(int(deviceConfig.SampleRate), vad.WithMinSilence(*minSilence))
+	}
+
 	sizeInBytes := uint32(engine.SampleSizeInBytes(deviceConfig.Capture.Format))
 	onRecvFrames := func(pSample2, pSample []byte, framecount uint32) {
 
@@ -71,6 +85,17 @@ func main() {
 
 		capturedSampleCount = newCapturedSampleCount
 
+		if detector == nil {
+			return
+		}
+		for _, event := range detector.WritePCM(pSample) {
+			if event.Type == vad.SpeechEnd {
+				select {
+				case speechEnded <- event.Offset:
+				default:
+				}
+			}
+		}
 	}
 
 	log.Println("Please speak now, I am recording your voice...")
@@ -89,8 +114,20 @@ func main() {
 		os.Exit(1)
 	}
 
+	enter := make(chan struct{})
+	go func() {
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			enter <- struct{}{}
+		}
+	}()
+
 	log.Println("Press ENTER key to stop recording...")
-	fmt.Scanln()
+	select {
+	case <-enter:
+	case offset := <-speechEnded:
+		log.Printf("Speech ended at %v\n", offset)
+	}
 
 	device.Uninit()
 
@@ -127,7 +164,7 @@ func main() {
 	}
 
 	log.Println("Press ENTER key to quit this program...")
-	fmt.Scanln()
+	<-enter
 
 	device.Uninit()
 }
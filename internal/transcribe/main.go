@@ -22,13 +22,28 @@ package main
 
 import (
 	"bufio"
+	"bytes"
+	"context"
 	"flag"
 	"fmt"
 	"io"
 	"log"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"syscall"
+	"time"
 
-	vosk "github.com/bhojpur/speech/pkg/vosk"
+	"github.com/bhojpur/speech/pkg/asr"
+	_ "github.com/bhojpur/speech/pkg/asr/fake"
+	"github.com/bhojpur/speech/pkg/audio"
+	_ "github.com/bhojpur/speech/pkg/coqui"
+	"github.com/bhojpur/speech/pkg/models"
+	"github.com/bhojpur/speech/pkg/subtitle"
+	"github.com/bhojpur/speech/pkg/transcribe"
+	_ "github.com/bhojpur/speech/pkg/vosk"
 )
 
 func main() {
@@ -36,45 +51,257 @@ func main() {
 	log.Println("Copyright (c) 2018 by Bhojpur Consulting Private Limited, India.")
 	log.Printf("All rights reserved.\n")
 
-	var filename string
-	flag.StringVar(&filename, "f", "", "file to transcribe")
+	var filename, output, engineName, modelsDir, modelName, language, encodingName, formatName string
+	var sampleRate, maxAlternatives int
+	var words bool
+	var maxLineLength, maxLines int
+	var minDuration, maxDuration time.Duration
+	var readingSpeed float64
+	var dir, list, outDir, manifest string
+	var workers int
+	var resume bool
+	flag.StringVar(&filename, "f", "", "file to transcribe, - for the standard input")
+	flag.StringVar(&output, "o", "", "file to write the transcript to, the standard output by default")
+	flag.StringVar(&engineName, "engine", "vosk", "recognition engine: "+strings.Join(asr.Engines(), ", "))
+	flag.StringVar(&modelsDir, "models", "", "directory of the models, selected by -model or -lang")
+	flag.StringVar(&modelName, "model", "", "model name, or model path without -models")
+	flag.StringVar(&language, "lang", "", "language of the model in BCP-47")
+	flag.StringVar(&encodingName, "encoding", "auto", "input encoding: auto, wav, mp3, aiff, linear16, mulaw or alaw")
+	flag.IntVar(&sampleRate, "rate", 0, "sample rate of the raw encodings in Hz")
+	flag.StringVar(&formatName, "format", "text", "transcript format: text, json, srt, vtt or ttml")
+	flag.IntVar(&maxAlternatives, "max-alternatives", 1, "maximum number of hypotheses per utterance")
+	flag.BoolVar(&words, "words", false, "include the word timings, implied by the subtitle formats")
+	flag.IntVar(&maxLineLength, "max-line-length", subtitle.DefaultMaxLineLength, "maximum characters per subtitle line")
+	flag.IntVar(&maxLines, "max-lines", subtitle.DefaultMaxLines, "maximum lines per subtitle")
+	flag.DurationVar(&minDuration, "min-duration", subtitle.DefaultMinDuration, "minimum duration of a subtitle")
+	flag.DurationVar(&maxDuration, "max-duration", subtitle.DefaultMaxDuration, "maximum duration of a subtitle")
+	flag.Float64Var(&readingSpeed, "reading-speed", subtitle.DefaultReadingSpeed, "characters read per second")
+	flag.StringVar(&dir, "dir", "", "batch mode: transcribe the recordings found in the directory")
+	flag.StringVar(&list, "list", "", "batch mode: transcribe the recordings listed in the file")
+	flag.StringVar(&outDir, "out", "", "batch mode: directory of the transcripts, next to the recordings by default")
+	flag.StringVar(&manifest, "manifest", "", "batch mode: JSONL manifest of the results, manifest.jsonl in -out by default")
+	flag.IntVar(&workers, "workers", runtime.NumCPU(), "batch mode: recordings transcribed concurrently")
+	flag.BoolVar(&resume, "resume", false, "batch mode: skip the recordings done according to the manifest")
 	flag.Parse()
 
-	model, err := vosk.NewModel("model")
+	format, err := transcribe.ParseFormat(formatName)
 	if err != nil {
 		log.Fatal(err)
 	}
+	var encoding *audio.Encoding
+	if encodingName != "auto" {
+		e, err := audio.ParseEncoding(encodingName)
+		if err != nil {
+			log.Fatal(err)
+		}
+		encoding = &e
+	}
+	layout := []subtitle.Option{
+		subtitle.WithMaxLineLength(maxLineLength),
+		subtitle.WithMaxLines(maxLines),
+		subtitle.WithMinDuration(minDuration),
+		subtitle.WithMaxDuration(maxDuration),
+		subtitle.WithReadingSpeed(readingSpeed),
+	}
 
-	sampleRate := 16000.0
-	rec, err := vosk.NewRecognizer(model, sampleRate)
+	if dir != "" || list != "" {
+		var jobs []transcribe.Job
+		if dir != "" {
+			jobs, err = transcribe.Walk(dir, outDir, format)
+		} else {
+			jobs, err = transcribe.ReadJobs(list, outDir, format)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		if manifest == "" {
+			manifest = filepath.Join(outDir, "manifest.jsonl")
+		}
+
+		engine, release, err := openEngine(engineName, modelsDir, modelName, language)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer release()
+
+		opts := []transcribe.BatchOption{
+			transcribe.WithWorkers(workers),
+			transcribe.WithSampleRate(sampleRate),
+			transcribe.WithAlternatives(maxAlternatives),
+			transcribe.WithFormat(format, layout...),
+		}
+		if encoding != nil {
+			opts = append(opts, transcribe.WithEncoding(*encoding))
+		}
+		if words {
+			opts = append(opts, transcribe.WithWords())
+		}
+		if err := runBatch(engine, jobs, manifest, resume, opts...); err != nil {
+			log.Print(err)
+			release()
+			os.Exit(1)
+		}
+		return
+	}
+
+	if filename == "" {
+		filename = flag.Arg(0)
+	}
+	in := os.Stdin
+	if filename != "-" {
+		if filename == "" {
+			flag.Usage()
+			os.Exit(2)
+		}
+		in, err = os.Open(filename)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer in.Close()
+	}
+	reader := bufio.NewReader(in)
+
+	if encoding == nil {
+		// a short file is still detected, or rejected, from what was read
+		header, _ := reader.Peek(12)
+		e, err := transcribe.DetectEncoding(filename, header)
+		if err != nil {
+			log.Fatalf("%v, use -encoding", err)
+		}
+		encoding = &e
+	}
+
+	engine, release, err := openEngine(engineName, modelsDir, modelName, language)
 	if err != nil {
 		log.Fatal(err)
 	}
-	rec.SetWords(1)
+	defer release()
+	log.Printf("transcribing %v audio with %s at %v Hz", *encoding, engine.Name(), engine.SampleRate())
 
-	file, err := os.Open(filename)
+	transcript, err := transcribe.Transcribe(engine, reader, transcribe.Config{
+		Encoding:        *encoding,
+		SampleRate:      sampleRate,
+		MaxAlternatives: maxAlternatives,
+		Words:           words || format.NeedsWords(),
+	})
 	if err != nil {
-		panic(err)
+		log.Fatal(err)
 	}
-	defer file.Close()
 
-	reader := bufio.NewReader(file)
-	buf := make([]byte, 4096)
+	var out io.Writer = os.Stdout
+	if output != "" {
+		f, err := os.Create(output)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		out = f
+	}
+	if err := transcribe.Write(out, transcript, format, layout...); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("transcribed %v of audio", transcript.Duration)
+}
 
-	for {
-		_, err := reader.Read(buf)
+// runBatch transcribes the jobs until done or interrupted by a signal. With
+// resume, the jobs done according to the manifest are skipped and the new
+// entries are appended to it.
+func runBatch(engine asr.Engine, jobs []transcribe.Job, manifest string, resume bool, opts ...transcribe.BatchOption) error {
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	cut := false
+	if resume {
+		data, err := os.ReadFile(manifest)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		entries, err := transcribe.ReadManifest(bytes.NewReader(data))
 		if err != nil {
-			if err != io.EOF {
-				log.Fatal(err)
-			}
+			return err
+		}
+		total := len(jobs)
+		jobs = transcribe.Remaining(jobs, entries)
+		log.Printf("resuming: %d of %d recordings done", total-len(jobs), total)
+		flags = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+		cut = len(data) > 0 && data[len(data)-1] != '\n'
+	}
+	if err := os.MkdirAll(filepath.Dir(manifest), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(manifest, flags, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if cut {
+		// the entry being written when interrupted ends the line
+		if _, err := f.Write([]byte("\n")); err != nil {
+			return err
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-			break
+	var done, failed int
+	var seconds float64
+	opts = append(opts, transcribe.WithProgress(func(e transcribe.Entry) {
+		if e.Status == transcribe.StatusFailed {
+			failed++
+			log.Printf("[%d/%d] %s failed: %s", done+failed, len(jobs), e.Input, e.Error)
+			return
 		}
+		done++
+		seconds += e.Duration
+		log.Printf("[%d/%d] %s: %.1fs of audio, real-time factor %.3f", done+failed, len(jobs), e.Input, e.Duration, e.RealTimeFactor)
+	}))
+	_, err = transcribe.NewBatch(engine, opts...).Run(ctx, jobs, f)
+	log.Printf("%d recordings done, %d failed, %.0fs of audio", done, failed, seconds)
+	if err == context.Canceled {
+		return fmt.Errorf("interrupted, run again with -resume to continue")
+	}
+	if err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d recordings failed, see %s", failed, manifest)
+	}
+	return nil
+}
 
-		if rec.AcceptWaveform(buf) != 0 {
-			fmt.Println(string(rec.Result()))
+// openEngine opens the model at the path given by name, or, with a models
+// directory, the model selected by name or language. It returns the engine
+// and the function releasing it.
+func openEngine(engineName, modelsDir, name, language string) (asr.Engine, func(), error) {
+	if modelsDir == "" {
+		if name == "" {
+			name = "model"
+		}
+		engine, err := asr.Open(engineName, name)
+		if err != nil {
+			return nil, nil, err
 		}
+		return engine, func() { engine.Close() }, nil
 	}
 
-	fmt.Println(string(rec.FinalResult()))
+	registry, err := models.New(modelsDir, models.WithEngine(engineName))
+	if err != nil {
+		return nil, nil, err
+	}
+	info, err := registry.Lookup(name, language)
+	if err != nil {
+		registry.Close()
+		return nil, nil, err
+	}
+	engine, release, err := registry.Acquire(info.Name)
+	if err != nil {
+		registry.Close()
+		return nil, nil, err
+	}
+	return engine, func() {
+		release()
+		registry.Close()
+	}, nil
 }
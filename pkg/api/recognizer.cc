@@ -30,6 +30,7 @@ using namespace kaldi::nnet3;
 Recognizer::Recognizer(Model *model, float sample_frequency) : model_(model), spk_model_(0), sample_frequency_(sample_frequency) {
 
     model_->Ref();
+    endpoint_config_ = model_->endpoint_config_;
 
     feature_pipeline_ = new kaldi::OnlineNnet2FeaturePipeline (model_->feature_info_);
     silence_weighting_ = new kaldi::OnlineSilenceWeighting(*model_->trans_model_, model_->feature_info_.silence_weighting_config, 3);
@@ -55,6 +56,7 @@ Recognizer::Recognizer(Model *model, float sample_frequency) : model_(model), sp
 Recognizer::Recognizer(Model *model, float sample_frequency, char const *grammar) : model_(model), spk_model_(0), sample_frequency_(sample_frequency)
 {
     model_->Ref();
+    endpoint_config_ = model_->endpoint_config_;
 
     feature_pipeline_ = new kaldi::OnlineNnet2FeaturePipeline (model_->feature_info_);
     silence_weighting_ = new kaldi::OnlineSilenceWeighting(*model_->trans_model_, model_->feature_info_.silence_weighting_config, 3);
@@ -116,6 +118,7 @@ Recognizer::Recognizer(Model *model, float sample_frequency, char const *grammar
 Recognizer::Recognizer(Model *model, float sample_frequency, SpkModel *spk_model) : model_(model), spk_model_(spk_model), sample_frequency_(sample_frequency) {
 
     model_->Ref();
+    endpoint_config_ = model_->endpoint_config_;
     spk_model->Ref();
 
     feature_pipeline_ = new kaldi::OnlineNnet2FeaturePipeline (model_->feature_info_);
@@ -262,6 +265,39 @@ void Recognizer::SetNLSML(bool nlsml)
     nlsml_ = nlsml;
 }
 
+void Recognizer::SetEndpointerMode(VoskEndpointerMode mode)
+{
+    float scale = 1.0;
+    switch (mode) {
+        case VOSK_EP_ANSWER_SHORT:
+            scale = 0.5;
+            break;
+        case VOSK_EP_ANSWER_LONG:
+            scale = 2.0;
+            break;
+        case VOSK_EP_ANSWER_VERY_LONG:
+            scale = 3.0;
+            break;
+        default:
+            break;
+    }
+    KALDI_LOG << "Updating endpointer scale " << scale;
+    endpoint_config_ = model_->endpoint_config_;
+    endpoint_config_.rule2.min_trailing_silence *= scale;
+    endpoint_config_.rule3.min_trailing_silence *= scale;
+    endpoint_config_.rule4.min_trailing_silence *= scale;
+}
+
+void Recognizer::SetEndpointerDelays(float t_start_max, float t_end, float t_max)
+{
+    KALDI_LOG << "Updating endpointer delays " << t_start_max << "," << t_end << "," << t_max;
+    endpoint_config_.rule1.min_trailing_silence = t_start_max;
+    endpoint_config_.rule2.min_trailing_silence = t_end;
+    endpoint_config_.rule3.min_trailing_silence = t_end;
+    endpoint_config_.rule4.min_trailing_silence = t_end;
+    endpoint_config_.rule5.max_utterance_length = t_max;
+}
+
 void Recognizer::SetSpkModel(SpkModel *spk_model)
 {
     if (state_ == RECOGNIZER_RUNNING) {
@@ -321,7 +357,7 @@ bool Recognizer::AcceptWaveform(Vector<BaseFloat> &wdata)
         spk_feature_->AcceptWaveform(sample_frequency_, wdata);
     }
 
-    if (decoder_->EndpointDetected(model_->endpoint_config_)) {
+    if (decoder_->EndpointDetected(endpoint_config_)) {
         return true;
     }
 
@@ -36,6 +36,7 @@
 
 #include "model.h"
 #include "spk_model.h"
+#include "vosk_api.h"
 
 using namespace kaldi;
 
@@ -57,6 +58,8 @@ class Recognizer {
         void SetWords(bool words);
         void SetPartialWords(bool partial_words);
         void SetNLSML(bool nlsml);
+        void SetEndpointerMode(VoskEndpointerMode mode);
+        void SetEndpointerDelays(float t_start_max, float t_end, float t_max);
         bool AcceptWaveform(const char *data, int len);
         bool AcceptWaveform(const short *sdata, int len);
         bool AcceptWaveform(const float *fdata, int len);
@@ -105,6 +108,7 @@ class Recognizer {
         bool words_ = false;
         bool partial_words_ = false;
         bool nlsml_ = false;
+        OnlineEndpointConfig endpoint_config_;
 
         float sample_frequency_;
         int32 frame_offset_;
@@ -0,0 +1,465 @@
+package gateway
+
+// Copyright (c) 2018 Bhojpur Consulting Private Limited, India. All rights reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// It exposes the SttService to the clients unable to speak gRPC streams,
+// such as web browsers, over WebSocket and plain HTTP. The requests go
+// through the same interceptors and recognition pipeline as the gRPC calls.
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/bhojpur/speech/pkg/api/v1/server"
+	"golang.org/x/net/websocket"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	ADDR string = "localhost"
+	PORT int    = 4002
+)
+
+const (
+	// StreamPath is the WebSocket endpoint of streaming recognition.
+	StreamPath = "/v1/stream"
+	// RecognizePath is the HTTP endpoint recognizing whole files.
+	RecognizePath = "/v1/recognize"
+
+	// APIKeyParameter is the query parameter carrying an API key, since
+	// browsers can not set the headers of a WebSocket.
+	APIKeyParameter = "api_key"
+
+	streamingRecognizeMethod = "/v1.server.SttService/StreamingRecognize"
+	recognizeMethod          = "/v1.server.SttService/Recognize"
+)
+
+// DefaultMaxBodySize bounds the size of the files posted for recognition.
+const DefaultMaxBodySize = 256 << 20
+
+// forwardedHeaders are the HTTP headers passed to the interceptors as
+// metadata.
+var forwardedHeaders = []string{"authorization", "x-api-key"}
+
+// Gateway serves the recognition over WebSocket and HTTP.
+type Gateway struct {
+	stt         server.SttServiceServer
+	unary       []grpc.UnaryServerInterceptor
+	stream      []grpc.StreamServerInterceptor
+	maxBodySize int64
+	mux         *http.ServeMux
+}
+
+// Option configures a Gateway.
+type Option func(*Gateway)
+
+// WithUnaryInterceptors runs the interceptors, in order, around the
+// recognition of posted files.
+func WithUnaryInterceptors(interceptors ...grpc.UnaryServerInterceptor) Option {
+	return func(g *Gateway) {
+		g.unary = append(g.unary, interceptors...)
+	}
+}
+
+// WithStreamInterceptors runs the interceptors, in order, around the
+// WebSocket streams.
+func WithStreamInterceptors(interceptors ...grpc.StreamServerInterceptor) Option {
+	return func(g *Gateway) {
+		g.stream = append(g.stream, interceptors...)
+	}
+}
+
+// WithMaxBodySize bounds the size of the files posted for recognition.
+func WithMaxBodySize(size int64) Option {
+	return func(g *Gateway) {
+		g.maxBodySize = size
+	}
+}
+
+// New creates a gateway relaying the requests to the recognition server.
+func New(stt server.SttServiceServer, opts ...Option) *Gateway {
+	g := &Gateway{
+		stt:         stt,
+		maxBodySize: DefaultMaxBodySize,
+		mux:         http.NewServeMux(),
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	g.mux.Handle(StreamPath, websocket.Server{Handler: g.serveStream})
+	g.mux.HandleFunc(RecognizePath, g.serveRecognize)
+	return g
+}
+
+// ServeHTTP implements http.Handler.
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	g.mux.ServeHTTP(w, r)
+}
+
+// serveStream relays a WebSocket to StreamingRecognize. The first message
+// is the JSON RecognitionConfig, binary messages carry the audio, and the
+// text message {"eof": true} ends it. The responses are sent back as JSON
+// StreamingRecognitionResponse messages, and the socket is closed once the
+// recognition is over.
+func (g *Gateway) serveStream(ws *websocket.Conn) {
+	defer ws.Close()
+	stream := &wsStream{ctx: callContext(ws.Request()), ws: ws}
+	handler := func(srv interface{}, ss grpc.ServerStream) error {
+		return g.stt.StreamingRecognize(&recognizeStream{ss})
+	}
+	info := &grpc.StreamServerInfo{FullMethod: streamingRecognizeMethod, IsClientStream: true, IsServerStream: true}
+	if err := chainStream(g.stream, handler)(g.stt, stream, info); err != nil {
+		websocket.JSON.Send(ws, errorOf(err))
+	}
+}
+
+// serveRecognize recognizes a posted file. A JSON body is a
+// RecognizeRequest; any other body is the audio itself, configured by the
+// JSON RecognitionConfig of the config query parameter. The encoding of the
+// audio defaults to its content type.
+func (g *Gateway) serveRecognize(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeError(w, status.Error(codes.Unimplemented, "only POST is supported"))
+		return
+	}
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, g.maxBodySize))
+	if err != nil {
+		writeError(w, status.Errorf(codes.InvalidArgument, "failed to read request: %v", err))
+		return
+	}
+	req, err := recognizeRequest(r, body)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return g.stt.Recognize(ctx, req.(*server.RecognizeRequest))
+	}
+	info := &grpc.UnaryServerInfo{Server: g.stt, FullMethod: recognizeMethod}
+	res, err := chainUnary(g.unary, handler)(callContext(r), req, info)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	data, err := protojson.Marshal(res.(proto.Message))
+	if err != nil {
+		writeError(w, status.Errorf(codes.Internal, "failed to encode response: %v", err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+// recognizeRequest reads the RecognizeRequest of a posted file.
+func recognizeRequest(r *http.Request, body []byte) (*server.RecognizeRequest, error) {
+	contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	req := &server.RecognizeRequest{}
+	if contentType == "application/json" {
+		if err := protojson.Unmarshal(body, req); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid request: %v", err)
+		}
+		return req, nil
+	}
+
+	req.Config = &server.RecognitionConfig{}
+	if config := r.URL.Query().Get("config"); config != "" {
+		if err := protojson.Unmarshal([]byte(config), req.Config); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid config: %v", err)
+		}
+	}
+	if req.Config.Specification == nil {
+		req.Config.Specification = &server.RecognitionSpec{}
+	}
+	spec := req.Config.Specification
+	if spec.AudioEncoding == server.RecognitionSpec_AUDIO_ENCODING_UNSPECIFIED {
+		spec.AudioEncoding = encodingOf(contentType)
+	}
+	req.AudioContent = body
+	return req, nil
+}
+
+// encodingOf maps the media type of audio to its encoding.
+func encodingOf(contentType string) server.RecognitionSpec_AudioEncoding {
+	switch contentType {
+	case "audio/wav", "audio/wave", "audio/x-wav", "audio/vnd.wave":
+		return server.RecognitionSpec_WAV
+	case "audio/mpeg", "audio/mp3":
+		return server.RecognitionSpec_MP3
+	case "audio/basic", "audio/pcmu":
+		return server.RecognitionSpec_MULAW
+	case "audio/pcma":
+		return server.RecognitionSpec_ALAW
+	}
+	return server.RecognitionSpec_AUDIO_ENCODING_UNSPECIFIED
+}
+
+// callContext returns the context of a request as the interceptors expect
+// it: the credentials in the incoming metadata and the TLS state in the
+// peer.
+func callContext(r *http.Request) context.Context {
+	md := metadata.MD{}
+	for _, header := range forwardedHeaders {
+		if value := r.Header.Get(header); value != "" {
+			md.Set(header, value)
+		}
+	}
+	if key := r.URL.Query().Get(APIKeyParameter); key != "" && len(md.Get("x-api-key")) == 0 {
+		md.Set("x-api-key", key)
+	}
+	ctx := metadata.NewIncomingContext(r.Context(), md)
+
+	p := &peer.Peer{Addr: remoteAddr(r.RemoteAddr)}
+	if r.TLS != nil {
+		p.AuthInfo = credentials.TLSInfo{State: *r.TLS}
+	}
+	return peer.NewContext(ctx, p)
+}
+
+type remoteAddr string
+
+func (a remoteAddr) Network() string { return "tcp" }
+func (a remoteAddr) String() string  { return string(a) }
+
+var _ net.Addr = remoteAddr("")
+
+// errorMessage is the JSON form of an error.
+type errorMessage struct {
+	Error struct {
+		Code    int    `json:"code"`
+		Status  string `json:"status"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func errorOf(err error) *errorMessage {
+	st := status.Convert(err)
+	msg := &errorMessage{}
+	msg.Error.Code = int(st.Code())
+	msg.Error.Status = strings.ToUpper(codeName(st.Code()))
+	msg.Error.Message = st.Message()
+	return msg
+}
+
+// codeName returns the name of a code in the form of google.rpc.Code.
+func codeName(code codes.Code) string {
+	switch code {
+	case codes.Canceled:
+		return "CANCELLED"
+	case codes.InvalidArgument:
+		return "INVALID_ARGUMENT"
+	case codes.DeadlineExceeded:
+		return "DEADLINE_EXCEEDED"
+	case codes.NotFound:
+		return "NOT_FOUND"
+	case codes.AlreadyExists:
+		return "ALREADY_EXISTS"
+	case codes.PermissionDenied:
+		return "PERMISSION_DENIED"
+	case codes.ResourceExhausted:
+		return "RESOURCE_EXHAUSTED"
+	case codes.FailedPrecondition:
+		return "FAILED_PRECONDITION"
+	case codes.OutOfRange:
+		return "OUT_OF_RANGE"
+	case codes.DataLoss:
+		return "DATA_LOSS"
+	case codes.Unauthenticated:
+		return "UNAUTHENTICATED"
+	}
+	return code.String()
+}
+
+// httpStatus maps a gRPC code to the HTTP status of the same meaning.
+func httpStatus(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return 499
+	case codes.InvalidArgument, codes.OutOfRange, codes.FailedPrecondition:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusMethodNotAllowed
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(httpStatus(status.Code(err)))
+	json.NewEncoder(w).Encode(errorOf(err))
+}
+
+// wsStream adapts a WebSocket to a grpc.ServerStream of
+// StreamingRecognize.
+type wsStream struct {
+	ctx        context.Context
+	ws         *websocket.Conn
+	configured bool
+}
+
+// frame is a WebSocket message along with its type.
+type frame struct {
+	text bool
+	data []byte
+}
+
+var frameCodec = websocket.Codec{
+	Unmarshal: func(data []byte, payloadType byte, v interface{}) error {
+		f := v.(*frame)
+		f.text = payloadType == websocket.TextFrame
+		f.data = data
+		return nil
+	},
+}
+
+// control is a text message following the config.
+type control struct {
+	EOF bool `json:"eof"`
+}
+
+func (s *wsStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *wsStream) SetHeader(metadata.MD) error  { return nil }
+func (s *wsStream) SendHeader(metadata.MD) error { return nil }
+func (s *wsStream) SetTrailer(metadata.MD)       {}
+
+func (s *wsStream) SendMsg(m interface{}) error {
+	data, err := protojson.Marshal(m.(proto.Message))
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to encode response: %v", err)
+	}
+	return websocket.Message.Send(s.ws, string(data))
+}
+
+func (s *wsStream) RecvMsg(m interface{}) error {
+	req := m.(*server.StreamingRecognitionRequest)
+	var f frame
+	if err := frameCodec.Receive(s.ws, &f); err != nil {
+		if errors.Is(err, io.EOF) {
+			return io.EOF
+		}
+		return status.Errorf(codes.Canceled, "connection closed: %v", err)
+	}
+
+	if !s.configured {
+		if !f.text {
+			return status.Error(codes.InvalidArgument, "first message must contain the recognition config")
+		}
+		config := &server.RecognitionConfig{}
+		if err := protojson.Unmarshal(f.data, config); err != nil {
+			return status.Errorf(codes.InvalidArgument, "invalid config: %v", err)
+		}
+		s.configured = true
+		req.StreamingRequest = &server.StreamingRecognitionRequest_Config{Config: config}
+		return nil
+	}
+
+	if f.text {
+		var c control
+		if err := json.Unmarshal(f.data, &c); err != nil {
+			return status.Errorf(codes.InvalidArgument, "invalid message: %v", err)
+		}
+		if c.EOF {
+			return io.EOF
+		}
+		return status.Errorf(codes.InvalidArgument, "unexpected message %s", f.data)
+	}
+	req.StreamingRequest = &server.StreamingRecognitionRequest_AudioContent{AudioContent: f.data}
+	return nil
+}
+
+// recognizeStream is the typed stream of StreamingRecognize.
+type recognizeStream struct {
+	grpc.ServerStream
+}
+
+func (s *recognizeStream) Send(m *server.StreamingRecognitionResponse) error {
+	return s.ServerStream.SendMsg(m)
+}
+
+func (s *recognizeStream) Recv() (*server.StreamingRecognitionRequest, error) {
+	m := &server.StreamingRecognitionRequest{}
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// chainUnary runs the interceptors in order around the handler.
+func chainUnary(interceptors []grpc.UnaryServerInterceptor, handler grpc.UnaryHandler) func(context.Context, interface{}, *grpc.UnaryServerInfo) (interface{}, error) {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo) (interface{}, error) {
+		next := handler
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, inner := interceptors[i], next
+			next = func(ctx context.Context, req interface{}) (interface{}, error) {
+				return interceptor(ctx, req, info, inner)
+			}
+		}
+		return next(ctx, req)
+	}
+}
+
+// chainStream runs the interceptors in order around the handler.
+func chainStream(interceptors []grpc.StreamServerInterceptor, handler grpc.StreamHandler) func(interface{}, grpc.ServerStream, *grpc.StreamServerInfo) error {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo) error {
+		next := handler
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, inner := interceptors[i], next
+			next = func(srv interface{}, stream grpc.ServerStream) error {
+				return interceptor(srv, stream, info, inner)
+			}
+		}
+		return next(srv, stream)
+	}
+}
@@ -0,0 +1,166 @@
+package gateway
+
+// Copyright (c) 2018 Bhojpur Consulting Private Limited, India. All rights reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bhojpur/speech/pkg/api/v1/server"
+	"github.com/bhojpur/speech/pkg/asr/fake"
+	"github.com/bhojpur/speech/pkg/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/websocket"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+func serve(t *testing.T, opts ...Option) *httptest.Server {
+	stt := server.NewServer(fake.NewEngine(16000,
+		fake.Utterance{Text: "turn on the light", Seconds: 1},
+		fake.Utterance{Text: "thank you", Seconds: 1},
+	))
+	t.Cleanup(stt.Close)
+	ts := httptest.NewServer(New(stt, opts...))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+// stream sends the config and the audio over a WebSocket and returns the
+// messages received back until the socket is closed.
+func stream(t *testing.T, ts *httptest.Server, query string, config string, audio []byte) []string {
+	ws, err := websocket.Dial(strings.Replace(ts.URL, "http", "ws", 1)+StreamPath+query, "", ts.URL)
+	require.NoError(t, err)
+	defer ws.Close()
+
+	require.NoError(t, websocket.Message.Send(ws, config))
+	for len(audio) > 0 {
+		n := 3200
+		if n > len(audio) {
+			n = len(audio)
+		}
+		require.NoError(t, websocket.Message.Send(ws, audio[:n]))
+		audio = audio[n:]
+	}
+	websocket.Message.Send(ws, `{"eof": true}`)
+
+	var messages []string
+	for {
+		var msg string
+		err := websocket.Message.Receive(ws, &msg)
+		if err == io.EOF {
+			return messages
+		}
+		require.NoError(t, err)
+		messages = append(messages, msg)
+	}
+}
+
+func TestStream(t *testing.T) {
+	ts := serve(t)
+	messages := stream(t, ts, "", `{"specification": {"sampleRateHertz": 16000}}`, make([]byte, 2*16000*2))
+	require.Len(t, messages, 2)
+
+	var texts []string
+	for _, msg := range messages {
+		res := &server.StreamingRecognitionResponse{}
+		require.NoError(t, protojson.Unmarshal([]byte(msg), res))
+		require.Len(t, res.GetChunks(), 1)
+		assert.True(t, res.GetChunks()[0].GetFinal())
+		texts = append(texts, res.GetChunks()[0].GetAlternatives()[0].GetText())
+	}
+	assert.Equal(t, []string{"turn on the light", "thank you"}, texts)
+
+	messages = stream(t, ts, "", `{"specification": {"maxAlternatives": 100}}`, nil)
+	require.Len(t, messages, 1)
+	assert.Contains(t, messages[0], `"status":"INVALID_ARGUMENT"`)
+}
+
+func TestStreamAuthentication(t *testing.T) {
+	guard := auth.New([]auth.Key{{Name: "alice", Key: "secret"}})
+	ts := serve(t, WithStreamInterceptors(guard.StreamInterceptor()))
+
+	messages := stream(t, ts, "", `{}`, nil)
+	require.Len(t, messages, 1)
+	var msg errorMessage
+	require.NoError(t, json.Unmarshal([]byte(messages[0]), &msg))
+	assert.Equal(t, "UNAUTHENTICATED", msg.Error.Status)
+
+	messages = stream(t, ts, "?"+APIKeyParameter+"=secret", `{}`, make([]byte, 16000*2))
+	require.Len(t, messages, 1)
+	assert.Contains(t, messages[0], "turn on the light")
+}
+
+func TestRecognize(t *testing.T) {
+	guard := auth.New([]auth.Key{{Name: "alice", Key: "secret"}})
+	ts := serve(t, WithUnaryInterceptors(guard.UnaryInterceptor()))
+	post := func(url, contentType string, body []byte) (int, []byte) {
+		req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set("Authorization", "Bearer secret")
+		res, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		data, err := ioutil.ReadAll(res.Body)
+		require.NoError(t, err)
+		return res.StatusCode, data
+	}
+
+	code, body := post(ts.URL+RecognizePath, "application/octet-stream", make([]byte, 2*16000*2))
+	require.Equal(t, http.StatusOK, code, string(body))
+	res := &server.RecognizeResponse{}
+	require.NoError(t, protojson.Unmarshal(body, res))
+	require.Len(t, res.GetChunks(), 2)
+	assert.Equal(t, "thank you", res.GetChunks()[1].GetAlternatives()[0].GetText())
+
+	request := `{"config": {"specification": {"sampleRateHertz": 16000}}, "audioContent": "` + base64.StdEncoding.EncodeToString(make([]byte, 16000*2)) + `"}`
+	code, body = post(ts.URL+RecognizePath, "application/json", []byte(request))
+	require.Equal(t, http.StatusOK, code, string(body))
+	assert.Contains(t, string(body), "turn on the light")
+
+	code, _ = post(ts.URL+RecognizePath+`?config={"specification":{"maxAlternatives":100}}`, "audio/l16", nil)
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	res2, err := http.Post(ts.URL+RecognizePath, "audio/wav", bytes.NewReader(nil))
+	require.NoError(t, err)
+	res2.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, res2.StatusCode)
+
+	res2, err = http.Get(ts.URL + RecognizePath)
+	require.NoError(t, err)
+	res2.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, res2.StatusCode)
+}
+
+func TestEncodingOf(t *testing.T) {
+	assert.Equal(t, server.RecognitionSpec_WAV, encodingOf("audio/x-wav"))
+	assert.Equal(t, server.RecognitionSpec_MP3, encodingOf("audio/mpeg"))
+	assert.Equal(t, server.RecognitionSpec_MULAW, encodingOf("audio/basic"))
+	assert.Equal(t, server.RecognitionSpec_AUDIO_ENCODING_UNSPECIFIED, encodingOf("application/octet-stream"))
+}
@@ -0,0 +1,394 @@
+package server
+
+// Copyright (c) 2018 Bhojpur Consulting Private Limited, India. All rights reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/bhojpur/speech/pkg/auth"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// LongRunningRecognize queues the recognition and returns the pending
+// operation. The recognition does not depend on the caller's context, so it
+// keeps running after the client disconnects.
+func (s *SttServer) LongRunningRecognize(ctx context.Context, req *LongRunningRecognizeRequest) (*Operation, error) {
+	spec, err := specOf(req.GetConfig())
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.validateSpec(spec); err != nil {
+		return nil, err
+	}
+	if !s.probing(spec) {
+		if _, err := s.modelName(spec); err != nil {
+			return nil, err
+		}
+	}
+	if webhook := req.GetWebhookUrl(); webhook != "" {
+		u, err := url.Parse(webhook)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid webhook url %q", webhook)
+		}
+	}
+
+	return s.operations.submit(ctx, spec, req.GetAudioContent(), req.GetWebhookUrl())
+}
+
+// GetOperation returns the current state of an operation. The operations of
+// the other accounts are not found.
+func (s *SttServer) GetOperation(ctx context.Context, req *GetOperationRequest) (*Operation, error) {
+	return s.operations.get(owner(ctx), req.GetId())
+}
+
+// CancelOperation stops a queued or running operation. Cancelling a finished
+// operation has no effect.
+func (s *SttServer) CancelOperation(ctx context.Context, req *CancelOperationRequest) (*Operation, error) {
+	return s.operations.cancel(owner(ctx), req.GetId())
+}
+
+// owner returns the account of a call, empty when the server does not
+// authenticate the calls.
+func owner(ctx context.Context) string {
+	if account, ok := auth.FromContext(ctx); ok {
+		return account.Name()
+	}
+	return ""
+}
+
+// job is a long-running recognition.
+type job struct {
+	owner   string // account which submitted the job
+	spec    *RecognitionSpec
+	audio   []byte
+	webhook string
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// guarded by operations.mu
+	op       *Operation
+	running  bool
+	finished time.Time
+}
+
+// operations runs the long-running recognitions on a bounded pool of
+// workers and keeps their state until it expires. The workers are started
+// by the first submission.
+type operations struct {
+	server  *SttServer
+	workers int
+	queue   chan *job
+	start   sync.Once
+	wg      sync.WaitGroup
+	// webhooks being called
+	notifying sync.WaitGroup
+
+	mu     sync.Mutex
+	jobs   map[string]*job
+	closed bool
+}
+
+func newOperations(server *SttServer, workers, queueSize int) *operations {
+	if workers < 1 {
+		workers = 1
+	}
+	if queueSize < 0 {
+		queueSize = 0
+	}
+	return &operations{
+		server:  server,
+		workers: workers,
+		queue:   make(chan *job, queueSize),
+		jobs:    map[string]*job{},
+	}
+}
+
+func (o *operations) submit(parent context.Context, spec *RecognitionSpec, audio []byte, webhook string) (*Operation, error) {
+	id, err := newOperationID()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create operation id: %v", err)
+	}
+
+	// the recognition outlives the call but is charged to its caller
+	now := timestamppb.Now()
+	ctx, cancel := context.WithCancel(auth.Detach(parent))
+	j := &job{
+		owner:   owner(parent),
+		spec:    spec,
+		audio:   audio,
+		webhook: webhook,
+		ctx:     ctx,
+		cancel:  cancel,
+		op: &Operation{
+			Id:             id,
+			CreateTime:     now,
+			LastUpdateTime: now,
+		},
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.closed {
+		cancel()
+		return nil, status.Error(codes.Unavailable, "server is shutting down")
+	}
+	o.start.Do(func() {
+		o.wg.Add(o.workers)
+		for i := 0; i < o.workers; i++ {
+			go o.work()
+		}
+	})
+	o.expire()
+	select {
+	case o.queue <- j:
+	default:
+		cancel()
+		return nil, status.Error(codes.ResourceExhausted, "too many pending recognitions")
+	}
+	o.jobs[id] = j
+	return proto.Clone(j.op).(*Operation), nil
+}
+
+func (o *operations) get(owner, id string) (*Operation, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.expire()
+	j, err := o.lookup(owner, id)
+	if err != nil {
+		return nil, err
+	}
+	return proto.Clone(j.op).(*Operation), nil
+}
+
+func (o *operations) cancel(owner, id string) (*Operation, error) {
+	o.mu.Lock()
+	j, err := o.lookup(owner, id)
+	if err != nil {
+		o.mu.Unlock()
+		return nil, err
+	}
+	j.cancel()
+	pending := !j.running
+	o.mu.Unlock()
+
+	// A queued job is finished right away, a running one finishes as soon
+	// as its worker notices the cancellation.
+	if pending {
+		o.finish(j, nil, status.Error(codes.Canceled, "operation cancelled"))
+	}
+	return o.get(owner, id)
+}
+
+// lookup returns the job of the owner with the id. It must be called with
+// o.mu held.
+func (o *operations) lookup(owner, id string) (*job, error) {
+	j, ok := o.jobs[id]
+	// other accounts are not told the operation exists
+	if !ok || j.owner != owner {
+		return nil, status.Errorf(codes.NotFound, "operation %q not found", id)
+	}
+	return j, nil
+}
+
+func (o *operations) work() {
+	defer o.wg.Done()
+	for j := range o.queue {
+		o.mu.Lock()
+		done := j.op.Done
+		j.running = true
+		o.mu.Unlock()
+		if done {
+			continue
+		}
+
+		res, err := o.run(j)
+		o.finish(j, res, err)
+	}
+}
+
+// run decodes and recognizes the audio of a job.
+func (o *operations) run(j *job) (*RecognizeResponse, error) {
+	spec := j.spec
+	sampleRate, err := o.server.validateSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+	probed := o.server.probing(spec)
+	var confidence float64
+	if probed {
+		spec, confidence, err = o.server.identifyLanguage(spec, sampleRate, j.audio)
+		if err != nil {
+			return nil, err
+		}
+	}
+	engine, release, err := o.server.acquire(spec)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	pcm, err := o.server.decode(j.ctx, spec, sampleRate, engine, j.audio)
+	if err != nil {
+		return nil, err
+	}
+	total := len(pcm)
+	res, err := o.server.recognize(j.ctx, spec, engine, pcm, func(done int) {
+		o.progress(j, done, total)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if probed {
+		res.LanguageCode = spec.GetLanguageCode()
+		res.LanguageConfidence = float32(confidence)
+	}
+	return res, nil
+}
+
+func (o *operations) progress(j *job, done, total int) {
+	percent := int32(100)
+	if total > 0 {
+		percent = int32(int64(done) * 100 / int64(total))
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if percent != j.op.ProgressPercent {
+		j.op.ProgressPercent = percent
+		j.op.LastUpdateTime = timestamppb.Now()
+	}
+}
+
+// finish records the outcome of a job and calls its webhook in the
+// background, so that a slow webhook holds neither a worker nor the caller
+// cancelling the job. It does nothing when the job is already finished.
+func (o *operations) finish(j *job, res *RecognizeResponse, err error) {
+	o.mu.Lock()
+	if j.op.Done {
+		o.mu.Unlock()
+		return
+	}
+	j.op.Done = true
+	j.op.LastUpdateTime = timestamppb.Now()
+	if err != nil {
+		st := status.Convert(err)
+		j.op.Result = &Operation_Error{Error: &OperationError{
+			Code:    int32(st.Code()),
+			Message: st.Message(),
+		}}
+	} else {
+		j.op.ProgressPercent = 100
+		j.op.Result = &Operation_Response{Response: res}
+	}
+	j.finished = time.Now()
+	j.audio = nil
+	j.cancel()
+	if j.webhook != "" {
+		op := proto.Clone(j.op).(*Operation)
+		o.notifying.Add(1)
+		go func() {
+			defer o.notifying.Done()
+			o.notify(j.webhook, op)
+		}()
+	}
+	o.mu.Unlock()
+}
+
+// notify posts the finished operation to its webhook.
+func (o *operations) notify(webhook string, op *Operation) {
+	body, err := protojson.Marshal(op)
+	if err != nil {
+		log.Printf("operation %s: failed to encode webhook body: %v", op.GetId(), err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), o.server.webhookTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook, bytes.NewReader(body))
+	if err != nil {
+		log.Printf("operation %s: failed to create webhook request: %v", op.GetId(), err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := o.server.webhookClient.Do(req)
+	if err != nil {
+		log.Printf("operation %s: webhook failed: %v", op.GetId(), err)
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		log.Printf("operation %s: webhook returned %s", op.GetId(), resp.Status)
+	}
+}
+
+// expire forgets the operations finished longer than the TTL ago. It must
+// be called with o.mu held.
+func (o *operations) expire() {
+	if o.server.operationTTL <= 0 {
+		return
+	}
+	deadline := time.Now().Add(-o.server.operationTTL)
+	for id, j := range o.jobs {
+		if j.op.Done && j.finished.Before(deadline) {
+			delete(o.jobs, id)
+		}
+	}
+}
+
+// close cancels all the jobs and waits for the workers to exit and the
+// webhooks to be called.
+func (o *operations) close() {
+	o.mu.Lock()
+	if o.closed {
+		o.mu.Unlock()
+		return
+	}
+	o.closed = true
+	for _, j := range o.jobs {
+		j.cancel()
+	}
+	close(o.queue)
+	o.mu.Unlock()
+	o.wg.Wait()
+	o.notifying.Wait()
+}
+
+func newOperationID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
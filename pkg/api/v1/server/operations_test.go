@@ -0,0 +1,242 @@
+package server
+
+// Copyright (c) 2018 Bhojpur Consulting Private Limited, India. All rights reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bhojpur/speech/pkg/asr"
+	"github.com/bhojpur/speech/pkg/asr/fake"
+	"github.com/bhojpur/speech/pkg/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// blockingEngine holds every recognizer in AcceptWaveform until released.
+type blockingEngine struct {
+	*fake.Engine
+	release chan struct{}
+}
+
+func (e *blockingEngine) NewRecognizer(config asr.Config) (asr.Recognizer, error) {
+	rec, err := e.Engine.NewRecognizer(config)
+	if err != nil {
+		return nil, err
+	}
+	return &blockingRecognizer{Recognizer: rec, release: e.release}, nil
+}
+
+type blockingRecognizer struct {
+	asr.Recognizer
+	release chan struct{}
+}
+
+func (r *blockingRecognizer) AcceptWaveform(pcm []byte) (bool, error) {
+	<-r.release
+	return r.Recognizer.AcceptWaveform(pcm)
+}
+
+func wait(t *testing.T, client SttServiceClient, id string) *Operation {
+	for i := 0; i < 500; i++ {
+		op, err := client.GetOperation(context.Background(), &GetOperationRequest{Id: id})
+		require.NoError(t, err)
+		if op.GetDone() {
+			return op
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("operation %s did not finish", id)
+	return nil
+}
+
+func TestLongRunningRecognize(t *testing.T) {
+	received := make(chan *Operation, 1)
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		op := &Operation{}
+		assert.NoError(t, protojson.Unmarshal(body, op))
+		received <- op
+	}))
+	defer webhook.Close()
+
+	_, loopback, _ := net.ParseCIDR("127.0.0.0/8")
+	client := dialServer(t, NewServer(script(), WithWebhookNetworks(loopback)))
+	op, err := client.LongRunningRecognize(context.Background(), &LongRunningRecognizeRequest{
+		Config:       &RecognitionConfig{Specification: &RecognitionSpec{}},
+		AudioContent: make([]byte, 2*16000*2),
+		WebhookUrl:   webhook.URL,
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, op.GetId())
+
+	op = wait(t, client, op.GetId())
+	assert.Equal(t, int32(100), op.GetProgressPercent())
+	require.Len(t, op.GetResponse().GetChunks(), 2)
+	assert.Equal(t, "thank you", op.GetResponse().GetChunks()[1].GetAlternatives()[0].GetText())
+
+	select {
+	case notified := <-received:
+		assert.Equal(t, op.GetId(), notified.GetId())
+		assert.True(t, notified.GetDone())
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook was not called")
+	}
+
+	_, err = client.GetOperation(context.Background(), &GetOperationRequest{Id: "missing"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = client.LongRunningRecognize(context.Background(), &LongRunningRecognizeRequest{
+		Config:     &RecognitionConfig{},
+		WebhookUrl: "ftp://example.com",
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestSlowWebhook(t *testing.T) {
+	release := make(chan struct{})
+	called := make(chan struct{}, 2)
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called <- struct{}{}
+		<-release
+	}))
+	defer webhook.Close()
+	defer close(release)
+
+	_, loopback, _ := net.ParseCIDR("127.0.0.0/8")
+	client := dialServer(t, NewServer(script(), WithWorkers(1), WithWebhookNetworks(loopback)))
+	req := &LongRunningRecognizeRequest{
+		Config:       &RecognitionConfig{Specification: &RecognitionSpec{}},
+		AudioContent: make([]byte, 2*16000*2),
+		WebhookUrl:   webhook.URL,
+	}
+	first, err := client.LongRunningRecognize(context.Background(), req)
+	require.NoError(t, err)
+	wait(t, client, first.GetId())
+	select {
+	case <-called:
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook was not called")
+	}
+
+	// the only worker runs the next job while the webhook hangs
+	second, err := client.LongRunningRecognize(context.Background(), req)
+	require.NoError(t, err)
+	assert.True(t, wait(t, client, second.GetId()).GetDone())
+}
+
+func TestCancelOperation(t *testing.T) {
+	engine := &blockingEngine{Engine: script(), release: make(chan struct{})}
+	server := NewServer(engine, WithWorkers(1), WithQueueSize(1))
+	defer server.Close()
+
+	request := &LongRunningRecognizeRequest{
+		Config:       &RecognitionConfig{},
+		AudioContent: make([]byte, 2*chunkSize),
+	}
+	running, err := server.LongRunningRecognize(context.Background(), request)
+	require.NoError(t, err)
+
+	// wait for the worker to pick the first job, so that the second one
+	// stays in the queue and the third one does not fit
+	require.Eventually(t, func() bool { return len(server.operations.queue) == 0 }, 5*time.Second, time.Millisecond)
+	queued, err := server.LongRunningRecognize(context.Background(), request)
+	require.NoError(t, err)
+	_, err = server.LongRunningRecognize(context.Background(), request)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	op, err := server.CancelOperation(context.Background(), &CancelOperationRequest{Id: queued.GetId()})
+	require.NoError(t, err)
+	assert.True(t, op.GetDone())
+	assert.Equal(t, int32(codes.Canceled), op.GetError().GetCode())
+
+	_, err = server.CancelOperation(context.Background(), &CancelOperationRequest{Id: running.GetId()})
+	require.NoError(t, err)
+	close(engine.release)
+
+	require.Eventually(t, func() bool {
+		op, err := server.GetOperation(context.Background(), &GetOperationRequest{Id: running.GetId()})
+		return err == nil && op.GetDone()
+	}, 5*time.Second, time.Millisecond)
+	op, err = server.GetOperation(context.Background(), &GetOperationRequest{Id: running.GetId()})
+	require.NoError(t, err)
+	assert.Equal(t, int32(codes.Canceled), op.GetError().GetCode())
+}
+
+func TestOperationOwner(t *testing.T) {
+	guard := auth.New([]auth.Key{{Name: "alice", Key: "alice-key"}, {Name: "bob", Key: "bob-key"}})
+	client := dialServer(t, NewServer(script()),
+		grpc.ChainUnaryInterceptor(guard.UnaryInterceptor()),
+		grpc.ChainStreamInterceptor(guard.StreamInterceptor()),
+	)
+	alice := metadata.AppendToOutgoingContext(context.Background(), auth.APIKeyHeader, "alice-key")
+	bob := metadata.AppendToOutgoingContext(context.Background(), auth.APIKeyHeader, "bob-key")
+
+	op, err := client.LongRunningRecognize(alice, &LongRunningRecognizeRequest{
+		Config:       &RecognitionConfig{Specification: &RecognitionSpec{}},
+		AudioContent: make([]byte, 2*16000*2),
+	})
+	require.NoError(t, err)
+
+	_, err = client.GetOperation(bob, &GetOperationRequest{Id: op.GetId()})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = client.CancelOperation(bob, &CancelOperationRequest{Id: op.GetId()})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	op, err = client.GetOperation(alice, &GetOperationRequest{Id: op.GetId()})
+	require.NoError(t, err)
+	assert.Empty(t, op.GetError())
+}
+
+func TestWebhookAddresses(t *testing.T) {
+	called := make(chan struct{}, 1)
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called <- struct{}{}
+	}))
+	defer webhook.Close()
+
+	// the loopback webhook is refused by default
+	client := newWebhookClient(nil)
+	_, err := client.Post(webhook.URL, "application/json", nil)
+	assert.Error(t, err)
+	select {
+	case <-called:
+		t.Fatal("webhook was called")
+	default:
+	}
+
+	for _, address := range []string{"127.0.0.1", "10.1.2.3", "192.168.1.1", "169.254.169.254", "::1", "fe80::1", "100.64.0.1", "0.0.0.0"} {
+		assert.False(t, publicAddress(net.ParseIP(address), nil), address)
+	}
+	assert.True(t, publicAddress(net.ParseIP("93.184.216.34"), nil))
+	_, private, _ := net.ParseCIDR("10.0.0.0/8")
+	assert.True(t, publicAddress(net.ParseIP("10.1.2.3"), []*net.IPNet{private}))
+}
@@ -0,0 +1,347 @@
+package server
+
+// Copyright (c) 2018 Bhojpur Consulting Private Limited, India. All rights reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"io"
+	"strings"
+
+	"github.com/bhojpur/speech/pkg/asr"
+	"github.com/bhojpur/speech/pkg/audio"
+	"github.com/bhojpur/speech/pkg/language"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// autoLanguage is the language_code asking for the language identification.
+const autoLanguage = "auto"
+
+// languageProbe identifies the language of a recording by recognizing its
+// beginning with a model of every candidate language. A candidate scores the
+// mean confidence of its recognizer, averaged with the confidence of the
+// language detector that its transcript is written in its language, since a
+// model hearing a foreign language tends to produce a confident transcript of
+// words that do not belong together.
+type languageProbe struct {
+	seconds    float64
+	candidates []*candidate
+	detector   language.LanguageDetector
+	content    [][]byte // audio written so far
+	closed     bool
+
+	winner     *candidate
+	confidence float64
+}
+
+// candidate is the recognition of the probe audio in one language.
+type candidate struct {
+	language string
+	model    string
+	engine   asr.Engine
+	release  func()
+	dec      *audio.Decoder
+	rec      asr.Recognizer
+	samples  int64
+	results  []*asr.Result
+}
+
+// probing reports whether the language of the specification is to be
+// identified.
+func (s *SttServer) probing(spec *RecognitionSpec) bool {
+	if s.registry == nil || s.probeDuration <= 0 || spec.GetModel() != "" {
+		return false
+	}
+	code := spec.GetLanguageCode()
+	return code == "" || strings.EqualFold(code, autoLanguage)
+}
+
+// newLanguageProbe loads a model of every candidate language and creates
+// their recognizers. The sample rate is the one returned by validateSpec.
+func (s *SttServer) newLanguageProbe(spec *RecognitionSpec, sampleRate int64) (*languageProbe, error) {
+	p := &languageProbe{seconds: s.probeDuration.Seconds()}
+	seen := map[string]bool{}
+	var detectable []language.Language
+	for _, code := range s.probeLanguages() {
+		info, err := s.registry.Lookup("", code)
+		if err != nil || seen[info.Name] {
+			continue
+		}
+		seen[info.Name] = true
+
+		engine, release, err := s.registry.Acquire(info.Name)
+		if err != nil {
+			p.close()
+			return nil, status.Errorf(codes.Unavailable, "%v", err)
+		}
+		c := &candidate{language: info.Language, model: info.Name, engine: engine, release: release}
+		p.candidates = append(p.candidates, c)
+
+		c.dec, err = s.newDecoder(spec, sampleRate, engine)
+		if err != nil {
+			p.close()
+			return nil, err
+		}
+		// the engines only rate the words they are asked to report
+		c.rec, err = engine.NewRecognizer(asr.Config{SampleRate: engine.SampleRate(), Words: true})
+		if err != nil {
+			p.close()
+			return nil, status.Errorf(codes.Internal, "failed to create recognizer: %v", err)
+		}
+		if lang, ok := detectorLanguage(c.language); ok {
+			detectable = append(detectable, lang)
+		}
+	}
+	if len(p.candidates) == 0 {
+		return nil, status.Error(codes.FailedPrecondition, "no model to identify the language")
+	}
+	if len(unique(detectable)) > 1 {
+		p.detector = language.NewLanguageDetectorBuilder().FromLanguages(detectable...).Build()
+	}
+	return p, nil
+}
+
+// probeLanguages returns the candidate languages, by default the languages
+// of all the models of the registry.
+func (s *SttServer) probeLanguages() []string {
+	if len(s.probeCandidates) > 0 {
+		return s.probeCandidates
+	}
+	var languages []string
+	for _, info := range s.registry.List() {
+		if info.Language != "" {
+			languages = append(languages, info.Language)
+		}
+	}
+	return languages
+}
+
+// write feeds audio to all the candidates and reports whether the probe
+// has heard enough of it. The audio is kept to be replayed to the winner.
+func (p *languageProbe) write(content []byte) (bool, error) {
+	p.content = append(p.content, content)
+	done := true
+	for _, c := range p.candidates {
+		pcm, err := c.dec.Write(content)
+		if err != nil {
+			return false, decodeError(err)
+		}
+		if err := c.accept(pcm); err != nil {
+			return false, err
+		}
+		if float64(c.samples) < p.seconds*c.engine.SampleRate() {
+			done = false
+		}
+	}
+	return done || len(p.candidates) == 1, nil
+}
+
+// finish flushes the candidates, releases their models and picks the
+// winning one.
+func (p *languageProbe) finish() error {
+	defer p.close()
+
+	p.confidence = -1
+	for _, c := range p.candidates {
+		pcm, err := c.dec.Close()
+		if err != nil {
+			return decodeError(err)
+		}
+		if err := c.accept(pcm); err != nil {
+			return err
+		}
+		res, err := c.rec.FinalResult()
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to get final result: %v", err)
+		}
+		c.results = append(c.results, res)
+
+		if score := p.score(c); score > p.confidence {
+			p.winner, p.confidence = c, score
+		}
+	}
+	return nil
+}
+
+// spec returns a copy of the specification selecting the model of the
+// winner.
+func (p *languageProbe) spec(spec *RecognitionSpec) *RecognitionSpec {
+	spec = proto.Clone(spec).(*RecognitionSpec)
+	spec.Model = p.winner.model
+	spec.LanguageCode = p.winner.language
+	return spec
+}
+
+// close releases the recognizers and models of the candidates.
+func (p *languageProbe) close() {
+	if p.closed {
+		return
+	}
+	p.closed = true
+	for _, c := range p.candidates {
+		if c.rec != nil {
+			c.rec.Close()
+		}
+		c.release()
+	}
+}
+
+// score rates how likely the audio is in the language of the candidate.
+// Languages unknown to the detector are rated on the recognizer confidence
+// only.
+func (p *languageProbe) score(c *candidate) float64 {
+	var texts []string
+	var confidence float64
+	var words int
+	for _, res := range c.results {
+		if res.Empty() {
+			continue
+		}
+		alt := res.Alternatives[0]
+		n := len(strings.Fields(alt.Text))
+		texts = append(texts, alt.Text)
+		confidence += alt.Confidence * float64(n)
+		words += n
+	}
+	if words == 0 {
+		return 0
+	}
+	confidence /= float64(words)
+
+	lang, ok := detectorLanguage(c.language)
+	if p.detector == nil || !ok {
+		return confidence
+	}
+	var detected float64
+	for _, value := range p.detector.ComputeLanguageConfidenceValues(strings.Join(texts, " ")) {
+		if value.Language() == lang {
+			detected = value.Value()
+		}
+	}
+	return (confidence + detected) / 2
+}
+
+// accept feeds decoded audio to the recognizer of the candidate and keeps
+// the results of the utterances it ends.
+func (c *candidate) accept(pcm []byte) error {
+	if len(pcm) == 0 {
+		return nil
+	}
+	c.samples += int64(len(pcm) / 2)
+	endpoint, err := c.rec.AcceptWaveform(pcm)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to recognize audio: %v", err)
+	}
+	if !endpoint {
+		return nil
+	}
+	res, err := c.rec.Result()
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to get result: %v", err)
+	}
+	c.results = append(c.results, res)
+	return nil
+}
+
+// identifyLanguage probes the beginning of a whole recording and returns the
+// specification selecting the model of the identified language.
+func (s *SttServer) identifyLanguage(spec *RecognitionSpec, sampleRate int64, content []byte) (*RecognitionSpec, float64, error) {
+	p, err := s.newLanguageProbe(spec, sampleRate)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer p.close()
+	for offset := 0; offset < len(content); offset += chunkSize {
+		end := offset + chunkSize
+		if end > len(content) {
+			end = len(content)
+		}
+		done, err := p.write(content[offset:end])
+		if err != nil {
+			return nil, 0, err
+		}
+		if done {
+			break
+		}
+	}
+	if err := p.finish(); err != nil {
+		return nil, 0, err
+	}
+	return p.spec(spec), p.confidence, nil
+}
+
+// probeStream probes the beginning of a stream. The probe keeps the audio
+// received, and the stream has ended when eof is set.
+func (s *SttServer) probeStream(stream SttService_StreamingRecognizeServer, spec *RecognitionSpec, sampleRate int64) (p *languageProbe, eof bool, err error) {
+	p, err = s.newLanguageProbe(spec, sampleRate)
+	if err != nil {
+		return nil, false, err
+	}
+	defer p.close()
+	for {
+		content, err := recvAudio(stream)
+		if err == io.EOF {
+			eof = true
+			break
+		}
+		if err != nil {
+			return nil, false, err
+		}
+		if len(content) == 0 {
+			continue
+		}
+		done, err := p.write(content)
+		if err != nil {
+			return nil, false, err
+		}
+		if done {
+			break
+		}
+	}
+	if err := p.finish(); err != nil {
+		return nil, false, err
+	}
+	return p, eof, nil
+}
+
+// detectorLanguage maps the primary subtag of a BCP-47 code to a language
+// of the language detector.
+func detectorLanguage(code string) (language.Language, bool) {
+	primary := strings.ToLower(strings.SplitN(strings.ReplaceAll(code, "_", "-"), "-", 2)[0])
+	for _, lang := range language.AllLanguages() {
+		if strings.ToLower(lang.IsoCode639_1().String()) == primary {
+			return lang, true
+		}
+	}
+	return language.Unknown, false
+}
+
+func unique(languages []language.Language) []language.Language {
+	seen := map[language.Language]bool{}
+	var result []language.Language
+	for _, lang := range languages {
+		if !seen[lang] {
+			seen[lang] = true
+			result = append(result, lang)
+		}
+	}
+	return result
+}
@@ -0,0 +1,218 @@
+package server
+
+// Copyright (c) 2018 Bhojpur Consulting Private Limited, India. All rights reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bhojpur/speech/pkg/asr"
+	"github.com/bhojpur/speech/pkg/asr/fake"
+	"github.com/bhojpur/speech/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	// both models hear English words, the German one with a higher
+	// confidence that only the language detector can tell apart
+	asr.Register("probe-test", func(path string) (asr.Engine, error) {
+		info, err := models.ReadInfo(path)
+		if err != nil {
+			return nil, err
+		}
+		confidence := 0.7
+		if info.Language == "de" {
+			confidence = 0.8
+		}
+		return fake.NewEngine(info.SampleRate,
+			fake.Utterance{Text: "turn on the light", Seconds: 1, Confidence: confidence},
+			fake.Utterance{Text: "thank you", Seconds: 1, Confidence: confidence},
+		), nil
+	})
+	// the Breton model, unknown to the language detector, hears with a
+	// higher confidence, reported like Vosk only along with the words
+	asr.Register("probe-words-test", func(path string) (asr.Engine, error) {
+		info, err := models.ReadInfo(path)
+		if err != nil {
+			return nil, err
+		}
+		confidence := 0.6
+		if info.Language == "br" {
+			confidence = 0.9
+		}
+		return wordsEngine{fake.NewEngine(info.SampleRate,
+			fake.Utterance{Text: "demat", Seconds: 1, Confidence: confidence},
+		)}, nil
+	})
+}
+
+// wordsEngine only reports the confidence of the recognizers enabling the
+// words.
+type wordsEngine struct {
+	asr.Engine
+}
+
+func (e wordsEngine) NewRecognizer(config asr.Config) (asr.Recognizer, error) {
+	rec, err := e.Engine.NewRecognizer(config)
+	if err != nil || config.Words {
+		return rec, err
+	}
+	return noConfidence{rec}, nil
+}
+
+type noConfidence struct {
+	asr.Recognizer
+}
+
+func (r noConfidence) Result() (*asr.Result, error) {
+	return r.strip(r.Recognizer.Result())
+}
+
+func (r noConfidence) PartialResult() (*asr.Result, error) {
+	return r.strip(r.Recognizer.PartialResult())
+}
+
+func (r noConfidence) FinalResult() (*asr.Result, error) {
+	return r.strip(r.Recognizer.FinalResult())
+}
+
+func (noConfidence) strip(res *asr.Result, err error) (*asr.Result, error) {
+	if res != nil {
+		for i := range res.Alternatives {
+			res.Alternatives[i].Confidence = 0
+		}
+	}
+	return res, err
+}
+
+func probeRegistry(t *testing.T) *models.Registry {
+	dir, err := ioutil.TempDir("", "models")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	for _, name := range []string{"vosk-model-small-en-us-0.15", "vosk-model-de-0.21"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, name), 0755))
+	}
+	registry, err := models.New(dir, models.WithEngine("probe-test"), models.WithDefault("vosk-model-de-0.21"))
+	require.NoError(t, err)
+	t.Cleanup(func() { registry.Close() })
+	return registry
+}
+
+func TestLanguageProbe(t *testing.T) {
+	registry := probeRegistry(t)
+	server := NewServer(nil, WithRegistry(registry), WithLanguageProbe(time.Second))
+	defer server.Close()
+	client := dialServer(t, server)
+
+	stream, err := client.StreamingRecognize(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, stream.Send(&StreamingRecognitionRequest{
+		StreamingRequest: &StreamingRecognitionRequest_Config{
+			Config: &RecognitionConfig{Specification: &RecognitionSpec{LanguageCode: "auto"}},
+		},
+	}))
+	audio := make([]byte, 2*16000*2)
+	for len(audio) > 0 {
+		require.NoError(t, stream.Send(&StreamingRecognitionRequest{
+			StreamingRequest: &StreamingRecognitionRequest_AudioContent{AudioContent: audio[:3200]},
+		}))
+		audio = audio[3200:]
+	}
+	require.NoError(t, stream.CloseSend())
+
+	var responses []*StreamingRecognitionResponse
+	for {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		responses = append(responses, res)
+	}
+	require.Len(t, responses, 3)
+	assert.Equal(t, "en-us", responses[0].GetLanguageCode())
+	assert.InDelta(t, 0.85, responses[0].GetLanguageConfidence(), 1e-6)
+	assert.Empty(t, responses[0].GetChunks())
+	// the probed second is replayed to the winning model
+	assert.Equal(t, "turn on the light", responses[1].GetChunks()[0].GetAlternatives()[0].GetText())
+	assert.Equal(t, "thank you", responses[2].GetChunks()[0].GetAlternatives()[0].GetText())
+	assert.Empty(t, responses[2].GetLanguageCode())
+
+	recognized, err := server.Recognize(context.Background(), &RecognizeRequest{
+		Config:       &RecognitionConfig{Specification: &RecognitionSpec{}},
+		AudioContent: make([]byte, 2*16000*2),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "en-us", recognized.GetLanguageCode())
+	assert.Len(t, recognized.GetChunks(), 2)
+
+	// an explicit language is not probed
+	recognized, err = server.Recognize(context.Background(), &RecognizeRequest{
+		Config:       &RecognitionConfig{Specification: &RecognitionSpec{LanguageCode: "de"}},
+		AudioContent: make([]byte, 2*16000*2),
+	})
+	require.NoError(t, err)
+	assert.Empty(t, recognized.GetLanguageCode())
+	assert.Len(t, recognized.GetChunks(), 2)
+}
+
+func TestLanguageProbeDisabled(t *testing.T) {
+	server := NewServer(nil, WithRegistry(probeRegistry(t)))
+	defer server.Close()
+
+	// without the probe, "auto" stands for the default model
+	recognized, err := server.Recognize(context.Background(), &RecognizeRequest{
+		Config:       &RecognitionConfig{Specification: &RecognitionSpec{LanguageCode: "auto"}},
+		AudioContent: make([]byte, 2*16000*2),
+	})
+	require.NoError(t, err)
+	assert.Empty(t, recognized.GetLanguageCode())
+	assert.Len(t, recognized.GetChunks(), 2)
+}
+
+func TestLanguageProbeConfidence(t *testing.T) {
+	dir, err := ioutil.TempDir("", "models")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	for _, name := range []string{"vosk-model-small-en-us-0.15", "vosk-model-br-0.8"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, name), 0755))
+	}
+	registry, err := models.New(dir, models.WithEngine("probe-words-test"))
+	require.NoError(t, err)
+	defer registry.Close()
+	server := NewServer(nil, WithRegistry(registry), WithLanguageProbe(time.Second, "en-us", "br"))
+	defer server.Close()
+
+	// without a detector the recognizer confidence alone picks the winner
+	recognized, err := server.Recognize(context.Background(), &RecognizeRequest{
+		Config:       &RecognitionConfig{Specification: &RecognitionSpec{LanguageCode: "auto"}},
+		AudioContent: make([]byte, 2*16000*2),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "br", recognized.GetLanguageCode())
+	assert.InDelta(t, 0.9, recognized.GetLanguageConfidence(), 1e-6)
+}
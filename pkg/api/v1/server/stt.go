@@ -0,0 +1,992 @@
+package server
+
+// Copyright (c) 2018 Bhojpur Consulting Private Limited, India. All rights reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bhojpur/speech/pkg/asr"
+	"github.com/bhojpur/speech/pkg/audio"
+	"github.com/bhojpur/speech/pkg/auth"
+	"github.com/bhojpur/speech/pkg/itn"
+	"github.com/bhojpur/speech/pkg/metrics"
+	"github.com/bhojpur/speech/pkg/models"
+	"github.com/bhojpur/speech/pkg/service/moderation"
+	"github.com/bhojpur/speech/pkg/speaker"
+	"github.com/bhojpur/speech/pkg/vad"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+const (
+	ADDR string = "localhost"
+	PORT int    = 4001
+)
+
+// maxAlternatives is the upper bound accepted for RecognitionSpec.max_alternatives.
+const maxAlternatives = 30
+
+// maxPhrases is the upper bound of the phrases of a grammar.
+const maxPhrases = 1000
+
+// chunkSize is the size of the audio chunks fed to the recognizer by the
+// Recognize and LongRunningRecognize calls.
+const chunkSize = 8192
+
+// SttServer implements the SttService on top of a speech recognition engine.
+// The engine is shared by all the streams, every stream gets its own
+// recognizer.
+type SttServer struct {
+	engine     asr.Engine
+	registry   *models.Registry
+	speakers   *speaker.Database
+	moderation *moderation.MaskFilters
+
+	probeDuration   time.Duration
+	probeCandidates []string
+
+	vad        bool
+	vadOptions []vad.Option
+
+	maxRecognizeDuration time.Duration
+	workers              int
+	queueSize            int
+	operationTTL         time.Duration
+	webhookTimeout       time.Duration
+	webhookNetworks      []*net.IPNet
+	webhookClient        *http.Client
+
+	operations *operations
+}
+
+// Option configures an SttServer.
+type Option func(*SttServer)
+
+// WithRegistry resolves the model and language_code of the recognition
+// specifications to the models of the registry. Specifications giving
+// neither use the server's engine, or the registry's default model when the
+// server has no engine.
+func WithRegistry(registry *models.Registry) Option {
+	return func(s *SttServer) {
+		s.registry = registry
+	}
+}
+
+// WithSpeakers enables speaker identification and verification against the
+// speakers of the database, and the enrollment of new ones.
+func WithSpeakers(speakers *speaker.Database) Option {
+	return func(s *SttServer) {
+		s.speakers = speakers
+	}
+}
+
+// WithModeration sets the per-language word lists of the profanity filter.
+// The default word list of the moderation package is used otherwise.
+func WithModeration(filters *moderation.MaskFilters) Option {
+	return func(s *SttServer) {
+		s.moderation = filters
+	}
+}
+
+// WithLanguageProbe identifies the language of the recordings whose
+// language_code is empty or "auto". Their first seconds are recognized with a
+// model of every candidate language of the registry, and the recognition
+// continues with the most likely one. The candidates default to the languages
+// of all the models of the registry.
+func WithLanguageProbe(duration time.Duration, languages ...string) Option {
+	return func(s *SttServer) {
+		s.probeDuration = duration
+		s.probeCandidates = languages
+	}
+}
+
+// WithVoiceActivityDetection ends the utterances at the silences found by a
+// voice activity detector configured by the options, besides the endpoints
+// of the engine. It gives engines without endpoint detection, and noisy
+// streams, a reliable end of utterance.
+func WithVoiceActivityDetection(opts ...vad.Option) Option {
+	return func(s *SttServer) {
+		s.vad = true
+		s.vadOptions = opts
+	}
+}
+
+// WithMaxRecognizeDuration limits the length of the audio accepted by
+// Recognize. Longer recordings must use LongRunningRecognize.
+func WithMaxRecognizeDuration(d time.Duration) Option {
+	return func(s *SttServer) {
+		s.maxRecognizeDuration = d
+	}
+}
+
+// WithWorkers sets the number of long-running recognitions processed
+// concurrently.
+func WithWorkers(n int) Option {
+	return func(s *SttServer) {
+		s.workers = n
+	}
+}
+
+// WithQueueSize sets the number of long-running recognitions which may wait
+// for a worker. Requests beyond it are rejected with RESOURCE_EXHAUSTED.
+func WithQueueSize(n int) Option {
+	return func(s *SttServer) {
+		s.queueSize = n
+	}
+}
+
+// WithOperationTTL sets how long finished operations can be queried.
+func WithOperationTTL(d time.Duration) Option {
+	return func(s *SttServer) {
+		s.operationTTL = d
+	}
+}
+
+// WithWebhookTimeout sets the timeout of the completion webhook calls.
+func WithWebhookTimeout(d time.Duration) Option {
+	return func(s *SttServer) {
+		s.webhookTimeout = d
+	}
+}
+
+// WithWebhookNetworks allows the webhooks to reach the given networks
+// despite being loopback, link-local or private. Such addresses are refused
+// by default, since the webhook URLs are chosen by the callers.
+func WithWebhookNetworks(networks ...*net.IPNet) Option {
+	return func(s *SttServer) {
+		s.webhookNetworks = append(s.webhookNetworks, networks...)
+	}
+}
+
+// NewServer creates a speech-to-text server. Audio is decoded and resampled to
+// the engine's sample rate. LINEAR16_PCM streams which do not specify
+// sample_rate_hertz are expected at the engine's sample rate. The engine may
+// be nil when a model registry is given.
+func NewServer(engine asr.Engine, opts ...Option) *SttServer {
+	server := &SttServer{
+		engine:               engine,
+		maxRecognizeDuration: time.Minute,
+		workers:              2,
+		queueSize:            64,
+		operationTTL:         24 * time.Hour,
+		webhookTimeout:       10 * time.Second,
+		moderation:           moderation.NewMaskFilters(moderation.DefaultFilterMap),
+	}
+	for _, opt := range opts {
+		opt(server)
+	}
+	if server.moderation == nil {
+		server.moderation = moderation.NewMaskFilters(moderation.DefaultFilterMap)
+	}
+	server.webhookClient = newWebhookClient(server.webhookNetworks)
+	server.operations = newOperations(server, server.workers, server.queueSize)
+	return server
+}
+
+// Close cancels the long-running recognitions and waits for the workers to
+// stop.
+func (s *SttServer) Close() {
+	s.operations.close()
+}
+
+// StreamingRecognize expects a RecognitionConfig as the first message and
+// audio_content chunks in the configured encoding afterwards. Recognized text is sent back
+// as soon as the recognizer, or the voice activity detector when enabled,
+// detects the end of an utterance. Intermediate
+// hypotheses are sent only when partial_results is set.
+func (s *SttServer) StreamingRecognize(stream SttService_StreamingRecognizeServer) error {
+	req, err := stream.Recv()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	config := req.GetConfig()
+	if config == nil {
+		return status.Error(codes.InvalidArgument, "first message must contain the recognition config")
+	}
+	spec := config.GetSpecification()
+	if spec == nil {
+		spec = &RecognitionSpec{}
+	}
+
+	sampleRate, err := s.validateSpec(spec)
+	if err != nil {
+		return err
+	}
+
+	// the audio heard by the language probe is replayed to the winning model
+	first := &StreamingRecognitionResponse{}
+	var replay [][]byte
+	var eof bool
+	if s.probing(spec) {
+		p, end, err := s.probeStream(stream, spec, sampleRate)
+		if err != nil {
+			return err
+		}
+		spec, replay, eof = p.spec(spec), p.content, end
+		first.LanguageCode = spec.GetLanguageCode()
+		first.LanguageConfidence = float32(p.confidence)
+	}
+
+	engine, release, err := s.acquire(spec)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	dec, err := s.newDecoder(spec, sampleRate, engine)
+	if err != nil {
+		return err
+	}
+	rec, oov, err := s.newRecognizer(spec, engine)
+	if err != nil {
+		return err
+	}
+	defer rec.Close()
+	detector := s.newDetector(engine)
+	first.OutOfVocabularyWords = oov
+	if len(oov) > 0 || first.LanguageCode != "" {
+		if err := stream.Send(first); err != nil {
+			return err
+		}
+	}
+
+	// the real-time factor accounts for the time spent recognizing only,
+	// not for the time waiting for the client
+	model := s.modelLabel(spec, engine)
+	var processed int
+	var elapsed time.Duration
+	defer func() {
+		metrics.ObserveRecognition(model, float64(processed)/2/engine.SampleRate(), elapsed)
+	}()
+
+	// feed recognizes a piece of audio and reports whether the stream is
+	// over after a single utterance
+	var lastPartial string
+	feed := func(content []byte) (bool, error) {
+		start := time.Now()
+		defer func() { elapsed += time.Since(start) }()
+		pcm, err := dec.Write(content)
+		if err != nil {
+			return false, decodeError(err)
+		}
+		if len(pcm) == 0 {
+			return false, nil
+		}
+		if err := charge(stream.Context(), engine, pcm); err != nil {
+			return false, err
+		}
+		processed += len(pcm)
+
+		res, err := asr.Accept(rec, pcm, speechEnded(detector, pcm))
+		if err != nil {
+			return false, status.Errorf(codes.Internal, "failed to recognize audio: %v", err)
+		}
+		if res != nil {
+			lastPartial = ""
+			if err := send(stream, s.finalChunk(res, spec)); err != nil {
+				return false, err
+			}
+			metrics.Since(metrics.ResultLatency.WithLabelValues(metrics.Final), start)
+			return spec.GetSingleUtterance(), nil
+		}
+
+		if !spec.GetPartialResults() {
+			return false, nil
+		}
+		res, err = rec.PartialResult()
+		if err != nil {
+			return false, status.Errorf(codes.Internal, "failed to get partial result: %v", err)
+		}
+		text := res.Text()
+		if text == "" || text == lastPartial {
+			return false, nil
+		}
+		lastPartial = text
+		if err := send(stream, &SpeechRecognitionChunk{
+			Alternatives: []*SpeechRecognitionAlternative{{Text: s.maskText(spec, text)}},
+		}); err != nil {
+			return false, err
+		}
+		metrics.Since(metrics.ResultLatency.WithLabelValues(metrics.Partial), start)
+		return false, nil
+	}
+
+	for _, content := range replay {
+		stop, err := feed(content)
+		if err != nil || stop {
+			return err
+		}
+	}
+	for !eof {
+		content, err := recvAudio(stream)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if len(content) == 0 {
+			continue
+		}
+		stop, err := feed(content)
+		if err != nil || stop {
+			return err
+		}
+	}
+
+	start := time.Now()
+	pcm, err := dec.Close()
+	if err != nil {
+		return decodeError(err)
+	}
+	if len(pcm) > 0 {
+		if err := charge(stream.Context(), engine, pcm); err != nil {
+			return err
+		}
+		processed += len(pcm)
+		if _, err := rec.AcceptWaveform(pcm); err != nil {
+			return status.Errorf(codes.Internal, "failed to recognize audio: %v", err)
+		}
+	}
+
+	results, err := asr.Drain(rec)
+	elapsed += time.Since(start)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to get final result: %v", err)
+	}
+	for _, res := range results {
+		if err := send(stream, s.finalChunk(res, spec)); err != nil {
+			return err
+		}
+		metrics.Since(metrics.ResultLatency.WithLabelValues(metrics.Final), start)
+	}
+	return nil
+}
+
+// recvAudio receives the next audio content of a stream whose config has
+// been received already.
+func recvAudio(stream SttService_StreamingRecognizeServer) ([]byte, error) {
+	req, err := stream.Recv()
+	if err != nil {
+		return nil, err
+	}
+	if req.GetConfig() != nil {
+		return nil, status.Error(codes.InvalidArgument, "recognition config can be sent only once")
+	}
+	return req.GetAudioContent(), nil
+}
+
+// Recognize recognizes a short audio clip and returns all its utterances.
+func (s *SttServer) Recognize(ctx context.Context, req *RecognizeRequest) (*RecognizeResponse, error) {
+	spec, err := specOf(req.GetConfig())
+	if err != nil {
+		return nil, err
+	}
+	sampleRate, err := s.validateSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+	probed := s.probing(spec)
+	var confidence float64
+	if probed {
+		spec, confidence, err = s.identifyLanguage(spec, sampleRate, req.GetAudioContent())
+		if err != nil {
+			return nil, err
+		}
+	}
+	engine, release, err := s.acquire(spec)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	pcm, err := s.decode(ctx, spec, sampleRate, engine, req.GetAudioContent())
+	if err != nil {
+		return nil, err
+	}
+	if s.maxRecognizeDuration > 0 {
+		duration := time.Duration(float64(len(pcm)) / 2 / engine.SampleRate() * float64(time.Second))
+		if duration > s.maxRecognizeDuration {
+			return nil, status.Errorf(codes.InvalidArgument, "audio longer than %v, use LongRunningRecognize", s.maxRecognizeDuration)
+		}
+	}
+	res, err := s.recognize(ctx, spec, engine, pcm, nil)
+	if err != nil {
+		return nil, err
+	}
+	if probed {
+		res.LanguageCode = spec.GetLanguageCode()
+		res.LanguageConfidence = float32(confidence)
+	}
+	return res, nil
+}
+
+// ListModels lists the models of the registry, or the server's engine when
+// the server has no registry.
+func (s *SttServer) ListModels(ctx context.Context, req *ListModelsRequest) (*ListModelsResponse, error) {
+	res := &ListModelsResponse{}
+	if s.registry == nil {
+		res.Models = append(res.Models, &ModelInfo{
+			Name:            s.engine.Name(),
+			SampleRateHertz: int64(s.engine.SampleRate()),
+			Loaded:          true,
+		})
+		return res, nil
+	}
+
+	for _, info := range s.registry.List() {
+		res.Models = append(res.Models, &ModelInfo{
+			Name:            info.Name,
+			LanguageCode:    info.Language,
+			SampleRateHertz: int64(info.SampleRate),
+			SpeakerModel:    info.SpeakerModel != "",
+			Loaded:          s.registry.Loaded(info.Name),
+			SizeBytes:       info.Size,
+		})
+	}
+	return res, nil
+}
+
+// EnrollSpeaker computes the speaker embedding of the sample recording and
+// adds it to the profile of the named speaker.
+func (s *SttServer) EnrollSpeaker(ctx context.Context, req *EnrollSpeakerRequest) (*SpeakerProfile, error) {
+	if s.speakers == nil {
+		return nil, status.Error(codes.FailedPrecondition, "speaker identification is not enabled on the server")
+	}
+	if req.GetName() == "" {
+		return nil, status.Error(codes.InvalidArgument, "speaker name is required")
+	}
+	spec := req.GetConfig().GetSpecification()
+	if spec == nil {
+		spec = &RecognitionSpec{}
+	}
+	sampleRate, err := s.validateSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+	engine, release, err := s.acquire(spec)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	pcm, err := s.decode(ctx, spec, sampleRate, engine, req.GetAudioContent())
+	if err != nil {
+		return nil, err
+	}
+	embedding, err := speaker.Embed(engine, pcm)
+	switch {
+	case errors.Is(err, asr.ErrNoSpeakerModel):
+		return nil, status.Error(codes.FailedPrecondition, "the model has no speaker model")
+	case errors.Is(err, speaker.ErrNoSpeech):
+		return nil, status.Error(codes.InvalidArgument, "no speech found in the sample")
+	case err != nil:
+		return nil, status.Errorf(codes.Internal, "failed to compute the speaker embedding: %v", err)
+	}
+
+	profile, err := s.speakers.Enroll(req.GetName(), embedding)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to enroll speaker: %v", err)
+	}
+	return &SpeakerProfile{Name: profile.Name, Samples: int32(profile.Samples)}, nil
+}
+
+func (s *SttServer) mustEmbedUnimplementedSttServiceServer() {}
+
+// specOf returns the specification of a mandatory config.
+func specOf(config *RecognitionConfig) (*RecognitionSpec, error) {
+	if config == nil {
+		return nil, status.Error(codes.InvalidArgument, "recognition config is required")
+	}
+	spec := config.GetSpecification()
+	if spec == nil {
+		spec = &RecognitionSpec{}
+	}
+	return spec, nil
+}
+
+// modelName resolves the model of the specification in the registry. The
+// empty name stands for the server's engine.
+func (s *SttServer) modelName(spec *RecognitionSpec) (string, error) {
+	if s.registry == nil {
+		return "", nil
+	}
+	code := spec.GetLanguageCode()
+	if strings.EqualFold(code, autoLanguage) {
+		code = ""
+	}
+	if s.engine != nil && spec.GetModel() == "" && code == "" {
+		return "", nil
+	}
+	info, err := s.registry.Lookup(spec.GetModel(), code)
+	if err != nil {
+		return "", status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	return info.Name, nil
+}
+
+// modelLabel names the model of the specification in the metrics.
+func (s *SttServer) modelLabel(spec *RecognitionSpec, engine asr.Engine) string {
+	if name, err := s.modelName(spec); err == nil && name != "" {
+		return name
+	}
+	return engine.Name()
+}
+
+// acquire returns the engine recognizing the specification and the function
+// releasing it.
+func (s *SttServer) acquire(spec *RecognitionSpec) (asr.Engine, func(), error) {
+	name, err := s.modelName(spec)
+	if err != nil {
+		return nil, nil, err
+	}
+	if name == "" {
+		return s.engine, func() {}, nil
+	}
+	engine, release, err := s.registry.Acquire(name)
+	if err != nil {
+		return nil, nil, status.Errorf(codes.Unavailable, "%v", err)
+	}
+	return engine, release, nil
+}
+
+// newDecoder creates the decoder converting the audio into PCM at the
+// engine's sample rate. The sample rate is the one returned by validateSpec.
+func (s *SttServer) newDecoder(spec *RecognitionSpec, sampleRate int64, engine asr.Engine) (*audio.Decoder, error) {
+	if sampleRate == 0 && spec.GetAudioEncoding() <= RecognitionSpec_LINEAR16_PCM {
+		sampleRate = int64(engine.SampleRate())
+	}
+
+	var encoding audio.Encoding
+	switch spec.GetAudioEncoding() {
+	case RecognitionSpec_MULAW:
+		encoding = audio.Mulaw
+	case RecognitionSpec_ALAW:
+		encoding = audio.Alaw
+	case RecognitionSpec_WAV:
+		encoding = audio.Wav
+	case RecognitionSpec_MP3:
+		encoding = audio.Mp3
+	default:
+		encoding = audio.Linear16
+	}
+
+	dec, err := audio.NewDecoder(encoding, int(sampleRate), int(engine.SampleRate()))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid audio: %v", err)
+	}
+	return dec, nil
+}
+
+// decode converts a whole recording into PCM at the engine's sample rate.
+// The audio is charged to the account of the call.
+func (s *SttServer) decode(ctx context.Context, spec *RecognitionSpec, sampleRate int64, engine asr.Engine, content []byte) ([]byte, error) {
+	dec, err := s.newDecoder(spec, sampleRate, engine)
+	if err != nil {
+		return nil, err
+	}
+	pcm, err := dec.Write(content)
+	if err != nil {
+		dec.Close()
+		return nil, decodeError(err)
+	}
+	rest, err := dec.Close()
+	if err != nil {
+		return nil, decodeError(err)
+	}
+	pcm = append(pcm, rest...)
+	if err := charge(ctx, engine, pcm); err != nil {
+		return nil, err
+	}
+	return pcm, nil
+}
+
+// charge takes the duration of PCM at the engine's sample rate from the
+// audio allowance of the caller.
+func charge(ctx context.Context, engine asr.Engine, pcm []byte) error {
+	return auth.Charge(ctx, float64(len(pcm))/2/engine.SampleRate())
+}
+
+// decodeError converts an error of the audio decoder into a status.
+func decodeError(err error) error {
+	if errors.Is(err, audio.ErrSampleRateMismatch) {
+		return status.Errorf(codes.InvalidArgument, "sample_rate_hertz does not match the audio: %v", err)
+	}
+	return status.Errorf(codes.InvalidArgument, "invalid audio: %v", err)
+}
+
+// newRecognizer creates a recognizer for the specification. It expects PCM
+// at the engine's sample rate. The out-of-vocabulary words of the grammar are
+// returned along.
+func (s *SttServer) newRecognizer(spec *RecognitionSpec, engine asr.Engine) (asr.Recognizer, []string, error) {
+	grammar, oov, err := grammarOf(spec, engine)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rec, err := engine.NewRecognizer(asr.Config{
+		SampleRate:      engine.SampleRate(),
+		MaxAlternatives: int(spec.GetMaxAlternatives()),
+		Words:           spec.GetEnableWordTimeOffsets(),
+		Speaker:         spec.GetEnableSpeakerIdentification() || spec.GetVerifySpeaker() != "",
+		Grammar:         grammar,
+	})
+	switch {
+	case errors.Is(err, asr.ErrNoSpeakerModel):
+		return nil, nil, status.Error(codes.FailedPrecondition, "the model has no speaker model")
+	case errors.Is(err, asr.ErrNoGrammar):
+		return nil, nil, status.Error(codes.FailedPrecondition, "the model does not support grammars")
+	case err != nil:
+		return nil, nil, status.Errorf(codes.Internal, "failed to create recognizer: %v", err)
+	}
+	return rec, oov, nil
+}
+
+// newDetector creates the voice activity detector of a stream, nil when the
+// detection is disabled.
+func (s *SttServer) newDetector(engine asr.Engine) *vad.Detector {
+	if !s.vad {
+		return nil
+	}
+	return vad.New(int(engine.SampleRate()), s.vadOptions...)
+}
+
+// speechEnded feeds PCM to the detector, if any, and reports whether a
+// speech segment ended.
+func speechEnded(detector *vad.Detector, pcm []byte) bool {
+	if detector == nil {
+		return false
+	}
+	ended := false
+	for _, event := range detector.WritePCM(pcm) {
+		if event.Type == vad.SpeechEnd {
+			ended = true
+		}
+	}
+	return ended
+}
+
+// grammarOf returns the normalized phrases of the grammar or phrase hints
+// of the specification, without the phrases holding words unknown to the
+// engine. The unknown words are returned as well.
+func grammarOf(spec *RecognitionSpec, engine asr.Engine) ([]string, []string, error) {
+	phrases := spec.GetGrammar()
+	hints := len(spec.GetPhraseHints()) > 0
+	if hints {
+		phrases = spec.GetPhraseHints()
+	}
+	if len(phrases) == 0 {
+		return nil, nil, nil
+	}
+
+	vocabulary, _ := engine.(asr.Vocabulary)
+	seen := map[string]bool{}
+	unknown := map[string]bool{}
+	var grammar []string
+	for _, phrase := range phrases {
+		phrase = strings.Join(strings.Fields(strings.ToLower(phrase)), " ")
+		if phrase == "" || seen[phrase] {
+			continue
+		}
+		seen[phrase] = true
+
+		known := true
+		for _, word := range strings.Fields(phrase) {
+			if vocabulary != nil && !vocabulary.HasWord(word) {
+				unknown[word] = true
+				known = false
+			}
+		}
+		if known {
+			grammar = append(grammar, phrase)
+		}
+	}
+
+	oov := make([]string, 0, len(unknown))
+	for word := range unknown {
+		oov = append(oov, word)
+	}
+	sort.Strings(oov)
+	if len(grammar) == 0 {
+		return nil, nil, status.Errorf(codes.InvalidArgument, "no phrase can be recognized, unknown words: %s", strings.Join(oov, ", "))
+	}
+	if hints {
+		grammar = append(grammar, asr.UnknownPhrase)
+	}
+	return grammar, oov, nil
+}
+
+// recognize feeds a whole decoded recording to a new recognizer and collects
+// the final results. The progress function, if any, is called after every
+// chunk with the number of bytes processed so far.
+func (s *SttServer) recognize(ctx context.Context, spec *RecognitionSpec, engine asr.Engine, pcm []byte, progress func(int)) (*RecognizeResponse, error) {
+	rec, oov, err := s.newRecognizer(spec, engine)
+	if err != nil {
+		return nil, err
+	}
+	defer rec.Close()
+	detector := s.newDetector(engine)
+	start := time.Now()
+
+	res := &RecognizeResponse{OutOfVocabularyWords: oov}
+	for offset := 0; offset < len(pcm); offset += chunkSize {
+		if err := ctx.Err(); err != nil {
+			return nil, status.FromContextError(err).Err()
+		}
+
+		end := offset + chunkSize
+		if end > len(pcm) {
+			end = len(pcm)
+		}
+		result, err := asr.Accept(rec, pcm[offset:end], speechEnded(detector, pcm[offset:end]))
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to recognize audio: %v", err)
+		}
+		if progress != nil {
+			progress(end)
+		}
+		if result != nil {
+			res.Chunks = append(res.Chunks, s.finalChunk(result, spec))
+		}
+	}
+
+	results, err := asr.Drain(rec)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get final result: %v", err)
+	}
+	for _, result := range results {
+		res.Chunks = append(res.Chunks, s.finalChunk(result, spec))
+	}
+	metrics.ObserveRecognition(s.modelLabel(spec, engine), float64(len(pcm))/2/engine.SampleRate(), time.Since(start))
+	return res, nil
+}
+
+// validateSpec checks the recognition specification and returns the sample
+// rate of the audio. It is zero when the audio is at the engine's rate or
+// when it is to be read from a WAV or MP3 stream.
+func (s *SttServer) validateSpec(spec *RecognitionSpec) (int64, error) {
+	sampleRate := spec.GetSampleRateHertz()
+	switch spec.GetAudioEncoding() {
+	case RecognitionSpec_AUDIO_ENCODING_UNSPECIFIED, RecognitionSpec_LINEAR16_PCM:
+		switch sampleRate {
+		case 0, 8000, 16000, 48000:
+		default:
+			return 0, status.Errorf(codes.InvalidArgument, "unsupported sample rate %d Hz", sampleRate)
+		}
+	case RecognitionSpec_MULAW, RecognitionSpec_ALAW:
+		switch sampleRate {
+		case 0:
+			sampleRate = 8000
+		case 8000:
+		default:
+			return 0, status.Errorf(codes.InvalidArgument, "unsupported sample rate %d Hz for %v", sampleRate, spec.GetAudioEncoding())
+		}
+	case RecognitionSpec_WAV, RecognitionSpec_MP3:
+		if sampleRate < 0 {
+			return 0, status.Errorf(codes.InvalidArgument, "invalid sample rate %d Hz", sampleRate)
+		}
+	default:
+		return 0, status.Errorf(codes.InvalidArgument, "unsupported audio encoding %v", spec.GetAudioEncoding())
+	}
+
+	if n := spec.GetMaxAlternatives(); n < 0 || n > maxAlternatives {
+		return 0, status.Errorf(codes.InvalidArgument, "max_alternatives must be between 0 and %d", maxAlternatives)
+	}
+	if len(spec.GetGrammar()) > 0 && len(spec.GetPhraseHints()) > 0 {
+		return 0, status.Error(codes.InvalidArgument, "only one of grammar and phrase_hints may be set")
+	}
+	if n := len(spec.GetGrammar()) + len(spec.GetPhraseHints()); n > maxPhrases {
+		return 0, status.Errorf(codes.InvalidArgument, "at most %d phrases are allowed", maxPhrases)
+	}
+	if spec.GetEnableSpeakerIdentification() || spec.GetVerifySpeaker() != "" {
+		if s.speakers == nil {
+			return 0, status.Error(codes.FailedPrecondition, "speaker identification is not enabled on the server")
+		}
+		if name := spec.GetVerifySpeaker(); name != "" && !s.speakers.Has(name) {
+			return 0, status.Errorf(codes.InvalidArgument, "unknown speaker %q", name)
+		}
+	}
+	return sampleRate, nil
+}
+
+func send(stream SttService_StreamingRecognizeServer, chunk *SpeechRecognitionChunk) error {
+	return stream.Send(&StreamingRecognitionResponse{
+		Chunks: []*SpeechRecognitionChunk{chunk},
+	})
+}
+
+// finalChunk converts a recognition result into a final chunk, rewritten in
+// written form unless raw results are asked for and labelled with the
+// speaker when the specification asks for it.
+func (s *SttServer) finalChunk(res *asr.Result, spec *RecognitionSpec) *SpeechRecognitionChunk {
+	limit := int(spec.GetMaxAlternatives())
+	if limit < 1 {
+		limit = 1
+	}
+
+	normalizer := normalizerOf(spec)
+	var alternatives []*SpeechRecognitionAlternative
+	for _, alt := range res.Alternatives {
+		if len(alternatives) == limit {
+			break
+		}
+		if alt.Text == "" {
+			continue
+		}
+		text, timings := alt.Text, alt.Words
+		if normalizer != nil {
+			text = normalizer.Normalize(text)
+			timings = normalizeWords(normalizer, timings)
+		}
+		words := wordInfos(timings, spec)
+		s.maskWords(spec, words)
+		alternatives = append(alternatives, &SpeechRecognitionAlternative{
+			Text:       s.maskText(spec, text),
+			Confidence: float32(alt.Confidence),
+			Words:      words,
+		})
+	}
+
+	label, score := s.speakerOf(res, spec)
+	for _, alt := range alternatives {
+		alt.SpeakerLabel = label
+		alt.SpeakerScore = score
+	}
+
+	return &SpeechRecognitionChunk{
+		Alternatives:   alternatives,
+		Final:          true,
+		EndOfUtterance: true,
+	}
+}
+
+// normalizerOf returns the inverse text normalizer of the specification,
+// nil when it asks for raw results or its language is not supported.
+func normalizerOf(spec *RecognitionSpec) *itn.Normalizer {
+	if spec.GetRawResults() {
+		return nil
+	}
+	return itn.For(spec.GetLanguageCode())
+}
+
+// normalizeWords merges the words rewritten together into a single word
+// spanning their timings.
+func normalizeWords(normalizer *itn.Normalizer, words []asr.Word) []asr.Word {
+	if len(words) == 0 {
+		return nil
+	}
+	texts := make([]string, len(words))
+	for i, w := range words {
+		texts[i] = w.Word
+	}
+	spans := normalizer.Spans(texts)
+	normalized := make([]asr.Word, len(spans))
+	for i, span := range spans {
+		var confidence float64
+		for _, w := range words[span.Start:span.End] {
+			confidence += w.Confidence
+		}
+		normalized[i] = asr.Word{
+			Word:       span.Text,
+			Start:      words[span.Start].Start,
+			End:        words[span.End-1].End,
+			Confidence: confidence / float64(span.End-span.Start),
+		}
+	}
+	return normalized
+}
+
+// speakerOf identifies or verifies the speaker of a result. The label is
+// empty when no enrolled speaker matches.
+func (s *SttServer) speakerOf(res *asr.Result, spec *RecognitionSpec) (string, float32) {
+	if s.speakers == nil || len(res.Speaker) == 0 {
+		return "", 0
+	}
+	if name := spec.GetVerifySpeaker(); name != "" {
+		match, ok, err := s.speakers.Verify(name, res.Speaker)
+		if err != nil || !ok {
+			return "", float32(match.Score)
+		}
+		return match.Name, float32(match.Score)
+	}
+	if spec.GetEnableSpeakerIdentification() {
+		match, ok := s.speakers.Identify(res.Speaker)
+		if !ok {
+			return "", float32(match.Score)
+		}
+		return match.Name, float32(match.Score)
+	}
+	return "", 0
+}
+
+// maskText masks the banned words of a text when the specification enables
+// the profanity filter. The words are masked rather than dropped, so that
+// the text stays aligned with the word timings.
+func (s *SttServer) maskText(spec *RecognitionSpec, text string) string {
+	if !spec.GetProfanityFilter() {
+		return text
+	}
+	filter := s.moderation.For(spec.GetLanguageCode())
+	return strings.Join(filter.MaskWords(strings.Fields(text)), " ")
+}
+
+// maskWords masks the banned words of the word timings when the
+// specification enables the profanity filter.
+func (s *SttServer) maskWords(spec *RecognitionSpec, infos []*WordInfo) {
+	if !spec.GetProfanityFilter() || len(infos) == 0 {
+		return
+	}
+	words := make([]string, len(infos))
+	for i, info := range infos {
+		words[i] = info.GetWord()
+	}
+	for i, word := range s.moderation.For(spec.GetLanguageCode()).MaskWords(words) {
+		infos[i].Word = word
+	}
+}
+
+func wordInfos(words []asr.Word, spec *RecognitionSpec) []*WordInfo {
+	if !spec.GetEnableWordTimeOffsets() || len(words) == 0 {
+		return nil
+	}
+	infos := make([]*WordInfo, 0, len(words))
+	for _, w := range words {
+		infos = append(infos, &WordInfo{
+			StartTime:  durationpb.New(w.Start),
+			EndTime:    durationpb.New(w.End),
+			Word:       w.Word,
+			Confidence: float32(w.Confidence),
+		})
+	}
+	return infos
+}
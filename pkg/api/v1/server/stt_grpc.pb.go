@@ -19,6 +19,18 @@ const _ = grpc.SupportPackageIsVersion7
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type SttServiceClient interface {
 	StreamingRecognize(ctx context.Context, opts ...grpc.CallOption) (SttService_StreamingRecognizeClient, error)
+	// Recognize performs synchronous recognition of a short audio clip.
+	Recognize(ctx context.Context, in *RecognizeRequest, opts ...grpc.CallOption) (*RecognizeResponse, error)
+	// LongRunningRecognize queues the recognition of a long recording and returns
+	// immediately. The result is obtained by polling GetOperation.
+	LongRunningRecognize(ctx context.Context, in *LongRunningRecognizeRequest, opts ...grpc.CallOption) (*Operation, error)
+	GetOperation(ctx context.Context, in *GetOperationRequest, opts ...grpc.CallOption) (*Operation, error)
+	CancelOperation(ctx context.Context, in *CancelOperationRequest, opts ...grpc.CallOption) (*Operation, error)
+	// ListModels returns the models the server can recognize with.
+	ListModels(ctx context.Context, in *ListModelsRequest, opts ...grpc.CallOption) (*ListModelsResponse, error)
+	// EnrollSpeaker adds a sample recording to the profile of a named speaker,
+	// which is created if needed.
+	EnrollSpeaker(ctx context.Context, in *EnrollSpeakerRequest, opts ...grpc.CallOption) (*SpeakerProfile, error)
 }
 
 type sttServiceClient struct {
@@ -60,11 +72,77 @@ func (x *sttServiceStreamingRecognizeClient) Recv() (*StreamingRecognitionRespon
 	return m, nil
 }
 
+func (c *sttServiceClient) Recognize(ctx context.Context, in *RecognizeRequest, opts ...grpc.CallOption) (*RecognizeResponse, error) {
+	out := new(RecognizeResponse)
+	err := c.cc.Invoke(ctx, "/v1.server.SttService/Recognize", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sttServiceClient) LongRunningRecognize(ctx context.Context, in *LongRunningRecognizeRequest, opts ...grpc.CallOption) (*Operation, error) {
+	out := new(Operation)
+	err := c.cc.Invoke(ctx, "/v1.server.SttService/LongRunningRecognize", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sttServiceClient) GetOperation(ctx context.Context, in *GetOperationRequest, opts ...grpc.CallOption) (*Operation, error) {
+	out := new(Operation)
+	err := c.cc.Invoke(ctx, "/v1.server.SttService/GetOperation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sttServiceClient) CancelOperation(ctx context.Context, in *CancelOperationRequest, opts ...grpc.CallOption) (*Operation, error) {
+	out := new(Operation)
+	err := c.cc.Invoke(ctx, "/v1.server.SttService/CancelOperation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sttServiceClient) ListModels(ctx context.Context, in *ListModelsRequest, opts ...grpc.CallOption) (*ListModelsResponse, error) {
+	out := new(ListModelsResponse)
+	err := c.cc.Invoke(ctx, "/v1.server.SttService/ListModels", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sttServiceClient) EnrollSpeaker(ctx context.Context, in *EnrollSpeakerRequest, opts ...grpc.CallOption) (*SpeakerProfile, error) {
+	out := new(SpeakerProfile)
+	err := c.cc.Invoke(ctx, "/v1.server.SttService/EnrollSpeaker", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // SttServiceServer is the server API for SttService service.
 // All implementations must embed UnimplementedSttServiceServer
 // for forward compatibility
 type SttServiceServer interface {
 	StreamingRecognize(SttService_StreamingRecognizeServer) error
+	// Recognize performs synchronous recognition of a short audio clip.
+	Recognize(context.Context, *RecognizeRequest) (*RecognizeResponse, error)
+	// LongRunningRecognize queues the recognition of a long recording and returns
+	// immediately. The result is obtained by polling GetOperation.
+	LongRunningRecognize(context.Context, *LongRunningRecognizeRequest) (*Operation, error)
+	GetOperation(context.Context, *GetOperationRequest) (*Operation, error)
+	CancelOperation(context.Context, *CancelOperationRequest) (*Operation, error)
+	// ListModels returns the models the server can recognize with.
+	ListModels(context.Context, *ListModelsRequest) (*ListModelsResponse, error)
+	// EnrollSpeaker adds a sample recording to the profile of a named speaker,
+	// which is created if needed.
+	EnrollSpeaker(context.Context, *EnrollSpeakerRequest) (*SpeakerProfile, error)
 	mustEmbedUnimplementedSttServiceServer()
 }
 
@@ -75,6 +153,24 @@ type UnimplementedSttServiceServer struct {
 func (UnimplementedSttServiceServer) StreamingRecognize(SttService_StreamingRecognizeServer) error {
 	return status.Errorf(codes.Unimplemented, "method StreamingRecognize not implemented")
 }
+func (UnimplementedSttServiceServer) Recognize(context.Context, *RecognizeRequest) (*RecognizeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Recognize not implemented")
+}
+func (UnimplementedSttServiceServer) LongRunningRecognize(context.Context, *LongRunningRecognizeRequest) (*Operation, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LongRunningRecognize not implemented")
+}
+func (UnimplementedSttServiceServer) GetOperation(context.Context, *GetOperationRequest) (*Operation, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOperation not implemented")
+}
+func (UnimplementedSttServiceServer) CancelOperation(context.Context, *CancelOperationRequest) (*Operation, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelOperation not implemented")
+}
+func (UnimplementedSttServiceServer) ListModels(context.Context, *ListModelsRequest) (*ListModelsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListModels not implemented")
+}
+func (UnimplementedSttServiceServer) EnrollSpeaker(context.Context, *EnrollSpeakerRequest) (*SpeakerProfile, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EnrollSpeaker not implemented")
+}
 func (UnimplementedSttServiceServer) mustEmbedUnimplementedSttServiceServer() {}
 
 // UnsafeSttServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -114,13 +210,146 @@ func (x *sttServiceStreamingRecognizeServer) Recv() (*StreamingRecognitionReques
 	return m, nil
 }
 
+func _SttService_Recognize_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RecognizeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SttServiceServer).Recognize(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.server.SttService/Recognize",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SttServiceServer).Recognize(ctx, req.(*RecognizeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SttService_LongRunningRecognize_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LongRunningRecognizeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SttServiceServer).LongRunningRecognize(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.server.SttService/LongRunningRecognize",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SttServiceServer).LongRunningRecognize(ctx, req.(*LongRunningRecognizeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SttService_GetOperation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOperationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SttServiceServer).GetOperation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.server.SttService/GetOperation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SttServiceServer).GetOperation(ctx, req.(*GetOperationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SttService_CancelOperation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelOperationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SttServiceServer).CancelOperation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.server.SttService/CancelOperation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SttServiceServer).CancelOperation(ctx, req.(*CancelOperationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SttService_ListModels_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListModelsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SttServiceServer).ListModels(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.server.SttService/ListModels",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SttServiceServer).ListModels(ctx, req.(*ListModelsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SttService_EnrollSpeaker_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EnrollSpeakerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SttServiceServer).EnrollSpeaker(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.server.SttService/EnrollSpeaker",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SttServiceServer).EnrollSpeaker(ctx, req.(*EnrollSpeakerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // SttService_ServiceDesc is the grpc.ServiceDesc for SttService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
 var SttService_ServiceDesc = grpc.ServiceDesc{
 	ServiceName: "v1.server.SttService",
 	HandlerType: (*SttServiceServer)(nil),
-	Methods:     []grpc.MethodDesc{},
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Recognize",
+			Handler:    _SttService_Recognize_Handler,
+		},
+		{
+			MethodName: "LongRunningRecognize",
+			Handler:    _SttService_LongRunningRecognize_Handler,
+		},
+		{
+			MethodName: "GetOperation",
+			Handler:    _SttService_GetOperation_Handler,
+		},
+		{
+			MethodName: "CancelOperation",
+			Handler:    _SttService_CancelOperation_Handler,
+		},
+		{
+			MethodName: "ListModels",
+			Handler:    _SttService_ListModels_Handler,
+		},
+		{
+			MethodName: "EnrollSpeaker",
+			Handler:    _SttService_EnrollSpeaker_Handler,
+		},
+	},
 	Streams: []grpc.StreamDesc{
 		{
 			StreamName:    "StreamingRecognize",
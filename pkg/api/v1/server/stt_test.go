@@ -0,0 +1,542 @@
+package server
+
+// Copyright (c) 2018 Bhojpur Consulting Private Limited, India. All rights reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bhojpur/speech/internal/grpctest"
+	"github.com/bhojpur/speech/pkg/asr"
+	"github.com/bhojpur/speech/pkg/asr/fake"
+	"github.com/bhojpur/speech/pkg/auth"
+	"github.com/bhojpur/speech/pkg/models"
+	"github.com/bhojpur/speech/pkg/service/moderation"
+	"github.com/bhojpur/speech/pkg/speaker"
+	"github.com/bhojpur/speech/pkg/wave"
+	"github.com/bhojpur/speech/pkg/wave/g711"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// dial starts an in-memory server backed by the given engine.
+func dial(t *testing.T, engine asr.Engine) SttServiceClient {
+	return dialServer(t, NewServer(engine))
+}
+
+// dialServer serves the given server in memory.
+func dialServer(t *testing.T, server *SttServer, opts ...grpc.ServerOption) SttServiceClient {
+	conn := grpctest.Dial(t, func(s *grpc.Server) { RegisterSttServiceServer(s, server) }, opts...)
+	return NewSttServiceClient(conn)
+}
+
+// recognize sends the spec and audio split into chunks of the given size
+// and collects all the chunks received back.
+func recognize(t *testing.T, client SttServiceClient, spec *RecognitionSpec, audio []byte, size int) ([]*SpeechRecognitionChunk, error) {
+	stream, err := client.StreamingRecognize(context.Background())
+	require.NoError(t, err)
+
+	err = stream.Send(&StreamingRecognitionRequest{
+		StreamingRequest: &StreamingRecognitionRequest_Config{
+			Config: &RecognitionConfig{Specification: spec},
+		},
+	})
+	require.NoError(t, err)
+	for len(audio) > 0 {
+		n := size
+		if n > len(audio) {
+			n = len(audio)
+		}
+		err = stream.Send(&StreamingRecognitionRequest{
+			StreamingRequest: &StreamingRecognitionRequest_AudioContent{AudioContent: audio[:n]},
+		})
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		audio = audio[n:]
+	}
+	require.NoError(t, stream.CloseSend())
+
+	var chunks []*SpeechRecognitionChunk
+	for {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			return chunks, nil
+		}
+		if err != nil {
+			return chunks, err
+		}
+		chunks = append(chunks, res.GetChunks()...)
+	}
+}
+
+// utterances are the two seconds of speech replayed by the test engines.
+var utterances = []fake.Utterance{
+	{Text: "turn on the light", Seconds: 1, Confidence: 0.9, Alternatives: []string{"turn on the lights"}},
+	{Text: "thank you", Seconds: 1},
+}
+
+func script() *fake.Engine {
+	return fake.NewEngine(16000, utterances...)
+}
+
+func TestStreamingRecognizeFinalResults(t *testing.T) {
+	engine := script()
+	client := dial(t, engine)
+
+	chunks, err := recognize(t, client, &RecognitionSpec{
+		AudioEncoding:         RecognitionSpec_LINEAR16_PCM,
+		SampleRateHertz:       16000,
+		MaxAlternatives:       2,
+		EnableWordTimeOffsets: true,
+	}, make([]byte, 2*16000*2), 3200)
+	require.NoError(t, err)
+
+	require.Len(t, chunks, 2)
+	assert.True(t, chunks[0].GetFinal())
+	assert.True(t, chunks[0].GetEndOfUtterance())
+	require.Len(t, chunks[0].GetAlternatives(), 2)
+	assert.Equal(t, "turn on the light", chunks[0].GetAlternatives()[0].GetText())
+	assert.InDelta(t, 0.9, chunks[0].GetAlternatives()[0].GetConfidence(), 1e-6)
+	assert.Equal(t, "turn on the lights", chunks[0].GetAlternatives()[1].GetText())
+	require.Len(t, chunks[0].GetAlternatives()[0].GetWords(), 4)
+	assert.Equal(t, "light", chunks[0].GetAlternatives()[0].GetWords()[3].GetWord())
+	assert.Equal(t, "thank you", chunks[1].GetAlternatives()[0].GetText())
+	assert.Equal(t, 0, engine.Active())
+}
+
+func TestStreamingRecognizePartialResults(t *testing.T) {
+	client := dial(t, script())
+
+	chunks, err := recognize(t, client, &RecognitionSpec{
+		PartialResults:  true,
+		SingleUtterance: true,
+	}, make([]byte, 2*16000*2), 8000)
+	require.NoError(t, err)
+
+	var texts []string
+	for _, chunk := range chunks {
+		assert.Len(t, chunk.GetAlternatives(), 1)
+		assert.Empty(t, chunk.GetAlternatives()[0].GetWords())
+		texts = append(texts, chunk.GetAlternatives()[0].GetText())
+	}
+	assert.Equal(t, []string{"turn", "turn on", "turn on the", "turn on the light"}, texts)
+	assert.False(t, chunks[0].GetFinal())
+	assert.True(t, chunks[len(chunks)-1].GetFinal())
+}
+
+func TestStreamingRecognizeInvalidConfig(t *testing.T) {
+	client := dial(t, script())
+
+	_, err := recognize(t, client, &RecognitionSpec{SampleRateHertz: 44100}, nil, 1)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = recognize(t, client, &RecognitionSpec{MaxAlternatives: 31}, nil, 1)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	stream, err := client.StreamingRecognize(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, stream.Send(&StreamingRecognitionRequest{
+		StreamingRequest: &StreamingRecognitionRequest_AudioContent{AudioContent: []byte{0, 0}},
+	}))
+	_, err = stream.Recv()
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestRecognize(t *testing.T) {
+	client := dial(t, script())
+
+	res, err := client.Recognize(context.Background(), &RecognizeRequest{
+		Config:       &RecognitionConfig{Specification: &RecognitionSpec{}},
+		AudioContent: make([]byte, 2*16000*2),
+	})
+	require.NoError(t, err)
+	require.Len(t, res.GetChunks(), 2)
+	assert.Equal(t, "turn on the light", res.GetChunks()[0].GetAlternatives()[0].GetText())
+	assert.Equal(t, "thank you", res.GetChunks()[1].GetAlternatives()[0].GetText())
+
+	_, err = client.Recognize(context.Background(), &RecognizeRequest{AudioContent: []byte{0, 0}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = client.Recognize(context.Background(), &RecognizeRequest{
+		Config:       &RecognitionConfig{Specification: &RecognitionSpec{SampleRateHertz: 8000}},
+		AudioContent: make([]byte, 2*8000*61),
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+// wavFile builds a mono 16-bit WAV file holding the samples.
+func wavFile(sampleRate int, pcm []byte) []byte {
+	var buf bytes.Buffer
+	wave.NewWriter(&buf, uint32(len(pcm)/2), 1, uint32(sampleRate), 16).Write(pcm)
+	return buf.Bytes()
+}
+
+func TestStreamingRecognizeEncodings(t *testing.T) {
+	client := dial(t, script())
+
+	// two seconds of u-law silence are resampled to the engine's rate
+	chunks, err := recognize(t, client, &RecognitionSpec{
+		AudioEncoding: RecognitionSpec_MULAW,
+	}, g711.EncodeUlaw(make([]byte, 2*8000*2)), 1000)
+	require.NoError(t, err)
+	require.Len(t, chunks, 2)
+	assert.Equal(t, "thank you", chunks[1].GetAlternatives()[0].GetText())
+
+	chunks, err = recognize(t, client, &RecognitionSpec{
+		AudioEncoding: RecognitionSpec_WAV,
+	}, wavFile(48000, make([]byte, 2*48000*2)), 4001)
+	require.NoError(t, err)
+	require.Len(t, chunks, 2)
+	assert.Equal(t, "turn on the light", chunks[0].GetAlternatives()[0].GetText())
+
+	_, err = recognize(t, client, &RecognitionSpec{
+		AudioEncoding:   RecognitionSpec_WAV,
+		SampleRateHertz: 16000,
+	}, wavFile(8000, make([]byte, 8000)), 4000)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = recognize(t, client, &RecognitionSpec{
+		AudioEncoding:   RecognitionSpec_ALAW,
+		SampleRateHertz: 16000,
+	}, nil, 1)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = client.Recognize(context.Background(), &RecognizeRequest{
+		Config:       &RecognitionConfig{Specification: &RecognitionSpec{AudioEncoding: RecognitionSpec_MP3}},
+		AudioContent: bytes.Repeat([]byte("not an mp3 stream "), 100),
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func init() {
+	// models of the registry tests replay the script at their sample rate
+	asr.Register("stt-test", func(path string) (asr.Engine, error) {
+		info, err := models.ReadInfo(path)
+		if err != nil {
+			return nil, err
+		}
+		return fake.NewEngine(info.SampleRate, utterances...), nil
+	})
+}
+
+func TestModelRegistry(t *testing.T) {
+	dir, err := ioutil.TempDir("", "models")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	for _, name := range []string{"vosk-model-small-en-us-0.15", "vosk-model-de-0.21"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, name, "conf"), 0755))
+	}
+	conf := []byte("--sample-frequency=8000\n")
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "vosk-model-de-0.21", "conf", "mfcc.conf"), conf, 0644))
+
+	registry, err := models.New(dir, models.WithEngine("stt-test"), models.WithDefault("vosk-model-small-en-us-0.15"))
+	require.NoError(t, err)
+	defer registry.Close()
+	server := NewServer(nil, WithRegistry(registry))
+	defer server.Close()
+
+	res, err := server.ListModels(context.Background(), &ListModelsRequest{})
+	require.NoError(t, err)
+	require.Len(t, res.GetModels(), 2)
+	assert.Equal(t, "vosk-model-de-0.21", res.GetModels()[0].GetName())
+	assert.Equal(t, "de", res.GetModels()[0].GetLanguageCode())
+	assert.Equal(t, int64(8000), res.GetModels()[0].GetSampleRateHertz())
+	assert.False(t, res.GetModels()[0].GetLoaded())
+
+	// two seconds at the 8 kHz of the German model
+	recognized, err := server.Recognize(context.Background(), &RecognizeRequest{
+		Config:       &RecognitionConfig{Specification: &RecognitionSpec{LanguageCode: "de-DE"}},
+		AudioContent: make([]byte, 2*8000*2),
+	})
+	require.NoError(t, err)
+	assert.Len(t, recognized.GetChunks(), 2)
+	assert.True(t, registry.Loaded("vosk-model-de-0.21"))
+	assert.False(t, registry.Loaded("vosk-model-small-en-us-0.15"))
+
+	recognized, err = server.Recognize(context.Background(), &RecognizeRequest{
+		Config:       &RecognitionConfig{Specification: &RecognitionSpec{}},
+		AudioContent: make([]byte, 2*16000*2),
+	})
+	require.NoError(t, err)
+	assert.Len(t, recognized.GetChunks(), 2)
+	assert.True(t, registry.Loaded("vosk-model-small-en-us-0.15"))
+
+	_, err = server.Recognize(context.Background(), &RecognizeRequest{
+		Config:       &RecognitionConfig{Specification: &RecognitionSpec{Model: "missing"}},
+		AudioContent: make([]byte, 2),
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = server.LongRunningRecognize(context.Background(), &LongRunningRecognizeRequest{
+		Config: &RecognitionConfig{Specification: &RecognitionSpec{LanguageCode: "fr"}},
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestSpeakerIdentification(t *testing.T) {
+	engine := fake.NewEngine(16000,
+		fake.Utterance{Text: "good morning doctor", Seconds: 1, Speaker: []float64{1, 0.1}},
+		fake.Utterance{Text: "how are you", Seconds: 1, Speaker: []float64{0.1, 1}},
+	)
+	speakers, err := speaker.Open("")
+	require.NoError(t, err)
+	server := NewServer(engine, WithSpeakers(speakers))
+	defer server.Close()
+
+	// the first second of the script is spoken by the patient
+	profile, err := server.EnrollSpeaker(context.Background(), &EnrollSpeakerRequest{
+		Name:         "patient",
+		AudioContent: make([]byte, 16000*2),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "patient", profile.GetName())
+	assert.Equal(t, int32(1), profile.GetSamples())
+	_, err = speakers.Enroll("doctor", []float64{0, 1})
+	require.NoError(t, err)
+
+	res, err := server.Recognize(context.Background(), &RecognizeRequest{
+		Config:       &RecognitionConfig{Specification: &RecognitionSpec{EnableSpeakerIdentification: true}},
+		AudioContent: make([]byte, 2*16000*2),
+	})
+	require.NoError(t, err)
+	require.Len(t, res.GetChunks(), 2)
+	assert.Equal(t, "patient", res.GetChunks()[0].GetAlternatives()[0].GetSpeakerLabel())
+	assert.InDelta(t, 1, res.GetChunks()[0].GetAlternatives()[0].GetSpeakerScore(), 1e-6)
+	assert.Equal(t, "doctor", res.GetChunks()[1].GetAlternatives()[0].GetSpeakerLabel())
+
+	res, err = server.Recognize(context.Background(), &RecognizeRequest{
+		Config:       &RecognitionConfig{Specification: &RecognitionSpec{VerifySpeaker: "patient"}},
+		AudioContent: make([]byte, 2*16000*2),
+	})
+	require.NoError(t, err)
+	require.Len(t, res.GetChunks(), 2)
+	assert.Equal(t, "patient", res.GetChunks()[0].GetAlternatives()[0].GetSpeakerLabel())
+	assert.Empty(t, res.GetChunks()[1].GetAlternatives()[0].GetSpeakerLabel())
+	assert.Less(t, res.GetChunks()[1].GetAlternatives()[0].GetSpeakerScore(), float32(0.5))
+
+	_, err = server.Recognize(context.Background(), &RecognizeRequest{
+		Config: &RecognitionConfig{Specification: &RecognitionSpec{VerifySpeaker: "visitor"}},
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = NewServer(engine).Recognize(context.Background(), &RecognizeRequest{
+		Config: &RecognitionConfig{Specification: &RecognitionSpec{EnableSpeakerIdentification: true}},
+	})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func TestGrammar(t *testing.T) {
+	client := dial(t, script())
+
+	stream, err := client.StreamingRecognize(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, stream.Send(&StreamingRecognitionRequest{
+		StreamingRequest: &StreamingRecognitionRequest_Config{Config: &RecognitionConfig{
+			Specification: &RecognitionSpec{Grammar: []string{"Turn on  the light", "switch off heater"}},
+		}},
+	}))
+	res, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Empty(t, res.GetChunks())
+	assert.Equal(t, []string{"heater", "off", "switch"}, res.GetOutOfVocabularyWords())
+	require.NoError(t, stream.CloseSend())
+
+	recognized, err := client.Recognize(context.Background(), &RecognizeRequest{
+		Config: &RecognitionConfig{Specification: &RecognitionSpec{
+			PhraseHints: []string{"turn on the light", "thank god"},
+		}},
+		AudioContent: make([]byte, 2*16000*2),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"god"}, recognized.GetOutOfVocabularyWords())
+	require.Len(t, recognized.GetChunks(), 2)
+	assert.Equal(t, "turn on the light", recognized.GetChunks()[0].GetAlternatives()[0].GetText())
+	assert.Equal(t, "[unk]", recognized.GetChunks()[1].GetAlternatives()[0].GetText())
+
+	chunks, err := recognize(t, client, &RecognitionSpec{
+		Grammar: []string{"turn on the light", "thank you"},
+	}, make([]byte, 2*16000*2), 3200)
+	require.NoError(t, err)
+	require.Len(t, chunks, 2)
+	assert.Equal(t, "thank you", chunks[1].GetAlternatives()[0].GetText())
+
+	_, err = recognize(t, client, &RecognitionSpec{
+		Grammar:     []string{"thank you"},
+		PhraseHints: []string{"thank you"},
+	}, nil, 1)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = recognize(t, client, &RecognitionSpec{Grammar: []string{"unknown words"}}, make([]byte, 2), 2)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestProfanityFilter(t *testing.T) {
+	engine := fake.NewEngine(16000, fake.Utterance{Text: "what the hell happened", Seconds: 1})
+	filters := moderation.NewMaskFilters(moderation.DefaultFilterMap)
+	banned := moderation.NewFilterMap()
+	banned.Set("kya", "")
+	filters.Add("hi", *banned)
+	server := NewServer(engine, WithModeration(filters))
+	defer server.Close()
+
+	spec := &RecognitionSpec{ProfanityFilter: true, EnableWordTimeOffsets: true}
+	res, err := server.Recognize(context.Background(), &RecognizeRequest{
+		Config:       &RecognitionConfig{Specification: spec},
+		AudioContent: make([]byte, 16000*2),
+	})
+	require.NoError(t, err)
+	alt := res.GetChunks()[0].GetAlternatives()[0]
+	assert.Equal(t, "what the h*** happened", alt.GetText())
+	require.Len(t, alt.GetWords(), 4)
+	assert.Equal(t, "h***", alt.GetWords()[2].GetWord())
+	assert.Equal(t, 500*time.Millisecond, alt.GetWords()[2].GetStartTime().AsDuration())
+
+	// the Hindi list does not ban the English word
+	spec.LanguageCode = "hi-IN"
+	res, err = server.Recognize(context.Background(), &RecognizeRequest{
+		Config:       &RecognitionConfig{Specification: spec},
+		AudioContent: make([]byte, 16000*2),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "what the hell happened", res.GetChunks()[0].GetAlternatives()[0].GetText())
+}
+
+func TestInverseTextNormalization(t *testing.T) {
+	engine := fake.NewEngine(16000, fake.Utterance{Text: "pay twenty five rupees on march third", Seconds: 1.4, Confidence: 0.8})
+	server := NewServer(engine)
+	defer server.Close()
+
+	spec := &RecognitionSpec{EnableWordTimeOffsets: true}
+	res, err := server.Recognize(context.Background(), &RecognizeRequest{
+		Config:       &RecognitionConfig{Specification: spec},
+		AudioContent: make([]byte, 2*16000*2),
+	})
+	require.NoError(t, err)
+	alt := res.GetChunks()[0].GetAlternatives()[0]
+	assert.Equal(t, "pay ₹25 on March 3", alt.GetText())
+	require.Len(t, alt.GetWords(), 4)
+	// the amount spans the timings of its three spoken words
+	assert.Equal(t, "₹25", alt.GetWords()[1].GetWord())
+	assert.Equal(t, 200*time.Millisecond, alt.GetWords()[1].GetStartTime().AsDuration())
+	assert.Equal(t, 800*time.Millisecond, alt.GetWords()[1].GetEndTime().AsDuration())
+	assert.InDelta(t, 0.8, alt.GetWords()[1].GetConfidence(), 1e-6)
+
+	spec.RawResults = true
+	res, err = server.Recognize(context.Background(), &RecognizeRequest{
+		Config:       &RecognitionConfig{Specification: spec},
+		AudioContent: make([]byte, 2*16000*2),
+	})
+	require.NoError(t, err)
+	alt = res.GetChunks()[0].GetAlternatives()[0]
+	assert.Equal(t, "pay twenty five rupees on march third", alt.GetText())
+	assert.Len(t, alt.GetWords(), 7)
+}
+
+// voice is one second of a vowel-like sound, a 150 Hz fundamental with
+// fading harmonics, as 16 kHz PCM.
+func voice() []byte {
+	pcm := make([]byte, 2*16000)
+	for i := 0; i < 16000; i++ {
+		var s float64
+		for h := 1; h <= 10; h++ {
+			s += math.Sin(2*math.Pi*150*float64(h)*float64(i)/16000) / float64(h)
+		}
+		binary.LittleEndian.PutUint16(pcm[2*i:], uint16(int16(s*5000)))
+	}
+	return pcm
+}
+
+func TestVoiceActivityDetection(t *testing.T) {
+	// the engine would end the utterance after two seconds only
+	engine := fake.NewEngine(16000, fake.Utterance{Text: "alpha bravo charlie delta", Seconds: 2})
+	client := dialServer(t, NewServer(engine, WithVoiceActivityDetection()))
+
+	audio := append(voice(), make([]byte, 2*2*16000)...)
+	chunks, err := recognize(t, client, &RecognitionSpec{SingleUtterance: true}, audio, 3200)
+	require.NoError(t, err)
+	// the utterance ends half a second into the silence
+	require.Len(t, chunks, 1)
+	assert.True(t, chunks[0].GetEndOfUtterance())
+	assert.Equal(t, "alpha bravo charlie", chunks[0].GetAlternatives()[0].GetText())
+
+	res, err := NewServer(engine, WithVoiceActivityDetection()).Recognize(context.Background(), &RecognizeRequest{
+		Config:       &RecognitionConfig{Specification: &RecognitionSpec{}},
+		AudioContent: audio,
+	})
+	require.NoError(t, err)
+	require.Len(t, res.GetChunks(), 1)
+	assert.Equal(t, "alpha bravo charlie", res.GetChunks()[0].GetAlternatives()[0].GetText())
+}
+
+func TestAudioQuota(t *testing.T) {
+	guard := auth.New([]auth.Key{{Name: "alice", Key: "secret", AudioSecondsPerMinute: 3}})
+	client := dialServer(t, NewServer(script()),
+		grpc.ChainUnaryInterceptor(guard.UnaryInterceptor()),
+		grpc.ChainStreamInterceptor(guard.StreamInterceptor()),
+	)
+	ctx := metadata.AppendToOutgoingContext(context.Background(), auth.APIKeyHeader, "secret")
+	request := &RecognizeRequest{
+		Config:       &RecognitionConfig{Specification: &RecognitionSpec{}},
+		AudioContent: make([]byte, 2*16000*2),
+	}
+
+	_, err := client.Recognize(context.Background(), request)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = client.Recognize(ctx, request)
+	require.NoError(t, err)
+	_, err = client.Recognize(ctx, request)
+	require.NoError(t, err)
+	_, err = client.Recognize(ctx, request)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+func TestAudioQuotaLongRecording(t *testing.T) {
+	guard := auth.New([]auth.Key{{Name: "alice", Key: "secret", AudioSecondsPerMinute: 3}})
+	client := dialServer(t, NewServer(script()),
+		grpc.ChainUnaryInterceptor(guard.UnaryInterceptor()),
+		grpc.ChainStreamInterceptor(guard.StreamInterceptor()),
+	)
+	ctx := metadata.AppendToOutgoingContext(context.Background(), auth.APIKeyHeader, "secret")
+	request := &RecognizeRequest{
+		Config:       &RecognitionConfig{Specification: &RecognitionSpec{}},
+		AudioContent: make([]byte, 10*16000*2),
+	}
+
+	// an idle account recognizes a recording longer than its allowance
+	_, err := client.Recognize(ctx, request)
+	require.NoError(t, err)
+	_, err = client.Recognize(ctx, request)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
@@ -0,0 +1,456 @@
+package client
+
+// Copyright (c) 2018 Bhojpur Consulting Private Limited, India. All rights reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// It plays the audio of the Streamer service through a pluggable sink. An
+// adaptive jitter buffer absorbs the variations of the network, filling
+// underruns with silence, and broken calls are resumed where they stopped.
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/bhojpur/speech/pkg/api/v1/stream"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// Sink plays audio, PortAudio for instance.
+type Sink interface {
+	// Open starts playing interleaved 16-bit audio of a sample rate and
+	// number of channels. When the format changes, the sink is closed and
+	// opened again.
+	Open(rate, channels int) error
+	// Write plays samples, blocking until the sink can take more.
+	Write(samples []int16) error
+	// Close stops playing.
+	Close() error
+}
+
+// EventType tells what happened to a player.
+type EventType int
+
+const (
+	// Connected is sent when a call starts.
+	Connected EventType = iota
+	// Reconnecting is sent after a call broke, with its error and the
+	// delay before the next one.
+	Reconnecting
+	// Dropped is sent when the radio dropped chunks the player was too slow
+	// to receive.
+	Dropped
+	// Underrun is sent when the buffer ran out of audio.
+	Underrun
+	// FormatChanged is sent when the sink is opened for a new format.
+	FormatChanged
+	// EndOfTrack is sent once a track has been played.
+	EndOfTrack
+	// Paused is sent once the audio preceding a pause has been played.
+	Paused
+)
+
+// Event is sent to the function of WithEvents.
+type Event struct {
+	Type EventType
+	// Data is the message of the server the event is about.
+	Data *stream.Data
+	Err  error
+	// Delay before reconnecting.
+	Delay time.Duration
+	// Rate and Channels of the sink.
+	Rate     int
+	Channels int
+}
+
+const (
+	// DefaultFrame is the duration of the audio written at once to the
+	// sink.
+	DefaultFrame = 20 * time.Millisecond
+	// DefaultMinDelay and DefaultMaxDelay bound the target of the jitter
+	// buffer.
+	DefaultMinDelay = 100 * time.Millisecond
+	DefaultMaxDelay = 2 * time.Second
+	// DefaultMinBackoff and DefaultMaxBackoff bound the delay between
+	// reconnections, which doubles after every failure.
+	DefaultMinBackoff = 250 * time.Millisecond
+	DefaultMaxBackoff = 10 * time.Second
+)
+
+// Player plays the audio of a Streamer.
+type Player struct {
+	client     stream.StreamerClient
+	sink       Sink
+	frame      time.Duration
+	minDelay   time.Duration
+	maxDelay   time.Duration
+	minBackoff time.Duration
+	maxBackoff time.Duration
+	maxRetries int
+	events     func(Event)
+}
+
+// Option configures a Player.
+type Option func(*Player)
+
+// WithFrame sets the duration of the audio written at once to the sink.
+func WithFrame(frame time.Duration) Option {
+	return func(p *Player) {
+		p.frame = frame
+	}
+}
+
+// WithJitter bounds the audio the jitter buffer holds before playing.
+func WithJitter(min, max time.Duration) Option {
+	return func(p *Player) {
+		p.minDelay = min
+		p.maxDelay = max
+	}
+}
+
+// WithBackoff bounds the delay between reconnections.
+func WithBackoff(min, max time.Duration) Option {
+	return func(p *Player) {
+		p.minBackoff = min
+		p.maxBackoff = max
+	}
+}
+
+// WithMaxRetries limits the reconnections in a row, zero meaning no limit.
+func WithMaxRetries(n int) Option {
+	return func(p *Player) {
+		p.maxRetries = n
+	}
+}
+
+// WithEvents sets a function receiving the events of the player. It is
+// called from the goroutines of the player and must not block.
+func WithEvents(fn func(Event)) Option {
+	return func(p *Player) {
+		p.events = fn
+	}
+}
+
+// New creates a player of the streamer playing to the sink.
+func New(client stream.StreamerClient, sink Sink, opts ...Option) *Player {
+	p := &Player{
+		client:     client,
+		sink:       sink,
+		frame:      DefaultFrame,
+		minDelay:   DefaultMinDelay,
+		maxDelay:   DefaultMaxDelay,
+		minBackoff: DefaultMinBackoff,
+		maxBackoff: DefaultMaxBackoff,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Play plays the track of the config, sending the controls to the server.
+// Closing the controls, or passing none, lets the call end with the track.
+// A broken call is resumed with the last track played, from the end of the
+// audio received. Play returns once the audio has been played, or at once
+// when the context is cancelled.
+func (p *Player) Play(ctx context.Context, config *stream.PlayConfig, controls <-chan *stream.PlayRequest) error {
+	s := &session{
+		config: proto.Clone(config).(*stream.PlayConfig),
+		closed: controls == nil,
+	}
+	if s.config.Track == nil {
+		s.config.Track = &stream.Track{}
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if controls != nil {
+		go s.forward(ctx, controls)
+	}
+	return p.run(ctx, func(ctx context.Context) (receiver, error) {
+		return s.open(ctx, p.client)
+	}, s.update)
+}
+
+// Listen plays the radio. A broken call joins the radio again at its live
+// position.
+func (p *Player) Listen(ctx context.Context, req *stream.ListenRequest) error {
+	return p.run(ctx, func(ctx context.Context) (receiver, error) {
+		return p.client.Listen(ctx, req)
+	}, nil)
+}
+
+// receiver is a Play or Listen call.
+type receiver interface {
+	Recv() (*stream.Data, error)
+}
+
+// run plays the audio of the calls made by open, calling update with every
+// chunk received, until the last call ends.
+func (p *Player) run(ctx context.Context, open func(context.Context) (receiver, error), update func(*stream.Data, format, int)) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	buf := newJitterBuffer(p.frame, p.minDelay, p.maxDelay)
+
+	errc := make(chan error, 1)
+	go func() {
+		err := p.receive(ctx, buf, open, update)
+		if err != nil {
+			buf.abort()
+		}
+		buf.close()
+		errc <- err
+	}()
+	go func() {
+		<-ctx.Done()
+		buf.abort()
+	}()
+
+	err := p.playout(buf)
+	cancel()
+	if rerr := <-errc; rerr != nil && err == nil {
+		err = rerr
+	}
+	return err
+}
+
+// receive makes the calls and buffers their audio, reconnecting with an
+// exponential backoff after the calls broken by transient errors.
+func (p *Player) receive(ctx context.Context, buf *jitterBuffer, open func(context.Context) (receiver, error), update func(*stream.Data, format, int)) error {
+	backoff := p.minBackoff
+	retries := 0
+	for {
+		err := p.call(ctx, buf, open, update, func() {
+			backoff, retries = p.minBackoff, 0
+		})
+		if err == io.EOF {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return status.FromContextError(ctx.Err()).Err()
+		}
+		if !retryable(err) || (p.maxRetries > 0 && retries >= p.maxRetries) {
+			return err
+		}
+		retries++
+		p.notify(Event{Type: Reconnecting, Err: err, Delay: backoff})
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		}
+		backoff *= 2
+		if backoff > p.maxBackoff {
+			backoff = p.maxBackoff
+		}
+	}
+}
+
+// call buffers the audio of a call until it ends, calling received once
+// the call works.
+func (p *Player) call(ctx context.Context, buf *jitterBuffer, open func(context.Context) (receiver, error), update func(*stream.Data, format, int), received func()) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	r, err := open(ctx)
+	if err != nil {
+		return err
+	}
+	var decoder payloadDecoder
+	for first := true; ; first = false {
+		data, err := r.Recv()
+		if err != nil {
+			return err
+		}
+		if first {
+			received()
+			p.notify(Event{Type: Connected, Data: data})
+		}
+		if data.GetDropped() > 0 {
+			p.notify(Event{Type: Dropped, Data: data})
+		}
+
+		samples, f, err := decoder.decode(data)
+		if err != nil {
+			return status.Errorf(codes.DataLoss, "failed to decode audio: %v", err)
+		}
+		if update != nil {
+			update(data, f, len(samples))
+		}
+		switch {
+		case len(samples) > 0:
+			buf.push(chunk{format: f, samples: samples})
+		case data.GetEndOfTrack() || data.GetPaused():
+			buf.push(chunk{format: f, marker: data})
+		}
+	}
+}
+
+// playout writes the buffered audio to the sink.
+func (p *Player) playout(buf *jitterBuffer) error {
+	var current format
+	defer func() {
+		if current.rate != 0 {
+			p.sink.Close()
+		}
+	}()
+	for {
+		pc, ok := buf.pop()
+		if !ok {
+			return nil
+		}
+		if pc.marker != nil {
+			if pc.marker.GetEndOfTrack() {
+				p.notify(Event{Type: EndOfTrack, Data: pc.marker})
+			} else {
+				p.notify(Event{Type: Paused, Data: pc.marker})
+			}
+			continue
+		}
+		if pc.underrun {
+			p.notify(Event{Type: Underrun})
+		}
+		if len(pc.samples) == 0 {
+			continue
+		}
+		if pc.format != current {
+			if current.rate != 0 {
+				if err := p.sink.Close(); err != nil {
+					buf.abort()
+					return err
+				}
+			}
+			current = format{}
+			if err := p.sink.Open(pc.format.rate, pc.format.channels); err != nil {
+				buf.abort()
+				return err
+			}
+			current = pc.format
+			p.notify(Event{Type: FormatChanged, Rate: current.rate, Channels: current.channels})
+		}
+		if err := p.sink.Write(pc.samples); err != nil {
+			buf.abort()
+			return err
+		}
+	}
+}
+
+func (p *Player) notify(e Event) {
+	if p.events != nil {
+		p.events(e)
+	}
+}
+
+// retryable reports whether a call broken by an error is worth making
+// again.
+func retryable(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.Aborted, codes.Internal, codes.Unknown, codes.DataLoss:
+		return true
+	}
+	return false
+}
+
+// session is the state of Play, carried over the calls.
+type session struct {
+	config *stream.PlayConfig
+
+	mu      sync.Mutex
+	current stream.Streamer_PlayClient
+	closed  bool // the controls
+}
+
+// open makes a Play call resuming the session.
+func (s *session) open(ctx context.Context, client stream.StreamerClient) (receiver, error) {
+	call, err := client.Play(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	err = call.Send(&stream.PlayRequest{Request: &stream.PlayRequest_Config{Config: s.config}})
+	if err != nil {
+		return nil, err
+	}
+	if s.closed {
+		call.CloseSend()
+	}
+	s.current = call
+	return call, nil
+}
+
+// forward sends the controls to the current call, keeping the config up to
+// date for the next calls.
+func (s *session) forward(ctx context.Context, controls <-chan *stream.PlayRequest) {
+	for {
+		select {
+		case req, ok := <-controls:
+			s.mu.Lock()
+			if !ok {
+				s.closed = true
+				if s.current != nil {
+					s.current.CloseSend()
+				}
+				s.mu.Unlock()
+				return
+			}
+			switch r := req.GetRequest().(type) {
+			case *stream.PlayRequest_Select:
+				s.config.Track = proto.Clone(r.Select).(*stream.Track)
+			case *stream.PlayRequest_Seek:
+				s.config.Track.Position = r.Seek
+			case *stream.PlayRequest_Pause:
+				s.config.Paused = true
+			case *stream.PlayRequest_Resume:
+				s.config.Paused = false
+			}
+			if s.current != nil {
+				// a failure breaks the call, which is resumed
+				s.current.Send(req)
+			}
+			s.mu.Unlock()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// update moves the config of the next calls to the end of the audio
+// received. The track is resumed by name, its index changing with the
+// library.
+func (s *session) update(data *stream.Data, f format, samples int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	position := data.GetPosition().AsDuration() + f.duration(samples)
+	if data.GetEndOfTrack() {
+		position = data.GetDuration().AsDuration()
+	}
+	s.config.Track = &stream.Track{
+		Name:     data.GetFilename(),
+		Position: durationpb.New(position),
+	}
+	if data.GetPaused() {
+		s.config.Paused = true
+	} else if samples > 0 {
+		s.config.Paused = false
+	}
+}
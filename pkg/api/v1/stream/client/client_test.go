@@ -0,0 +1,352 @@
+package client
+
+// Copyright (c) 2018 Bhojpur Consulting Private Limited, India. All rights reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bhojpur/speech/internal/grpctest"
+	"github.com/bhojpur/speech/pkg/api/v1/stream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+const testLibrary = "../../../../../audios"
+
+// fakeSink records the audio it plays in real time.
+type fakeSink struct {
+	mu      sync.Mutex
+	formats []format
+	samples int
+	open    bool
+}
+
+func (s *fakeSink) Open(rate, channels int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.formats = append(s.formats, format{rate: rate, channels: channels})
+	s.open = true
+	return nil
+}
+
+func (s *fakeSink) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.samples
+}
+
+func (s *fakeSink) Write(samples []int16) error {
+	s.mu.Lock()
+	f := s.formats[len(s.formats)-1]
+	s.samples += len(samples)
+	s.mu.Unlock()
+	time.Sleep(f.duration(len(samples)))
+	return nil
+}
+
+func (s *fakeSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.open = false
+	return nil
+}
+
+// breakCall breaks a call, counted from one, after it sent n messages.
+func breakCall(call int32, n int) grpc.StreamServerInterceptor {
+	var calls int32
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if atomic.AddInt32(&calls, 1) != call {
+			return handler(srv, ss)
+		}
+		return handler(srv, &breakingStream{ServerStream: ss, left: n})
+	}
+}
+
+type breakingStream struct {
+	grpc.ServerStream
+	left int
+}
+
+func (s *breakingStream) SendMsg(m interface{}) error {
+	if s.left == 0 {
+		return status.Error(codes.Unavailable, "connection lost")
+	}
+	s.left--
+	return s.ServerStream.SendMsg(m)
+}
+
+func dialServer(t *testing.T, server *stream.StreamServer, opts ...grpc.ServerOption) stream.StreamerClient {
+	conn := grpctest.Dial(t, func(s *grpc.Server) { stream.RegisterStreamerServer(s, server) }, opts...)
+	t.Cleanup(server.Close)
+	return stream.NewStreamerClient(conn)
+}
+
+// lastSeconds selects the last seconds of the track of the test library.
+func lastSeconds(t *testing.T, client stream.StreamerClient, seconds time.Duration) *stream.Track {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	call, err := client.Play(ctx)
+	require.NoError(t, err)
+	require.NoError(t, call.Send(&stream.PlayRequest{Request: &stream.PlayRequest_Config{Config: &stream.PlayConfig{
+		Track: &stream.Track{Index: 1},
+	}}}))
+	data, err := call.Recv()
+	require.NoError(t, err)
+	return &stream.Track{Index: 1, Position: durationpb.New(data.GetDuration().AsDuration() - seconds)}
+}
+
+// events records the events of a player.
+type events struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+func (e *events) record(event Event) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.events = append(e.events, event)
+}
+
+func (e *events) count(typ EventType) int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	n := 0
+	for _, event := range e.events {
+		if event.Type == typ {
+			n++
+		}
+	}
+	return n
+}
+
+func TestPlay(t *testing.T) {
+	client := dialServer(t, stream.NewServer(stream.WithLibrary(testLibrary)))
+	sink := &fakeSink{}
+	var recorded events
+	// the whole track is buffered, so that a slow server does not underrun
+	player := New(client, sink, WithEvents(recorded.record), WithJitter(time.Minute, time.Minute))
+
+	err := player.Play(context.Background(), &stream.PlayConfig{
+		Track:    lastSeconds(t, client, time.Second),
+		Rate:     8000,
+		Channels: 1,
+	}, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, []format{{rate: 8000, channels: 1}}, sink.formats)
+	assert.False(t, sink.open)
+	assert.InDelta(t, 8000, sink.samples, 400)
+	assert.Equal(t, 1, recorded.count(Connected))
+	assert.Equal(t, 1, recorded.count(EndOfTrack))
+	assert.Equal(t, 0, recorded.count(Reconnecting))
+}
+
+func TestPlayReconnects(t *testing.T) {
+	client := dialServer(t, stream.NewServer(stream.WithLibrary(testLibrary), stream.WithLead(time.Hour)),
+		grpc.ChainStreamInterceptor(breakCall(2, 3)))
+	sink := &fakeSink{}
+	var recorded events
+	player := New(client, sink, WithEvents(recorded.record), WithJitter(time.Minute, time.Minute),
+		WithBackoff(time.Millisecond, time.Millisecond))
+
+	err := player.Play(context.Background(), &stream.PlayConfig{
+		Track:    lastSeconds(t, client, time.Second),
+		Rate:     8000,
+		Channels: 1,
+		Format:   stream.PayloadFormat_MULAW,
+	}, nil)
+	require.NoError(t, err)
+
+	// the audio is resumed, neither lost nor repeated
+	assert.InDelta(t, 8000, sink.samples, 400)
+	assert.Equal(t, 1, recorded.count(Reconnecting))
+	assert.Equal(t, 2, recorded.count(Connected))
+	assert.Equal(t, 1, recorded.count(EndOfTrack))
+}
+
+func TestSessionUpdate(t *testing.T) {
+	s := &session{config: &stream.PlayConfig{Track: &stream.Track{Index: 1}}}
+	s.update(&stream.Data{
+		Sequence: 1,
+		Filename: "b.mp3",
+		Position: durationpb.New(time.Second),
+	}, format{rate: 8000, channels: 1}, 4000)
+
+	// a track added before b.mp3 does not change what is resumed
+	assert.Equal(t, "b.mp3", s.config.Track.GetName())
+	assert.Zero(t, s.config.Track.GetIndex())
+	assert.Equal(t, 1500*time.Millisecond, s.config.Track.GetPosition().AsDuration())
+}
+
+func TestPlayFailure(t *testing.T) {
+	client := dialServer(t, stream.NewServer(stream.WithLibrary(testLibrary)))
+	var recorded events
+	player := New(client, &fakeSink{}, WithEvents(recorded.record))
+
+	err := player.Play(context.Background(), &stream.PlayConfig{Track: &stream.Track{Name: "missing.mp3"}}, nil)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.Equal(t, 0, recorded.count(Reconnecting))
+}
+
+func TestPlayControls(t *testing.T) {
+	client := dialServer(t, stream.NewServer(stream.WithLibrary(testLibrary)))
+	sink := &fakeSink{}
+	var recorded events
+	player := New(client, sink, WithEvents(recorded.record))
+
+	track := lastSeconds(t, client, 3*time.Second)
+	controls := make(chan *stream.PlayRequest)
+	done := make(chan error)
+	go func() {
+		done <- player.Play(context.Background(), &stream.PlayConfig{Track: &stream.Track{Index: 1}, Format: stream.PayloadFormat_MP3}, controls)
+	}()
+	require.Eventually(t, func() bool { return sink.count() > 0 }, 5*time.Second, 10*time.Millisecond)
+	controls <- &stream.PlayRequest{Request: &stream.PlayRequest_Pause{}}
+	require.Eventually(t, func() bool { return recorded.count(Paused) == 1 }, 5*time.Second, 10*time.Millisecond)
+
+	controls <- &stream.PlayRequest{Request: &stream.PlayRequest_Select{Select: track}}
+	controls <- &stream.PlayRequest{Request: &stream.PlayRequest_Resume{}}
+	close(controls)
+	require.NoError(t, <-done)
+
+	// the MP3 frames of the track are decoded to stereo
+	for _, f := range sink.formats {
+		assert.Equal(t, 2, f.channels)
+	}
+	assert.Equal(t, 1, recorded.count(EndOfTrack))
+}
+
+func TestPlayCancel(t *testing.T) {
+	client := dialServer(t, stream.NewServer(stream.WithLibrary(testLibrary)))
+	sink := &fakeSink{}
+	player := New(client, sink)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error)
+	go func() {
+		done <- player.Play(ctx, &stream.PlayConfig{Track: &stream.Track{Index: 1}, Rate: 8000, Channels: 1}, make(chan *stream.PlayRequest))
+	}()
+	require.Eventually(t, func() bool { return sink.count() > 0 }, 5*time.Second, 10*time.Millisecond)
+	cancel()
+
+	// the playback stops without waiting for the end of the track
+	select {
+	case err := <-done:
+		assert.Equal(t, codes.Canceled, status.Code(err))
+	case <-time.After(time.Second):
+		t.Fatal("the playback went on after the cancellation")
+	}
+	assert.False(t, sink.open)
+	assert.Less(t, sink.count(), 8000*2)
+}
+
+func TestPlayout(t *testing.T) {
+	sink := &fakeSink{}
+	var recorded events
+	player := New(nil, sink, WithEvents(recorded.record))
+
+	buf := newJitterBuffer(DefaultFrame, DefaultMinDelay, DefaultMaxDelay)
+	mono := format{rate: 8000, channels: 1}
+	stereo := format{rate: 16000, channels: 2}
+	buf.push(chunk{format: mono, samples: make([]int16, 800)})
+	buf.push(chunk{format: stereo, samples: make([]int16, 3200)})
+	buf.close()
+	require.NoError(t, player.playout(buf))
+
+	assert.Equal(t, []format{mono, stereo}, sink.formats)
+	assert.Equal(t, 4000, sink.samples)
+	assert.Equal(t, 2, recorded.count(FormatChanged))
+	assert.False(t, sink.open)
+}
+
+func TestJitterBuffer(t *testing.T) {
+	f := format{rate: 1000, channels: 1}
+	buf := newJitterBuffer(10*time.Millisecond, 30*time.Millisecond, 100*time.Millisecond)
+	now := time.Now()
+	buf.now = func() time.Time { return now }
+
+	// the playback starts once the target is buffered
+	popped := make(chan piece)
+	go func() {
+		p, _ := buf.pop()
+		popped <- p
+	}()
+	buf.push(chunk{format: f, samples: make([]int16, 20)})
+	select {
+	case <-popped:
+		t.Fatal("playback started before the target was buffered")
+	case <-time.After(20 * time.Millisecond):
+	}
+	buf.push(chunk{format: f, samples: make([]int16, 15)})
+	p := <-popped
+	assert.Len(t, p.samples, 10)
+	for i := 0; i < 2; i++ {
+		p, _ = buf.pop()
+		assert.Len(t, p.samples, 10)
+	}
+	p, _ = buf.pop()
+	assert.Len(t, p.samples, 5)
+
+	// an underrun is filled with silence until the larger target is
+	// buffered again
+	p, ok := buf.pop()
+	require.True(t, ok)
+	assert.True(t, p.underrun)
+	assert.Len(t, p.samples, 10)
+	assert.Equal(t, 45*time.Millisecond, buf.target)
+	buf.push(chunk{format: f, samples: []int16{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1}})
+	p, _ = buf.pop()
+	assert.False(t, p.underrun)
+	assert.Equal(t, make([]int16, 10), p.samples)
+	buf.push(chunk{format: f, samples: make([]int16, 20)})
+	p, _ = buf.pop()
+	assert.Equal(t, int16(1), p.samples[0])
+
+	// the target shrinks back while the playback runs smoothly
+	now = now.Add(time.Minute)
+	buf.pop()
+	assert.Equal(t, 40500*time.Microsecond, buf.target)
+
+	// markers are played even below the target, and the rest once closed
+	buf.push(chunk{format: f, marker: &stream.Data{EndOfTrack: true}})
+	for {
+		p, _ = buf.pop()
+		if p.marker != nil {
+			break
+		}
+	}
+	buf.push(chunk{format: f, samples: make([]int16, 5)})
+	buf.close()
+	p, ok = buf.pop()
+	require.True(t, ok)
+	assert.Len(t, p.samples, 5)
+	_, ok = buf.pop()
+	assert.False(t, ok)
+}
@@ -0,0 +1,68 @@
+package client
+
+// Copyright (c) 2018 Bhojpur Consulting Private Limited, India. All rights reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/bhojpur/speech/pkg/api/v1/stream"
+	"github.com/bhojpur/speech/pkg/audio"
+	"github.com/bhojpur/speech/pkg/mp3"
+	"github.com/bhojpur/speech/pkg/wave/g711"
+)
+
+// payloadDecoder decodes the payloads of a stream into 16-bit samples.
+type payloadDecoder struct {
+	mp3 mp3.FrameDecoder
+}
+
+// decode returns the samples of a chunk and their format, the MP3 decoder
+// always producing stereo.
+func (d *payloadDecoder) decode(data *stream.Data) ([]int16, format, error) {
+	f := format{rate: int(data.GetRate()), channels: int(data.GetChannels())}
+	switch data.GetFormat() {
+	case stream.PayloadFormat_MULAW:
+		return audio.Int16s(g711.DecodeUlaw(data.GetData())), f, nil
+	case stream.PayloadFormat_ALAW:
+		return audio.Int16s(g711.DecodeAlaw(data.GetData())), f, nil
+	case stream.PayloadFormat_MP3:
+		// the chunks hold whole frames
+		f.channels = 2
+		var pcm []byte
+		frames := mp3.NewFrameReader(bytes.NewReader(data.GetData()))
+		for {
+			frame, err := frames.ReadFrame()
+			if err == io.EOF {
+				return audio.Int16s(pcm), f, nil
+			}
+			if err != nil {
+				return nil, f, err
+			}
+			out, err := d.mp3.Decode(frame.Data)
+			if err != nil {
+				return nil, f, err
+			}
+			pcm = append(pcm, out...)
+		}
+	}
+	return audio.Int16s(data.GetData()), f, nil
+}
@@ -0,0 +1,243 @@
+package client
+
+// Copyright (c) 2018 Bhojpur Consulting Private Limited, India. All rights reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"sync"
+	"time"
+
+	"github.com/bhojpur/speech/pkg/api/v1/stream"
+)
+
+// format is the sample rate and number of channels of audio.
+type format struct {
+	rate     int
+	channels int
+}
+
+// duration returns the duration of interleaved samples.
+func (f format) duration(samples int) time.Duration {
+	if f.rate == 0 || f.channels == 0 {
+		return 0
+	}
+	return time.Duration(samples/f.channels) * time.Second / time.Duration(f.rate)
+}
+
+// samples returns the number of interleaved samples lasting d.
+func (f format) samples(d time.Duration) int {
+	return int(int64(d)*int64(f.rate)/int64(time.Second)) * f.channels
+}
+
+// chunk is audio received, or a marker of the end of a track or of a pause
+// when it carries no samples.
+type chunk struct {
+	format  format
+	samples []int16
+	marker  *stream.Data
+}
+
+// piece is the next audio to play, silence filling an underrun, or a
+// marker.
+type piece struct {
+	format   format
+	samples  []int16
+	underrun bool
+	marker   *stream.Data
+}
+
+type bufferState int
+
+const (
+	waiting     bufferState = iota // for the buffer to fill, the sink idle
+	playing                        // the buffered audio
+	rebuffering                    // after an underrun, filling the sink with silence
+)
+
+// jitterBuffer holds the audio received ahead of the playback. The playback
+// starts, and restarts after an underrun, once the buffer holds its target
+// delay. The target grows after every underrun, and shrinks back towards
+// its minimum while the playback runs smoothly.
+type jitterBuffer struct {
+	frame    time.Duration
+	min, max time.Duration
+	adapt    time.Duration // of smooth playback before shrinking the target
+	now      func() time.Time
+
+	mu        sync.Mutex
+	cond      *sync.Cond
+	chunks    []chunk
+	buffered  time.Duration
+	target    time.Duration
+	state     bufferState
+	last      format // of the audio played last
+	adapted   time.Time
+	underruns int
+	closed    bool
+	aborted   bool
+}
+
+func newJitterBuffer(frame, min, max time.Duration) *jitterBuffer {
+	b := &jitterBuffer{
+		frame:  frame,
+		min:    min,
+		max:    max,
+		adapt:  10 * time.Second,
+		now:    time.Now,
+		target: min,
+	}
+	b.cond = sync.NewCond(&b.mu)
+	return b
+}
+
+// push queues a chunk.
+func (b *jitterBuffer) push(c chunk) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.chunks = append(b.chunks, c)
+	b.buffered += c.format.duration(len(c.samples))
+	b.cond.Broadcast()
+}
+
+// close tells that no more chunks will be pushed, the buffered ones are
+// still played.
+func (b *jitterBuffer) close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	b.cond.Broadcast()
+}
+
+// abort stops the playback.
+func (b *jitterBuffer) abort() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.aborted = true
+	b.cond.Broadcast()
+}
+
+// pop returns the next piece to play, at most a frame long. It waits for
+// the buffer to fill, and returns false once the buffer is closed and
+// empty, or aborted.
+func (b *jitterBuffer) pop() (piece, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for {
+		if b.aborted {
+			return piece{}, false
+		}
+		switch b.state {
+		case waiting:
+			if b.closed && len(b.chunks) == 0 {
+				return piece{}, false
+			}
+			if b.ready() {
+				b.state = playing
+				b.adapted = b.now()
+				continue
+			}
+			b.cond.Wait()
+		case rebuffering:
+			if b.ready() {
+				b.state = playing
+				continue
+			}
+			return piece{format: b.last, samples: make([]int16, b.last.samples(b.frame))}, true
+		case playing:
+			if len(b.chunks) == 0 {
+				if b.closed {
+					return piece{}, false
+				}
+				b.underrun()
+				return piece{format: b.last, samples: make([]int16, b.last.samples(b.frame)), underrun: true}, true
+			}
+			if b.chunks[0].marker != nil {
+				// the sink idles after a track or during a pause
+				c := b.chunks[0]
+				b.chunks = b.chunks[1:]
+				b.state = waiting
+				return piece{format: c.format, marker: c.marker}, true
+			}
+			if b.target > b.min && b.now().Sub(b.adapted) >= b.adapt {
+				b.target = b.target * 9 / 10
+				if b.target < b.min {
+					b.target = b.min
+				}
+				b.adapted = b.now()
+			}
+			return b.take(), true
+		}
+	}
+}
+
+// ready reports whether the playback can start: the target is buffered,
+// or no more audio comes before a marker or the end.
+func (b *jitterBuffer) ready() bool {
+	if b.buffered >= b.target || b.closed {
+		return true
+	}
+	for _, c := range b.chunks {
+		if c.marker != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// underrun grows the target and fills the sink with silence until the
+// target is buffered again.
+func (b *jitterBuffer) underrun() {
+	b.underruns++
+	b.target = b.target * 3 / 2
+	if b.target > b.max {
+		b.target = b.max
+	}
+	b.adapted = b.now()
+	b.state = rebuffering
+}
+
+// take returns up to a frame of the audio of the format of the first chunk,
+// joining the following chunks of the same format.
+func (b *jitterBuffer) take() piece {
+	f := b.chunks[0].format
+	n := f.samples(b.frame)
+	p := piece{format: f}
+	for len(b.chunks) > 0 && len(p.samples) < n {
+		c := &b.chunks[0]
+		if c.marker != nil || c.format != f {
+			break
+		}
+		k := n - len(p.samples)
+		if k > len(c.samples) {
+			k = len(c.samples)
+		}
+		p.samples = append(p.samples, c.samples[:k]...)
+		c.samples = c.samples[k:]
+		if len(c.samples) == 0 {
+			b.chunks = b.chunks[1:]
+		}
+	}
+	b.buffered -= f.duration(len(p.samples))
+	if b.buffered < 0 || len(b.chunks) == 0 {
+		b.buffered = 0
+	}
+	b.last = f
+	return p
+}
@@ -0,0 +1,328 @@
+package stream
+
+// Copyright (c) 2018 Bhojpur Consulting Private Limited, India. All rights reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bhojpur/speech/pkg/audio"
+	"github.com/bhojpur/speech/pkg/mp3"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// library is the directory of MP3 tracks served by the streamer.
+type library struct {
+	dir string
+
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+func newLibrary(dir string) *library {
+	return &library{
+		dir:  dir,
+		rand: rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// names returns the file names of the tracks sorted by name.
+func (l *library) names() ([]string, error) {
+	files, err := ioutil.ReadDir(l.dir)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to read the library: %v", err)
+	}
+	var names []string
+	for _, file := range files {
+		if !file.IsDir() && strings.EqualFold(filepath.Ext(file.Name()), ".mp3") {
+			names = append(names, file.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// open opens the selected track, positioned as the selection asks.
+func (l *library) open(sel *Track) (*track, error) {
+	names, err := l.names()
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return nil, status.Error(codes.NotFound, "the library is empty")
+	}
+
+	index := -1
+	switch {
+	case sel.GetName() != "":
+		for i, name := range names {
+			if name == sel.GetName() {
+				index = i
+			}
+		}
+		if index < 0 {
+			return nil, status.Errorf(codes.NotFound, "track %q not found", sel.GetName())
+		}
+	case sel.GetIndex() != 0:
+		if sel.GetIndex() < 1 || int(sel.GetIndex()) > len(names) {
+			return nil, status.Errorf(codes.NotFound, "track %d not found, the library has %d tracks", sel.GetIndex(), len(names))
+		}
+		index = int(sel.GetIndex()) - 1
+	default:
+		l.mu.Lock()
+		index = l.rand.Intn(len(names))
+		l.mu.Unlock()
+	}
+
+	t, err := openTrack(filepath.Join(l.dir, names[index]))
+	if err != nil {
+		return nil, err
+	}
+	t.sequence = int32(index + 1)
+	if position := sel.GetPosition(); position != nil {
+		if err := t.seek(position.AsDuration()); err != nil {
+			t.close()
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// track decodes an MP3 file, which the decoder always turns into 16-bit
+// stereo, or passes its MP3 frames through.
+type track struct {
+	sequence int32
+	name     string
+	path     string
+	channels int // of the MP3 frames
+	file     *os.File
+	decoder  *mp3.Decoder
+	frames   int64 // stereo frames decoded or passed through so far
+
+	// passthrough reads the MP3 frames from its own file
+	passthrough     *mp3.FrameReader
+	passthroughFile *os.File
+}
+
+func openTrack(path string) (*track, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to open track: %v", err)
+	}
+	t := &track{
+		name: filepath.Base(path),
+		path: path,
+		file: file,
+	}
+	frame, err := mp3.NewFrameReader(file).ReadFrame()
+	if err != nil {
+		file.Close()
+		return nil, status.Errorf(codes.Internal, "failed to read %s: %v", t.name, err)
+	}
+	t.channels = frame.Channels
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		file.Close()
+		return nil, status.Errorf(codes.Internal, "failed to read %s: %v", t.name, err)
+	}
+	t.decoder, err = mp3.NewDecoder(file)
+	if err != nil {
+		file.Close()
+		return nil, status.Errorf(codes.Internal, "failed to decode %s: %v", t.name, err)
+	}
+	return t, nil
+}
+
+// rate returns the sample rate of the track.
+func (t *track) rate() int {
+	return t.decoder.SampleRate()
+}
+
+// duration returns the duration of the track.
+func (t *track) duration() time.Duration {
+	return t.at(t.decoder.Length() / 4)
+}
+
+// position returns the position of the next samples read.
+func (t *track) position() time.Duration {
+	return t.at(t.frames)
+}
+
+func (t *track) at(frames int64) time.Duration {
+	return time.Duration(frames) * time.Second / time.Duration(t.rate())
+}
+
+// seek moves to a position of the track.
+func (t *track) seek(position time.Duration) error {
+	if position < 0 || position > t.duration() {
+		return status.Errorf(codes.OutOfRange, "position %v is outside of the track of %v", position, t.duration())
+	}
+	// rounded, since the durations of the positions are truncated
+	frames := (int64(position)*int64(t.rate()) + int64(time.Second)/2) / int64(time.Second)
+	if t.passthrough != nil {
+		return t.openPassthrough(frames)
+	}
+	// the decoder can not seek to the very end, where read stops anyway
+	if frames < t.decoder.Length()/4 {
+		if _, err := t.decoder.Seek(4*frames, io.SeekStart); err != nil {
+			return status.Errorf(codes.Internal, "failed to seek: %v", err)
+		}
+	}
+	t.frames = frames
+	return nil
+}
+
+// openPassthrough starts passing the MP3 frames through from the first one
+// starting at or after a position, in stereo frames.
+func (t *track) openPassthrough(position int64) error {
+	if t.passthroughFile != nil {
+		t.passthroughFile.Close()
+	}
+	file, err := os.Open(t.path)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to open track: %v", err)
+	}
+	t.passthroughFile = file
+	t.passthrough = mp3.NewFrameReader(file)
+	t.frames = 0
+	for t.frames < position {
+		frame, err := t.passthrough.ReadFrame()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to read %s: %v", t.name, err)
+		}
+		t.frames += int64(frame.Samples)
+	}
+	return nil
+}
+
+// readMP3 reads whole MP3 frames decoding to at least n stereo frames. It
+// returns io.EOF at the end of the track.
+func (t *track) readMP3(n int) ([]byte, error) {
+	if t.passthrough == nil {
+		if err := t.openPassthrough(t.frames); err != nil {
+			return nil, err
+		}
+	}
+	var data []byte
+	for samples := 0; samples < n; {
+		frame, err := t.passthrough.ReadFrame()
+		if err == io.EOF {
+			if len(data) > 0 {
+				break
+			}
+			return nil, io.EOF
+		}
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to read %s: %v", t.name, err)
+		}
+		data = append(data, frame.Data...)
+		samples += frame.Samples
+		t.frames += int64(frame.Samples)
+	}
+	return data, nil
+}
+
+// read decodes up to n stereo frames. It returns io.EOF at the end of the
+// track.
+func (t *track) read(n int) ([]int16, error) {
+	if t.frames >= t.decoder.Length()/4 {
+		return nil, io.EOF
+	}
+	buf := make([]byte, 4*n)
+	read, err := io.ReadFull(t.decoder, buf)
+	if err == io.ErrUnexpectedEOF || (err == io.EOF && read > 0) {
+		err = nil
+	}
+	if err != nil {
+		if err != io.EOF {
+			err = status.Errorf(codes.Internal, "failed to decode %s: %v", t.name, err)
+		}
+		return nil, err
+	}
+	read -= read % 4
+	t.frames += int64(read / 4)
+	return audio.Int16s(buf[:read]), nil
+}
+
+func (t *track) close() {
+	t.file.Close()
+	if t.passthroughFile != nil {
+		t.passthroughFile.Close()
+	}
+}
+
+// converter converts the stereo samples of a track to the sample rate and
+// channels of a playback.
+type converter struct {
+	channels   int
+	resamplers []*audio.Resampler
+}
+
+func newConverter(from, to, channels int) *converter {
+	c := &converter{channels: channels}
+	for i := 0; i < channels; i++ {
+		c.resamplers = append(c.resamplers, audio.NewResampler(from, to))
+	}
+	return c
+}
+
+// process converts stereo samples.
+func (c *converter) process(stereo []int16) []int16 {
+	if c.channels == 1 {
+		return c.resamplers[0].Process(audio.Downmix(stereo, 2))
+	}
+	left := make([]int16, len(stereo)/2)
+	right := make([]int16, len(stereo)/2)
+	for i := range left {
+		left[i], right[i] = stereo[2*i], stereo[2*i+1]
+	}
+	return interleave(c.resamplers[0].Process(left), c.resamplers[1].Process(right))
+}
+
+// flush returns the samples held back by the resamplers.
+func (c *converter) flush() []int16 {
+	if c.channels == 1 {
+		return c.resamplers[0].Flush()
+	}
+	return interleave(c.resamplers[0].Flush(), c.resamplers[1].Flush())
+}
+
+func interleave(left, right []int16) []int16 {
+	n := len(left)
+	if len(right) < n {
+		n = len(right)
+	}
+	samples := make([]int16, 2*n)
+	for i := 0; i < n; i++ {
+		samples[2*i], samples[2*i+1] = left[i], right[i]
+	}
+	return samples
+}
@@ -0,0 +1,321 @@
+package stream
+
+// Copyright (c) 2018 Bhojpur Consulting Private Limited, India. All rights reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"io"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/bhojpur/speech/pkg/audio"
+	"github.com/bhojpur/speech/pkg/auth"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// DropPolicy decides which chunk is lost when the buffer of a listener
+// falling behind the radio is full.
+type DropPolicy int
+
+const (
+	// DropOldest discards the oldest buffered chunk, so that the listener
+	// catches up with the live position.
+	DropOldest DropPolicy = iota
+	// DropNewest discards the chunk being broadcast.
+	DropNewest
+)
+
+const (
+	// DefaultRadioBuffer is the number of chunks buffered for a listener.
+	DefaultRadioBuffer = 32
+	// DefaultRadioRate is the sample rate of the radio.
+	DefaultRadioRate = 44100
+)
+
+// libraryRetry is how long the radio waits for a library holding nothing
+// playable to be filled.
+const libraryRetry = 5 * time.Second
+
+// Listen sends the audio of the radio from its live position.
+func (s *StreamServer) Listen(req *ListenRequest, stream Streamer_ListenServer) error {
+	if s.radio == nil {
+		return status.Error(codes.FailedPrecondition, "the radio is disabled")
+	}
+	format := req.GetFormat()
+	if err := checkFormat(format); err != nil {
+		return err
+	}
+	if format == PayloadFormat_MP3 {
+		return status.Error(codes.InvalidArgument, "the radio does not pass MP3 frames through")
+	}
+	l, nowPlaying := s.radio.subscribe()
+	defer s.radio.unsubscribe(l)
+	if nowPlaying != nil {
+		nowPlaying.Format = format
+		if err := stream.Send(nowPlaying); err != nil {
+			return err
+		}
+	}
+
+	ctx := stream.Context()
+	for {
+		select {
+		case data, ok := <-l.data:
+			if !ok {
+				return status.Error(codes.Unavailable, "the radio stopped")
+			}
+			if len(data.GetData()) > 0 {
+				// the broadcast audio is charged to the account of the listener
+				seconds := float64(len(data.GetData())) / float64(2*data.GetChannels()*data.GetRate())
+				if err := auth.Charge(ctx, seconds); err != nil {
+					return err
+				}
+			}
+			if format != PayloadFormat_LINEAR16_PCM {
+				// the data is shared by all the listeners
+				data = proto.Clone(data).(*Data)
+				data.Data = encode(format, data.Data)
+				data.Format = format
+			}
+			if err := stream.Send(data); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		}
+	}
+}
+
+// radio decodes the library once, in a loop and paced at real time, for all
+// its listeners.
+type radio struct {
+	library  *library
+	rate     int
+	channels int
+	lead     time.Duration
+	buffer   int
+	policy   DropPolicy
+
+	stop      chan struct{}
+	closeOnce sync.Once
+	done      chan struct{}
+
+	mu         sync.Mutex
+	listeners  map[*listener]bool
+	nowPlaying *Data
+}
+
+// listener is the buffer of a Listen call.
+type listener struct {
+	data    chan *Data
+	dropped int64 // guarded by radio.mu
+}
+
+func (r *radio) start() {
+	if r.rate <= 0 {
+		r.rate = DefaultRadioRate
+	}
+	if r.channels != 1 {
+		r.channels = 2
+	}
+	if r.buffer < 1 {
+		r.buffer = DefaultRadioBuffer
+	}
+	r.listeners = map[*listener]bool{}
+	r.stop = make(chan struct{})
+	r.done = make(chan struct{})
+	go r.run()
+}
+
+// close stops the radio and ends the Listen calls.
+func (r *radio) close() {
+	r.closeOnce.Do(func() {
+		close(r.stop)
+	})
+	<-r.done
+}
+
+func (r *radio) subscribe() (*listener, *Data) {
+	l := &listener{data: make(chan *Data, r.buffer)}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.listeners == nil {
+		// the radio has stopped
+		close(l.data)
+		return l, nil
+	}
+	r.listeners[l] = true
+	if r.nowPlaying == nil {
+		return l, nil
+	}
+	return l, proto.Clone(r.nowPlaying).(*Data)
+}
+
+func (r *radio) unsubscribe(l *listener) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.listeners, l)
+}
+
+// run plays the tracks of the library in order, rereading the library at
+// the end of every loop. A loop which plays nothing is followed by a pause.
+func (r *radio) run() {
+	defer func() {
+		r.mu.Lock()
+		for l := range r.listeners {
+			close(l.data)
+		}
+		r.listeners = nil
+		r.mu.Unlock()
+		close(r.done)
+	}()
+
+	// the pacing spans the tracks, so that the radio never drifts
+	start := time.Now()
+	var sent time.Duration
+	for {
+		names, err := r.library.names()
+		if err != nil {
+			log.Printf("radio: %v", err)
+		}
+		played := sent
+		for _, name := range names {
+			t, err := r.library.open(&Track{Name: name})
+			if err != nil {
+				log.Printf("radio: %v", err)
+				continue
+			}
+			ok := r.play(t, start, &sent)
+			t.close()
+			if !ok {
+				return
+			}
+		}
+		if sent == played {
+			// nothing could be played, the library is waited for
+			select {
+			case <-time.After(libraryRetry):
+				// silence is not sent, the pacing starts over
+				start, sent = time.Now(), 0
+			case <-r.stop:
+				return
+			}
+		}
+	}
+}
+
+// play broadcasts a track. It returns false when the radio is stopped.
+func (r *radio) play(t *track, start time.Time, sent *time.Duration) bool {
+	meta := func(position time.Duration) *Data {
+		return &Data{
+			Sequence: t.sequence,
+			Filename: t.name,
+			Rate:     int64(r.rate),
+			Channels: int64(r.channels),
+			Duration: durationpb.New(t.duration()),
+			Position: durationpb.New(position),
+		}
+	}
+	r.broadcast(meta(0))
+
+	c := newConverter(t.rate(), r.rate, r.channels)
+	for {
+		if delay := *sent - r.lead - time.Since(start); delay > 0 {
+			select {
+			case <-time.After(delay):
+			case <-r.stop:
+				return false
+			}
+		} else {
+			select {
+			case <-r.stop:
+				return false
+			default:
+			}
+		}
+
+		position := t.position()
+		stereo, err := t.read(chunkFrames)
+		var samples []int16
+		switch {
+		case err == io.EOF:
+			samples = c.flush()
+		case err != nil:
+			log.Printf("radio: %v", err)
+			samples = c.flush()
+		default:
+			samples = c.process(stereo)
+		}
+		if len(samples) > 0 {
+			data := meta(position)
+			data.Data = audio.Bytes(samples)
+			r.broadcast(data)
+			*sent += time.Duration(len(samples)/r.channels) * time.Second / time.Duration(r.rate)
+		}
+		if err != nil {
+			end := meta(t.duration())
+			end.EndOfTrack = true
+			r.broadcast(end)
+			return true
+		}
+	}
+}
+
+// broadcast queues data for all the listeners, dropping chunks for the
+// ones whose buffer is full. A listener learns how many chunks it missed
+// from the next one it receives.
+func (r *radio) broadcast(data *Data) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	nowPlaying := proto.Clone(data).(*Data)
+	nowPlaying.Data = nil
+	nowPlaying.EndOfTrack = false
+	r.nowPlaying = nowPlaying
+
+	for l := range r.listeners {
+		if len(l.data) == cap(l.data) {
+			if r.policy == DropNewest {
+				l.dropped++
+				continue
+			}
+			select {
+			case oldest := <-l.data:
+				// the chunks the oldest one reported as missed are still missed
+				l.dropped += 1 + oldest.GetDropped()
+			default:
+			}
+		}
+		d := data
+		if l.dropped > 0 {
+			d = proto.Clone(data).(*Data)
+			d.Dropped = l.dropped
+			l.dropped = 0
+		}
+		select {
+		case l.data <- d:
+		default:
+			l.dropped++
+		}
+	}
+}
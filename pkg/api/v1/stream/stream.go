@@ -21,15 +21,15 @@ package stream
 // THE SOFTWARE.
 
 import (
-	"fmt"
-	"io/ioutil"
-	"math/rand"
+	"io"
 	"time"
 
-	"github.com/bhojpur/speech/pkg/mpg123"
-	"github.com/bhojpur/speech/pkg/portaudio"
-	"github.com/bhojpur/speech/pkg/utils"
-	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	"github.com/bhojpur/speech/pkg/audio"
+	"github.com/bhojpur/speech/pkg/auth"
+	"github.com/bhojpur/speech/pkg/wave/g711"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
 )
 
 const (
@@ -37,63 +37,383 @@ const (
 	PORT int    = 4000
 )
 
-type StreamServer struct{}
+const (
+	// DefaultLibrary is the directory of the tracks.
+	DefaultLibrary = "./audios"
+	// DefaultLead is how far ahead of real time the audio is sent.
+	DefaultLead = 500 * time.Millisecond
+)
+
+// chunkFrames is the number of frames of the track sent at once.
+const chunkFrames = 4096
+
+type StreamServer struct {
+	library *library
+	lead    time.Duration
+	radio   *radio
+
+	recordings *recordings
+}
+
+// Option configures a StreamServer.
+type Option func(*StreamServer)
+
+// WithLibrary sets the directory of the MP3 tracks.
+func WithLibrary(dir string) Option {
+	return func(s *StreamServer) {
+		s.library = newLibrary(dir)
+	}
+}
+
+// WithLead sets how far ahead of real time the audio is sent, which is the
+// audio clients have to buffer.
+func WithLead(lead time.Duration) Option {
+	return func(s *StreamServer) {
+		s.lead = lead
+	}
+}
+
+// WithRadio starts the radio of the Listen calls, which plays the library
+// at a sample rate and with a number of channels, DefaultRadioRate and
+// stereo when zero. Every listener buffers up to buffer chunks,
+// DefaultRadioBuffer when zero, and loses chunks according to the policy
+// when it falls behind.
+func WithRadio(rate, channels, buffer int, policy DropPolicy) Option {
+	return func(s *StreamServer) {
+		s.radio = &radio{
+			rate:     rate,
+			channels: channels,
+			buffer:   buffer,
+			policy:   policy,
+		}
+	}
+}
+
+// WithRecordings enables the uploads, stored in a directory. The audio of an
+// upload is limited to maxBytes once decoded, DefaultMaxUpload when zero.
+func WithRecordings(dir string, maxBytes int64) Option {
+	return func(s *StreamServer) {
+		if maxBytes <= 0 {
+			maxBytes = DefaultMaxUpload
+		}
+		s.recordings = &recordings{dir: dir, maxBytes: maxBytes}
+	}
+}
 
-func NewServer() *StreamServer {
-	server := &StreamServer{}
+func NewServer(opts ...Option) *StreamServer {
+	server := &StreamServer{
+		library: newLibrary(DefaultLibrary),
+		lead:    DefaultLead,
+	}
+	for _, opt := range opts {
+		opt(server)
+	}
+	if server.radio != nil {
+		// the radio starts once the library and the lead are known
+		server.radio.library = server.library
+		server.radio.lead = server.lead
+		server.radio.start()
+	}
 	return server
 }
 
-func (s *StreamServer) Audio(empty *emptypb.Empty, a Streamer_AudioServer) error {
-	files, err := ioutil.ReadDir("./audios")
-	utils.Chk(err)
+// Close stops the radio.
+func (s *StreamServer) Close() {
+	if s.radio != nil {
+		s.radio.close()
+	}
+}
+
+// Play streams the track selected by the config, and then follows the
+// controls of the client. The audio is paced at real time, so that a pause
+// stops it right away.
+func (s *StreamServer) Play(stream Streamer_PlayServer) error {
+	req, err := stream.Recv()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	config := req.GetConfig()
+	if config == nil {
+		return status.Error(codes.InvalidArgument, "the first request must carry the config")
+	}
+	if config.GetRate() != 0 && (config.GetRate() < 8000 || config.GetRate() > 192000) {
+		return status.Error(codes.InvalidArgument, "rate must be between 8000 and 192000")
+	}
+	channels := int(config.GetChannels())
+	switch channels {
+	case 0:
+		channels = 2
+	case 1, 2:
+	default:
+		return status.Error(codes.InvalidArgument, "channels must be 1 or 2")
+	}
+	if err := checkFormat(config.GetFormat()); err != nil {
+		return err
+	}
+	if config.GetFormat() == PayloadFormat_MP3 && (config.GetRate() != 0 || config.GetChannels() != 0) {
+		return status.Error(codes.InvalidArgument, "MP3 frames are passed through, their rate and channels can not be changed")
+	}
 
-	rand.Seed(time.Now().UnixNano())
-	randomIndex := rand.Intn(len(files))
-	fmt.Println("Sequence: ", randomIndex+1)
-	file := files[randomIndex]
+	p := &playback{
+		server:   s,
+		stream:   stream,
+		rate:     int(config.GetRate()),
+		channels: channels,
+		format:   config.GetFormat(),
+		paused:   config.GetPaused(),
+	}
+	if err := p.load(config.GetTrack()); err != nil {
+		return err
+	}
+	defer p.track.close()
 
-	fmt.Println("Playing: ", file.Name())
-	// create mpg123 decoder instance
-	decoder, err := mpg123.NewDecoder("")
-	utils.Chk(err)
+	controls := make(chan control)
+	go p.receive(controls)
+
+	ctx := stream.Context()
+	now := make(chan time.Time)
+	close(now)
+	for {
+		if p.ended && controls == nil {
+			return nil
+		}
+		var ready <-chan time.Time
+		var timer *time.Timer
+		if !p.paused && !p.ended {
+			if delay := p.delay(); delay > 0 {
+				timer = time.NewTimer(delay)
+				ready = timer.C
+			} else {
+				ready = now
+			}
+		}
+
+		select {
+		case c := <-controls:
+			if c.err == io.EOF {
+				// the client has no more controls
+				controls = nil
+				continue
+			}
+			if c.err != nil {
+				return c.err
+			}
+			if err := p.apply(c.req); err != nil {
+				return err
+			}
+		case <-ready:
+			if err := p.next(); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+}
+
+func (s *StreamServer) mustEmbedUnimplementedStreamerServer() {}
 
-	utils.Chk(decoder.Open("./audios/" + file.Name()))
-	defer decoder.Close()
+// control is a request received from the client, or the error ending the
+// requests.
+type control struct {
+	req *PlayRequest
+	err error
+}
 
-	// get audio format information
-	rate, channels, _ := decoder.GetFormat()
+// playback is the state of a Play call.
+type playback struct {
+	server   *StreamServer
+	stream   Streamer_PlayServer
+	rate     int // requested, zero for the rate of the track
+	channels int
+	format   PayloadFormat
 
-	// make sure output format does not change
-	decoder.FormatNone()
-	decoder.Format(rate, channels, mpg123.ENC_SIGNED_16)
+	track     *track
+	converter *converter
+	paused    bool
+	ended     bool
 
-	portaudio.Initialize()
-	defer portaudio.Terminate()
-	out := make([]int16, 8192)
-	stream, err := portaudio.OpenDefaultStream(0, channels, float64(rate), len(out), &out)
-	utils.Chk(err)
-	defer stream.Close()
+	// pacing, the audio sent since start
+	start time.Time
+	sent  time.Duration
+}
 
-	utils.Chk(stream.Start())
-	defer stream.Stop()
+// receive passes the requests of the client to the playback until the end
+// of the stream.
+func (p *playback) receive(controls chan<- control) {
 	for {
-		audio := make([]byte, 2*len(out))
-		_, err = decoder.Read(audio)
-		if err == mpg123.EOF {
-			break
+		req, err := p.stream.Recv()
+		select {
+		case controls <- control{req: req, err: err}:
+		case <-p.stream.Context().Done():
+			return
+		}
+		if err != nil {
+			return
 		}
-		utils.Chk(err)
+	}
+}
 
-		a.Send(&Data{
-			Sequence: int32(randomIndex + 1),
-			Filename: file.Name(),
-			Rate:     rate,
-			Channels: int64(channels),
-			Data:     audio,
-		})
+// load switches to the selected track.
+func (p *playback) load(sel *Track) error {
+	t, err := p.server.library.open(sel)
+	if err != nil {
+		return err
+	}
+	if p.track != nil {
+		p.track.close()
 	}
+	p.track = t
+	p.ended = false
+	p.restart()
 	return nil
 }
 
-func (s *StreamServer) mustEmbedUnimplementedStreamerServer() {}
+// restart resets the conversion and the pacing, after the position of the
+// playback changed or the playback resumed.
+func (p *playback) restart() {
+	p.converter = newConverter(p.track.rate(), p.outputRate(), p.channels)
+	p.start = time.Now()
+	p.sent = 0
+}
+
+func (p *playback) outputRate() int {
+	if p.rate != 0 {
+		return p.rate
+	}
+	return p.track.rate()
+}
+
+// delay returns how long to wait before sending the next chunk.
+func (p *playback) delay() time.Duration {
+	return p.sent - p.server.lead - time.Since(p.start)
+}
+
+// apply executes a control request.
+func (p *playback) apply(req *PlayRequest) error {
+	switch r := req.GetRequest().(type) {
+	case *PlayRequest_Select:
+		return p.load(r.Select)
+	case *PlayRequest_Seek:
+		if err := p.track.seek(r.Seek.AsDuration()); err != nil {
+			return err
+		}
+		p.ended = false
+		p.restart()
+	case *PlayRequest_Pause:
+		if p.paused {
+			return nil
+		}
+		p.paused = true
+		return p.send(&Data{Paused: true})
+	case *PlayRequest_Resume:
+		if !p.paused {
+			return nil
+		}
+		p.paused = false
+		p.restart()
+	case *PlayRequest_Config:
+		return status.Error(codes.InvalidArgument, "the config can only be sent first")
+	default:
+		return status.Error(codes.InvalidArgument, "empty request")
+	}
+	return nil
+}
+
+// next sends the next chunk of the track, or the end of the track.
+func (p *playback) next() error {
+	position := p.track.position()
+	if p.format == PayloadFormat_MP3 {
+		data, err := p.track.readMP3(chunkFrames)
+		if err == io.EOF {
+			return p.end()
+		}
+		if err != nil {
+			return err
+		}
+		return p.sendAudio(data, p.track.position()-position, position)
+	}
+
+	stereo, err := p.track.read(chunkFrames)
+	var samples []int16
+	switch {
+	case err == io.EOF:
+		samples = p.converter.flush()
+	case err != nil:
+		return err
+	default:
+		samples = p.converter.process(stereo)
+	}
+	if len(samples) > 0 {
+		duration := time.Duration(len(samples)/p.channels) * time.Second / time.Duration(p.outputRate())
+		if err := p.sendAudio(encode(p.format, audio.Bytes(samples)), duration, position); err != nil {
+			return err
+		}
+	}
+	if err == io.EOF {
+		return p.end()
+	}
+	return nil
+}
+
+// end sends the end of the track.
+func (p *playback) end() error {
+	p.ended = true
+	return p.send(&Data{EndOfTrack: true, Position: durationpb.New(p.track.duration())})
+}
+
+// sendAudio sends a payload lasting duration and starting at a position of
+// the track. The audio is charged to the account of the listener.
+func (p *playback) sendAudio(payload []byte, duration, position time.Duration) error {
+	if err := auth.Charge(p.stream.Context(), duration.Seconds()); err != nil {
+		return err
+	}
+	p.sent += duration
+	return p.send(&Data{
+		Data:     payload,
+		Position: durationpb.New(position),
+	})
+}
+
+// send completes the data with the state of the playback and sends it.
+func (p *playback) send(data *Data) error {
+	data.Sequence = p.track.sequence
+	data.Filename = p.track.name
+	data.Rate = int64(p.outputRate())
+	data.Channels = int64(p.channels)
+	if p.format == PayloadFormat_MP3 {
+		data.Channels = int64(p.track.channels)
+	}
+	data.Format = p.format
+	data.Duration = durationpb.New(p.track.duration())
+	if data.Position == nil {
+		data.Position = durationpb.New(p.track.position())
+	}
+	return p.stream.Send(data)
+}
+
+// checkFormat validates a requested payload format.
+func checkFormat(format PayloadFormat) error {
+	if _, ok := PayloadFormat_name[int32(format)]; !ok {
+		return status.Errorf(codes.InvalidArgument, "unsupported payload format %v", format)
+	}
+	return nil
+}
+
+// encode encodes 16-bit little-endian PCM into a payload format other than
+// MP3.
+func encode(format PayloadFormat, pcm []byte) []byte {
+	switch format {
+	case PayloadFormat_MULAW:
+		return g711.EncodeUlaw(pcm)
+	case PayloadFormat_ALAW:
+		return g711.EncodeAlaw(pcm)
+	}
+	return pcm
+}
@@ -7,7 +7,6 @@ import (
 	grpc "google.golang.org/grpc"
 	codes "google.golang.org/grpc/codes"
 	status "google.golang.org/grpc/status"
-	emptypb "google.golang.org/protobuf/types/known/emptypb"
 )
 
 // This is a compile-time assertion to ensure that this generated file
@@ -19,7 +18,18 @@ const _ = grpc.SupportPackageIsVersion7
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type StreamerClient interface {
-	Audio(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (Streamer_AudioClient, error)
+	// Play streams tracks of the library, paced at real time, under the
+	// control of the client.
+	Play(ctx context.Context, opts ...grpc.CallOption) (Streamer_PlayClient, error)
+	// Listen joins the radio, which plays the library in a loop for all its
+	// listeners at once. The first message and the first one of every
+	// track carry no data but announce the track being played, listeners
+	// joining at the live position.
+	Listen(ctx context.Context, in *ListenRequest, opts ...grpc.CallOption) (Streamer_ListenClient, error)
+	// Upload stores a recording of the client as a WAV file.
+	Upload(ctx context.Context, opts ...grpc.CallOption) (Streamer_UploadClient, error)
+	// ListRecordings returns the recordings uploaded.
+	ListRecordings(ctx context.Context, in *ListRecordingsRequest, opts ...grpc.CallOption) (*ListRecordingsResponse, error)
 }
 
 type streamerClient struct {
@@ -30,12 +40,43 @@ func NewStreamerClient(cc grpc.ClientConnInterface) StreamerClient {
 	return &streamerClient{cc}
 }
 
-func (c *streamerClient) Audio(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (Streamer_AudioClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Streamer_ServiceDesc.Streams[0], "/v1.stream.Streamer/Audio", opts...)
+func (c *streamerClient) Play(ctx context.Context, opts ...grpc.CallOption) (Streamer_PlayClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Streamer_ServiceDesc.Streams[0], "/v1.stream.Streamer/Play", opts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &streamerAudioClient{stream}
+	x := &streamerPlayClient{stream}
+	return x, nil
+}
+
+type Streamer_PlayClient interface {
+	Send(*PlayRequest) error
+	Recv() (*Data, error)
+	grpc.ClientStream
+}
+
+type streamerPlayClient struct {
+	grpc.ClientStream
+}
+
+func (x *streamerPlayClient) Send(m *PlayRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *streamerPlayClient) Recv() (*Data, error) {
+	m := new(Data)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *streamerClient) Listen(ctx context.Context, in *ListenRequest, opts ...grpc.CallOption) (Streamer_ListenClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Streamer_ServiceDesc.Streams[1], "/v1.stream.Streamer/Listen", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &streamerListenClient{stream}
 	if err := x.ClientStream.SendMsg(in); err != nil {
 		return nil, err
 	}
@@ -45,16 +86,16 @@ func (c *streamerClient) Audio(ctx context.Context, in *emptypb.Empty, opts ...g
 	return x, nil
 }
 
-type Streamer_AudioClient interface {
+type Streamer_ListenClient interface {
 	Recv() (*Data, error)
 	grpc.ClientStream
 }
 
-type streamerAudioClient struct {
+type streamerListenClient struct {
 	grpc.ClientStream
 }
 
-func (x *streamerAudioClient) Recv() (*Data, error) {
+func (x *streamerListenClient) Recv() (*Data, error) {
 	m := new(Data)
 	if err := x.ClientStream.RecvMsg(m); err != nil {
 		return nil, err
@@ -62,11 +103,65 @@ func (x *streamerAudioClient) Recv() (*Data, error) {
 	return m, nil
 }
 
+func (c *streamerClient) Upload(ctx context.Context, opts ...grpc.CallOption) (Streamer_UploadClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Streamer_ServiceDesc.Streams[2], "/v1.stream.Streamer/Upload", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &streamerUploadClient{stream}
+	return x, nil
+}
+
+type Streamer_UploadClient interface {
+	Send(*UploadRequest) error
+	CloseAndRecv() (*Recording, error)
+	grpc.ClientStream
+}
+
+type streamerUploadClient struct {
+	grpc.ClientStream
+}
+
+func (x *streamerUploadClient) Send(m *UploadRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *streamerUploadClient) CloseAndRecv() (*Recording, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(Recording)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *streamerClient) ListRecordings(ctx context.Context, in *ListRecordingsRequest, opts ...grpc.CallOption) (*ListRecordingsResponse, error) {
+	out := new(ListRecordingsResponse)
+	err := c.cc.Invoke(ctx, "/v1.stream.Streamer/ListRecordings", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // StreamerServer is the server API for Streamer service.
 // All implementations must embed UnimplementedStreamerServer
 // for forward compatibility
 type StreamerServer interface {
-	Audio(*emptypb.Empty, Streamer_AudioServer) error
+	// Play streams tracks of the library, paced at real time, under the
+	// control of the client.
+	Play(Streamer_PlayServer) error
+	// Listen joins the radio, which plays the library in a loop for all its
+	// listeners at once. The first message and the first one of every
+	// track carry no data but announce the track being played, listeners
+	// joining at the live position.
+	Listen(*ListenRequest, Streamer_ListenServer) error
+	// Upload stores a recording of the client as a WAV file.
+	Upload(Streamer_UploadServer) error
+	// ListRecordings returns the recordings uploaded.
+	ListRecordings(context.Context, *ListRecordingsRequest) (*ListRecordingsResponse, error)
 	mustEmbedUnimplementedStreamerServer()
 }
 
@@ -74,8 +169,17 @@ type StreamerServer interface {
 type UnimplementedStreamerServer struct {
 }
 
-func (UnimplementedStreamerServer) Audio(*emptypb.Empty, Streamer_AudioServer) error {
-	return status.Errorf(codes.Unimplemented, "method Audio not implemented")
+func (UnimplementedStreamerServer) Play(Streamer_PlayServer) error {
+	return status.Errorf(codes.Unimplemented, "method Play not implemented")
+}
+func (UnimplementedStreamerServer) Listen(*ListenRequest, Streamer_ListenServer) error {
+	return status.Errorf(codes.Unimplemented, "method Listen not implemented")
+}
+func (UnimplementedStreamerServer) Upload(Streamer_UploadServer) error {
+	return status.Errorf(codes.Unimplemented, "method Upload not implemented")
+}
+func (UnimplementedStreamerServer) ListRecordings(context.Context, *ListRecordingsRequest) (*ListRecordingsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRecordings not implemented")
 }
 func (UnimplementedStreamerServer) mustEmbedUnimplementedStreamerServer() {}
 
@@ -90,40 +194,126 @@ func RegisterStreamerServer(s grpc.ServiceRegistrar, srv StreamerServer) {
 	s.RegisterService(&Streamer_ServiceDesc, srv)
 }
 
-func _Streamer_Audio_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(emptypb.Empty)
+func _Streamer_Play_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(StreamerServer).Play(&streamerPlayServer{stream})
+}
+
+type Streamer_PlayServer interface {
+	Send(*Data) error
+	Recv() (*PlayRequest, error)
+	grpc.ServerStream
+}
+
+type streamerPlayServer struct {
+	grpc.ServerStream
+}
+
+func (x *streamerPlayServer) Send(m *Data) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *streamerPlayServer) Recv() (*PlayRequest, error) {
+	m := new(PlayRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Streamer_Listen_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListenRequest)
 	if err := stream.RecvMsg(m); err != nil {
 		return err
 	}
-	return srv.(StreamerServer).Audio(m, &streamerAudioServer{stream})
+	return srv.(StreamerServer).Listen(m, &streamerListenServer{stream})
 }
 
-type Streamer_AudioServer interface {
+type Streamer_ListenServer interface {
 	Send(*Data) error
 	grpc.ServerStream
 }
 
-type streamerAudioServer struct {
+type streamerListenServer struct {
+	grpc.ServerStream
+}
+
+func (x *streamerListenServer) Send(m *Data) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Streamer_Upload_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(StreamerServer).Upload(&streamerUploadServer{stream})
+}
+
+type Streamer_UploadServer interface {
+	SendAndClose(*Recording) error
+	Recv() (*UploadRequest, error)
+	grpc.ServerStream
+}
+
+type streamerUploadServer struct {
 	grpc.ServerStream
 }
 
-func (x *streamerAudioServer) Send(m *Data) error {
+func (x *streamerUploadServer) SendAndClose(m *Recording) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func (x *streamerUploadServer) Recv() (*UploadRequest, error) {
+	m := new(UploadRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Streamer_ListRecordings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRecordingsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StreamerServer).ListRecordings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.stream.Streamer/ListRecordings",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StreamerServer).ListRecordings(ctx, req.(*ListRecordingsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Streamer_ServiceDesc is the grpc.ServiceDesc for Streamer service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
 var Streamer_ServiceDesc = grpc.ServiceDesc{
 	ServiceName: "v1.stream.Streamer",
 	HandlerType: (*StreamerServer)(nil),
-	Methods:     []grpc.MethodDesc{},
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListRecordings",
+			Handler:    _Streamer_ListRecordings_Handler,
+		},
+	},
 	Streams: []grpc.StreamDesc{
 		{
-			StreamName:    "Audio",
-			Handler:       _Streamer_Audio_Handler,
+			StreamName:    "Play",
+			Handler:       _Streamer_Play_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "Listen",
+			Handler:       _Streamer_Listen_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "Upload",
+			Handler:       _Streamer_Upload_Handler,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "pkg/api/v1/stream/stream.proto",
 }
@@ -0,0 +1,356 @@
+package stream
+
+// Copyright (c) 2018 Bhojpur Consulting Private Limited, India. All rights reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bhojpur/speech/internal/grpctest"
+	"github.com/bhojpur/speech/pkg/mp3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// testLibrary is the directory of the tracks of the repository.
+const testLibrary = "../../../../audios"
+
+func dialServer(t *testing.T, server *StreamServer, opts ...grpc.ServerOption) StreamerClient {
+	conn := grpctest.Dial(t, func(s *grpc.Server) { RegisterStreamerServer(s, server) }, opts...)
+	return NewStreamerClient(conn)
+}
+
+func play(t *testing.T, client StreamerClient, config *PlayConfig) Streamer_PlayClient {
+	stream, err := client.Play(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, stream.Send(&PlayRequest{Request: &PlayRequest_Config{Config: config}}))
+	return stream
+}
+
+// trackDuration returns the duration of the track of the test library.
+func trackDuration(t *testing.T) time.Duration {
+	tr, err := newLibrary(testLibrary).open(&Track{Index: 1})
+	require.NoError(t, err)
+	defer tr.close()
+	return tr.duration()
+}
+
+func TestPlay(t *testing.T) {
+	client := dialServer(t, NewServer(WithLibrary(testLibrary), WithLead(time.Hour)))
+	duration := trackDuration(t)
+
+	stream := play(t, client, &PlayConfig{
+		Track:    &Track{Name: "chrono.mp3", Position: durationpb.New(duration - 2*time.Second)},
+		Rate:     16000,
+		Channels: 1,
+	})
+	require.NoError(t, stream.CloseSend())
+
+	var samples int
+	var last *Data
+	position := time.Duration(-1)
+	for {
+		data, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), data.GetSequence())
+		assert.Equal(t, "chrono.mp3", data.GetFilename())
+		assert.Equal(t, int64(16000), data.GetRate())
+		assert.Equal(t, int64(1), data.GetChannels())
+		assert.Equal(t, duration, data.GetDuration().AsDuration())
+		assert.GreaterOrEqual(t, data.GetPosition().AsDuration(), position)
+		position = data.GetPosition().AsDuration()
+		samples += len(data.GetData()) / 2
+		last = data
+	}
+	require.NotNil(t, last)
+	assert.True(t, last.GetEndOfTrack())
+	assert.Empty(t, last.GetData())
+	assert.InDelta(t, 2*16000, samples, 16000/10)
+}
+
+func TestPlayInvalid(t *testing.T) {
+	client := dialServer(t, NewServer(WithLibrary(testLibrary), WithLead(time.Hour)))
+
+	for name, tc := range map[string]struct {
+		config *PlayConfig
+		code   codes.Code
+	}{
+		"name":     {&PlayConfig{Track: &Track{Name: "missing.mp3"}}, codes.NotFound},
+		"index":    {&PlayConfig{Track: &Track{Index: 2}}, codes.NotFound},
+		"position": {&PlayConfig{Track: &Track{Index: 1, Position: durationpb.New(time.Hour)}}, codes.OutOfRange},
+		"rate":     {&PlayConfig{Rate: 1000}, codes.InvalidArgument},
+		"channels": {&PlayConfig{Channels: 6}, codes.InvalidArgument},
+	} {
+		_, err := play(t, client, tc.config).Recv()
+		assert.Equal(t, tc.code, status.Code(err), name)
+	}
+
+	stream, err := client.Play(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, stream.Send(&PlayRequest{Request: &PlayRequest_Pause{Pause: &emptypb.Empty{}}}))
+	_, err = stream.Recv()
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestPlayControls(t *testing.T) {
+	client := dialServer(t, NewServer(WithLibrary(testLibrary), WithLead(100*time.Millisecond)))
+	duration := trackDuration(t)
+
+	stream := play(t, client, &PlayConfig{Track: &Track{Index: 1}, Paused: true})
+	resume := &PlayRequest{Request: &PlayRequest_Resume{Resume: &emptypb.Empty{}}}
+	require.NoError(t, stream.Send(resume))
+
+	data, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), data.GetChannels())
+	assert.Equal(t, time.Duration(0), data.GetPosition().AsDuration())
+	assert.NotEmpty(t, data.GetData())
+
+	// the chunks already sent arrive before the acknowledgement
+	require.NoError(t, stream.Send(&PlayRequest{Request: &PlayRequest_Pause{Pause: &emptypb.Empty{}}}))
+	for !data.GetPaused() {
+		data, err = stream.Recv()
+		require.NoError(t, err)
+	}
+	assert.Empty(t, data.GetData())
+
+	require.NoError(t, stream.Send(&PlayRequest{Request: &PlayRequest_Seek{Seek: durationpb.New(10 * time.Second)}}))
+	require.NoError(t, stream.Send(resume))
+	data, err = stream.Recv()
+	require.NoError(t, err)
+	assert.InDelta(t, 10*time.Second, data.GetPosition().AsDuration(), float64(time.Millisecond))
+
+	require.NoError(t, stream.Send(&PlayRequest{Request: &PlayRequest_Select{Select: &Track{
+		Name:     "chrono.mp3",
+		Position: durationpb.New(duration - 200*time.Millisecond),
+	}}}))
+	require.NoError(t, stream.CloseSend())
+	for !data.GetEndOfTrack() {
+		data, err = stream.Recv()
+		require.NoError(t, err)
+	}
+	_, err = stream.Recv()
+	assert.Equal(t, io.EOF, err)
+}
+
+func newRadio(t *testing.T, lead time.Duration, buffer int, policy DropPolicy) StreamerClient {
+	server := NewServer(WithLibrary(testLibrary), WithLead(lead), WithRadio(16000, 1, buffer, policy))
+	t.Cleanup(server.Close)
+	return dialServer(t, server)
+}
+
+func listen(t *testing.T, client StreamerClient, format PayloadFormat) Streamer_ListenClient {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	stream, err := client.Listen(ctx, &ListenRequest{Format: format})
+	require.NoError(t, err)
+	return stream
+}
+
+func TestListen(t *testing.T) {
+	client := newRadio(t, 0, DefaultRadioBuffer, DropOldest)
+
+	first := listen(t, client, PayloadFormat_LINEAR16_PCM)
+	nowPlaying, err := first.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, "chrono.mp3", nowPlaying.GetFilename())
+	assert.Equal(t, int64(16000), nowPlaying.GetRate())
+	assert.Equal(t, int64(1), nowPlaying.GetChannels())
+	assert.Empty(t, nowPlaying.GetData())
+
+	var data *Data
+	for len(data.GetData()) == 0 {
+		data, err = first.Recv()
+		require.NoError(t, err)
+	}
+
+	// a late listener joins at the live position and hears the same chunks
+	second := listen(t, client, PayloadFormat_LINEAR16_PCM)
+	joined, err := second.Recv()
+	require.NoError(t, err)
+	assert.Empty(t, joined.GetData())
+	assert.GreaterOrEqual(t, joined.GetPosition().AsDuration(), data.GetPosition().AsDuration())
+
+	late, err := second.Recv()
+	require.NoError(t, err)
+	for data.GetPosition().AsDuration() < late.GetPosition().AsDuration() {
+		data, err = first.Recv()
+		require.NoError(t, err)
+	}
+	assert.Equal(t, late.GetPosition().AsDuration(), data.GetPosition().AsDuration())
+	assert.Equal(t, late.GetData(), data.GetData())
+	assert.Zero(t, late.GetDropped())
+}
+
+func TestRadioDrops(t *testing.T) {
+	for policy, kept := range map[DropPolicy][]int32{
+		DropOldest: {4, 5},
+		DropNewest: {1, 2},
+	} {
+		r := &radio{buffer: 2, policy: policy}
+		r.listeners = map[*listener]bool{}
+		l, _ := r.subscribe()
+		for i := 1; i <= 5; i++ {
+			r.broadcast(&Data{Sequence: int32(i)})
+		}
+
+		first, second := <-l.data, <-l.data
+		assert.Equal(t, kept, []int32{first.GetSequence(), second.GetSequence()}, policy)
+		assert.Equal(t, int64(3), first.GetDropped()+second.GetDropped()+l.dropped, policy)
+
+		// the next chunk tells how many were missed
+		r.broadcast(&Data{Sequence: 6})
+		if policy == DropNewest {
+			assert.Equal(t, int64(3), (<-l.data).GetDropped(), policy)
+		} else {
+			assert.Equal(t, int64(0), (<-l.data).GetDropped(), policy)
+		}
+	}
+}
+
+func TestRadioUnplayableLibrary(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "broken.mp3"), []byte("not an mp3"), 0644))
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	server := NewServer(WithLibrary(dir), WithRadio(16000, 1, DefaultRadioBuffer, DropOldest))
+	time.Sleep(100 * time.Millisecond)
+	server.Close()
+	// the library is not reread before the retry delay
+	assert.Equal(t, 1, strings.Count(logs.String(), "radio:"), logs.String())
+}
+
+func TestListenDisabled(t *testing.T) {
+	client := dialServer(t, NewServer(WithLibrary(testLibrary)))
+	_, err := listen(t, client, PayloadFormat_LINEAR16_PCM).Recv()
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func TestPlayFormats(t *testing.T) {
+	client := dialServer(t, NewServer(WithLibrary(testLibrary), WithLead(time.Hour)))
+	duration := trackDuration(t)
+	start := durationpb.New(duration - time.Second)
+
+	stream := play(t, client, &PlayConfig{
+		Track:    &Track{Index: 1, Position: start},
+		Rate:     8000,
+		Channels: 1,
+		Format:   PayloadFormat_MULAW,
+	})
+	require.NoError(t, stream.CloseSend())
+	var size int
+	for {
+		data, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		assert.Equal(t, PayloadFormat_MULAW, data.GetFormat())
+		size += len(data.GetData())
+	}
+	// a byte per sample
+	assert.InDelta(t, 8000, size, 800)
+
+	stream = play(t, client, &PlayConfig{Track: &Track{Index: 1, Position: start}, Format: PayloadFormat_MP3})
+	require.NoError(t, stream.CloseSend())
+	var decoder mp3.FrameDecoder
+	var pcm []byte
+	var last *Data
+	for {
+		data, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		assert.Equal(t, PayloadFormat_MP3, data.GetFormat())
+		last = data
+		if len(data.GetData()) == 0 {
+			continue
+		}
+		// the chunks hold whole frames
+		reader := mp3.NewFrameReader(bytes.NewReader(data.GetData()))
+		for {
+			frame, err := reader.ReadFrame()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+			assert.Equal(t, int(data.GetRate()), frame.SampleRate)
+			assert.Equal(t, int(data.GetChannels()), frame.Channels)
+			out, err := decoder.Decode(frame.Data)
+			require.NoError(t, err)
+			pcm = append(pcm, out...)
+		}
+	}
+	require.NotNil(t, last)
+	assert.True(t, last.GetEndOfTrack())
+	assert.InDelta(t, last.GetRate(), len(pcm)/4, float64(last.GetRate())/10)
+
+	_, err := play(t, client, &PlayConfig{Rate: 16000, Format: PayloadFormat_MP3}).Recv()
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestListenFormats(t *testing.T) {
+	client := newRadio(t, 0, DefaultRadioBuffer, DropOldest)
+
+	_, err := listen(t, client, PayloadFormat_MP3).Recv()
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	stream := listen(t, client, PayloadFormat_ALAW)
+	var data *Data
+	for len(data.GetData()) == 0 {
+		data, err = stream.Recv()
+		require.NoError(t, err)
+		assert.Equal(t, PayloadFormat_ALAW, data.GetFormat())
+	}
+	// a byte per sample of the mono radio
+	assert.InDelta(t, chunkFrames*16000/44100, len(data.GetData()), 32)
+}
+
+func TestPlayAtEnd(t *testing.T) {
+	client := dialServer(t, NewServer(WithLibrary(testLibrary), WithLead(time.Hour)))
+	duration := trackDuration(t)
+
+	stream := play(t, client, &PlayConfig{Track: &Track{Index: 1, Position: durationpb.New(duration)}})
+	require.NoError(t, stream.CloseSend())
+	data, err := stream.Recv()
+	require.NoError(t, err)
+	assert.True(t, data.GetEndOfTrack())
+	_, err = stream.Recv()
+	assert.Equal(t, io.EOF, err)
+}
@@ -0,0 +1,338 @@
+package stream
+
+// Copyright (c) 2018 Bhojpur Consulting Private Limited, India. All rights reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bhojpur/speech/pkg/auth"
+	"github.com/bhojpur/speech/pkg/wave"
+	"github.com/bhojpur/speech/pkg/wave/g711"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// DefaultMaxUpload is the default size limit of the audio of an upload,
+// once decoded to 16-bit PCM.
+const DefaultMaxUpload = 100 << 20
+
+// maxWavData is the largest data chunk of a WAV file.
+const maxWavData = math.MaxUint32 - 36
+
+// recordings is the directory storing the uploads. Every recording is a WAV
+// file named after its ID, with a JSON sidecar holding its Recording.
+type recordings struct {
+	dir      string
+	maxBytes int64
+}
+
+// Upload stores the audio of the client as a WAV file, and its metadata in
+// a sidecar. The sidecar is written last, so that a recording is only
+// listed once complete.
+func (s *StreamServer) Upload(stream Streamer_UploadServer) error {
+	if s.recordings == nil {
+		return status.Error(codes.FailedPrecondition, "uploads are disabled")
+	}
+	req, err := stream.Recv()
+	if err == io.EOF {
+		return status.Error(codes.InvalidArgument, "the upload has no metadata")
+	}
+	if err != nil {
+		return err
+	}
+	metadata := req.GetMetadata()
+	if metadata == nil {
+		return status.Error(codes.InvalidArgument, "the first request must carry the metadata")
+	}
+	if err := checkMetadata(metadata); err != nil {
+		return err
+	}
+
+	u, err := s.recordings.create(owner(stream.Context()), metadata)
+	if err != nil {
+		return err
+	}
+	defer u.discard()
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if req.GetMetadata() != nil {
+			return status.Error(codes.InvalidArgument, "the metadata can only be sent first")
+		}
+		if err := u.write(req.GetAudio()); err != nil {
+			return err
+		}
+	}
+	recording, err := u.store()
+	if err != nil {
+		return err
+	}
+	return stream.SendAndClose(recording)
+}
+
+// ListRecordings returns the stored recordings of the account of the call
+// matching the filters of the request.
+func (s *StreamServer) ListRecordings(ctx context.Context, req *ListRecordingsRequest) (*ListRecordingsResponse, error) {
+	if s.recordings == nil {
+		return nil, status.Error(codes.FailedPrecondition, "uploads are disabled")
+	}
+	all, err := s.recordings.list()
+	if err != nil {
+		return nil, err
+	}
+	account := owner(ctx)
+	res := &ListRecordingsResponse{}
+	for _, r := range all {
+		if r.GetAccount() == account && matches(r, req) {
+			res.Recordings = append(res.Recordings, r)
+		}
+	}
+	return res, nil
+}
+
+// owner returns the account of a call, empty when the server does not
+// authenticate the calls.
+func owner(ctx context.Context) string {
+	if account, ok := auth.FromContext(ctx); ok {
+		return account.Name()
+	}
+	return ""
+}
+
+func checkMetadata(metadata *RecordingMetadata) error {
+	if metadata.GetSubjectId() == "" {
+		return status.Error(codes.InvalidArgument, "subject_id is required")
+	}
+	if rate := metadata.GetSampleRateHertz(); rate < 8000 || rate > 192000 {
+		return status.Error(codes.InvalidArgument, "sample_rate_hertz must be between 8000 and 192000")
+	}
+	if channels := metadata.GetChannels(); channels != 1 && channels != 2 {
+		return status.Error(codes.InvalidArgument, "channels must be 1 or 2")
+	}
+	if err := checkFormat(metadata.GetEncoding()); err != nil {
+		return err
+	}
+	if metadata.GetEncoding() == PayloadFormat_MP3 {
+		return status.Error(codes.InvalidArgument, "MP3 uploads are not supported")
+	}
+	return nil
+}
+
+func matches(r *Recording, req *ListRecordingsRequest) bool {
+	metadata := r.GetMetadata()
+	switch {
+	case req.GetId() != "" && req.GetId() != r.GetId():
+		return false
+	case req.GetSubjectId() != "" && req.GetSubjectId() != metadata.GetSubjectId():
+		return false
+	case req.GetSession() != "" && req.GetSession() != metadata.GetSession():
+		return false
+	}
+	for key, value := range req.GetTags() {
+		if v, ok := metadata.GetTags()[key]; !ok || v != value {
+			return false
+		}
+	}
+	return true
+}
+
+// upload is a recording being received. Its audio is decoded to 16-bit
+// PCM into a temporary file, since the WAV header needs its size.
+type upload struct {
+	dir       string
+	maxBytes  int64
+	recording *Recording
+	pcm       *os.File
+	size      int64 // of the PCM written
+}
+
+func (r *recordings) create(account string, metadata *RecordingMetadata) (*upload, error) {
+	if err := os.MkdirAll(r.dir, 0o755); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create the recordings directory: %v", err)
+	}
+	id, err := newRecordingID()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create recording id: %v", err)
+	}
+	pcm, err := ioutil.TempFile(r.dir, ".upload-*.pcm")
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create upload: %v", err)
+	}
+	return &upload{
+		dir:      r.dir,
+		maxBytes: r.maxBytes,
+		recording: &Recording{
+			Id:         id,
+			Metadata:   metadata,
+			CreateTime: timestamppb.Now(),
+			Account:    account,
+		},
+		pcm: pcm,
+	}, nil
+}
+
+// write decodes and appends audio, failing once the limit is exceeded.
+func (u *upload) write(audio []byte) error {
+	switch u.recording.GetMetadata().GetEncoding() {
+	case PayloadFormat_MULAW:
+		audio = g711.DecodeUlaw(audio)
+	case PayloadFormat_ALAW:
+		audio = g711.DecodeAlaw(audio)
+	}
+	if u.size+int64(len(audio)) > u.maxBytes || u.size+int64(len(audio)) > maxWavData {
+		return status.Errorf(codes.ResourceExhausted, "the upload exceeds %d bytes of audio", u.maxBytes)
+	}
+	if _, err := u.pcm.Write(audio); err != nil {
+		return status.Errorf(codes.Internal, "failed to write upload: %v", err)
+	}
+	u.size += int64(len(audio))
+	return nil
+}
+
+// store writes the WAV file and its sidecar, and returns the recording.
+func (u *upload) store() (*Recording, error) {
+	metadata := u.recording.GetMetadata()
+	blockAlign := 2 * int64(metadata.GetChannels())
+	if u.size%blockAlign != 0 {
+		return nil, status.Error(codes.InvalidArgument, "the audio ends with a partial sample")
+	}
+	if _, err := u.pcm.Seek(0, io.SeekStart); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to read upload: %v", err)
+	}
+
+	wav, err := ioutil.TempFile(u.dir, ".upload-*.wav")
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create recording: %v", err)
+	}
+	defer os.Remove(wav.Name())
+	defer wav.Close()
+	hash := sha256.New()
+	out := bufio.NewWriter(io.MultiWriter(wav, hash))
+	w := wave.NewWriter(out, uint32(u.size/blockAlign), uint16(metadata.GetChannels()), uint32(metadata.GetSampleRateHertz()), 16)
+	if _, err := io.Copy(w, u.pcm); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to write recording: %v", err)
+	}
+	if err := out.Flush(); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to write recording: %v", err)
+	}
+	if err := wav.Close(); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to write recording: %v", err)
+	}
+
+	r := u.recording
+	r.Duration = durationpb.New(time.Duration(u.size/blockAlign) * time.Second / time.Duration(metadata.GetSampleRateHertz()))
+	r.SizeBytes = 44 + u.size
+	r.Sha256 = hex.EncodeToString(hash.Sum(nil))
+	if err := os.Rename(wav.Name(), filepath.Join(u.dir, r.GetId()+".wav")); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to store recording: %v", err)
+	}
+	if err := writeSidecar(filepath.Join(u.dir, r.GetId()+".json"), r); err != nil {
+		os.Remove(filepath.Join(u.dir, r.GetId()+".wav"))
+		return nil, err
+	}
+	return r, nil
+}
+
+// discard removes the temporary file of the upload.
+func (u *upload) discard() {
+	u.pcm.Close()
+	os.Remove(u.pcm.Name())
+}
+
+// writeSidecar writes the recording atomically.
+func writeSidecar(path string, r *Recording) error {
+	data, err := protojson.MarshalOptions{Multiline: true}.Marshal(r)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to encode recording: %v", err)
+	}
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0o644); err != nil {
+		os.Remove(tmp)
+		return status.Errorf(codes.Internal, "failed to store recording: %v", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return status.Errorf(codes.Internal, "failed to store recording: %v", err)
+	}
+	return nil
+}
+
+// list reads the sidecars of the recordings, sorted by creation time.
+func (r *recordings) list() ([]*Recording, error) {
+	files, err := ioutil.ReadDir(r.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to read the recordings: %v", err)
+	}
+	var all []*Recording
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), ".json") {
+			continue
+		}
+		data, err := ioutil.ReadFile(filepath.Join(r.dir, file.Name()))
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to read recording: %v", err)
+		}
+		recording := &Recording{}
+		if err := protojson.Unmarshal(data, recording); err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to decode %s: %v", file.Name(), err)
+		}
+		all = append(all, recording)
+	}
+	sort.SliceStable(all, func(i, j int) bool {
+		a, b := all[i].GetCreateTime().AsTime(), all[j].GetCreateTime().AsTime()
+		if a.Equal(b) {
+			return all[i].GetId() < all[j].GetId()
+		}
+		return a.Before(b)
+	})
+	return all, nil
+}
+
+func newRecordingID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,254 @@
+package stream
+
+// Copyright (c) 2018 Bhojpur Consulting Private Limited, India. All rights reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bhojpur/speech/pkg/audio"
+	"github.com/bhojpur/speech/pkg/auth"
+	"github.com/bhojpur/speech/pkg/wave"
+	"github.com/bhojpur/speech/pkg/wave/g711"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// uploadRecording sends the metadata and the chunks, and returns the outcome.
+func uploadRecording(t *testing.T, client StreamerClient, metadata *RecordingMetadata, chunks ...[]byte) (*Recording, error) {
+	return uploadRecordingAs(context.Background(), t, client, metadata, chunks...)
+}
+
+// uploadRecordingAs uploads a recording with the metadata of ctx.
+func uploadRecordingAs(ctx context.Context, t *testing.T, client StreamerClient, metadata *RecordingMetadata, chunks ...[]byte) (*Recording, error) {
+	stream, err := client.Upload(ctx)
+	require.NoError(t, err)
+	if metadata != nil {
+		require.NoError(t, stream.Send(&UploadRequest{Request: &UploadRequest_Metadata{Metadata: metadata}}))
+	}
+	for _, chunk := range chunks {
+		if err := stream.Send(&UploadRequest{Request: &UploadRequest_Audio{Audio: chunk}}); err != nil {
+			break
+		}
+	}
+	return stream.CloseAndRecv()
+}
+
+func ramp(n int) []int16 {
+	samples := make([]int16, n)
+	for i := range samples {
+		samples[i] = int16(i * 7)
+	}
+	return samples
+}
+
+func TestUpload(t *testing.T) {
+	dir := t.TempDir()
+	client := dialServer(t, NewServer(WithRecordings(dir, 0)))
+
+	metadata := &RecordingMetadata{
+		SubjectId:       "patient-1",
+		Session:         "morning",
+		SampleRateHertz: 16000,
+		Channels:        2,
+		Tags:            map[string]string{"device": "tablet"},
+	}
+	pcm := audio.Bytes(ramp(32000))
+	// the chunks need not hold whole samples
+	recording, err := uploadRecording(t, client, metadata, pcm[:1001], pcm[1001:40001], pcm[40001:])
+	require.NoError(t, err)
+	assert.Len(t, recording.GetId(), 32)
+	assert.True(t, proto.Equal(metadata, recording.GetMetadata()))
+	assert.Equal(t, "1s", recording.GetDuration().AsDuration().String())
+	assert.Equal(t, int64(44+len(pcm)), recording.GetSizeBytes())
+
+	// the WAV file holds the audio and matches the checksum
+	data, err := ioutil.ReadFile(filepath.Join(dir, recording.GetId()+".wav"))
+	require.NoError(t, err)
+	sum := sha256.Sum256(data)
+	assert.Equal(t, hex.EncodeToString(sum[:]), recording.GetSha256())
+	file, err := os.Open(filepath.Join(dir, recording.GetId()+".wav"))
+	require.NoError(t, err)
+	defer file.Close()
+	format, size, err := wave.ReadHeader(file)
+	require.NoError(t, err)
+	assert.Equal(t, &wave.WavFormat{
+		AudioFormat:   wave.AudioFormatPCM,
+		NumChannels:   2,
+		SampleRate:    16000,
+		ByteRate:      64000,
+		BlockAlign:    4,
+		BitsPerSample: 16,
+	}, format)
+	assert.Equal(t, uint32(len(pcm)), size)
+	stored, err := ioutil.ReadAll(file)
+	require.NoError(t, err)
+	assert.True(t, assert.ObjectsAreEqual(pcm, stored), "the stored audio differs")
+
+	// the sidecar is listed, and no temporary file is left
+	res, err := client.ListRecordings(context.Background(), &ListRecordingsRequest{Id: recording.GetId()})
+	require.NoError(t, err)
+	require.Len(t, res.GetRecordings(), 1)
+	assert.True(t, proto.Equal(recording, res.GetRecordings()[0]))
+	files, err := filepath.Glob(filepath.Join(dir, "*"))
+	require.NoError(t, err)
+	assert.Len(t, files, 2)
+}
+
+func TestUploadG711(t *testing.T) {
+	client := dialServer(t, NewServer(WithRecordings(t.TempDir(), 0)))
+	pcm := audio.Bytes(ramp(8000))
+
+	for _, test := range []struct {
+		encoding PayloadFormat
+		encode   func([]byte) []byte
+	}{
+		{PayloadFormat_MULAW, g711.EncodeUlaw},
+		{PayloadFormat_ALAW, g711.EncodeAlaw},
+	} {
+		t.Run(test.encoding.String(), func(t *testing.T) {
+			recording, err := uploadRecording(t, client, &RecordingMetadata{
+				SubjectId:       "patient-1",
+				SampleRateHertz: 8000,
+				Channels:        1,
+				Encoding:        test.encoding,
+			}, test.encode(pcm))
+			require.NoError(t, err)
+			// stored as 16-bit PCM
+			assert.Equal(t, int64(44+len(pcm)), recording.GetSizeBytes())
+			assert.Equal(t, "1s", recording.GetDuration().AsDuration().String())
+		})
+	}
+}
+
+func TestUploadInvalid(t *testing.T) {
+	client := dialServer(t, NewServer(WithRecordings(t.TempDir(), 100)))
+	valid := &RecordingMetadata{SubjectId: "patient-1", SampleRateHertz: 8000, Channels: 1}
+
+	for name, test := range map[string]struct {
+		metadata *RecordingMetadata
+		chunks   [][]byte
+		code     codes.Code
+	}{
+		"no metadata":  {nil, nil, codes.InvalidArgument},
+		"no subject":   {&RecordingMetadata{SampleRateHertz: 8000, Channels: 1}, nil, codes.InvalidArgument},
+		"bad rate":     {&RecordingMetadata{SubjectId: "s", SampleRateHertz: 4000, Channels: 1}, nil, codes.InvalidArgument},
+		"bad channels": {&RecordingMetadata{SubjectId: "s", SampleRateHertz: 8000, Channels: 3}, nil, codes.InvalidArgument},
+		"mp3":          {&RecordingMetadata{SubjectId: "s", SampleRateHertz: 8000, Channels: 1, Encoding: PayloadFormat_MP3}, nil, codes.InvalidArgument},
+		"partial":      {valid, [][]byte{make([]byte, 5)}, codes.InvalidArgument},
+		"too large":    {valid, [][]byte{make([]byte, 60), make([]byte, 60)}, codes.ResourceExhausted},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := uploadRecording(t, client, test.metadata, test.chunks...)
+			assert.Equal(t, test.code, status.Code(err))
+		})
+	}
+
+	// nothing is stored
+	res, err := client.ListRecordings(context.Background(), &ListRecordingsRequest{})
+	require.NoError(t, err)
+	assert.Empty(t, res.GetRecordings())
+}
+
+func TestListRecordings(t *testing.T) {
+	client := dialServer(t, NewServer(WithRecordings(t.TempDir(), 0)))
+
+	// an empty directory lists nothing
+	res, err := client.ListRecordings(context.Background(), &ListRecordingsRequest{})
+	require.NoError(t, err)
+	assert.Empty(t, res.GetRecordings())
+
+	var ids []string
+	for _, metadata := range []*RecordingMetadata{
+		{SubjectId: "a", Session: "1", Tags: map[string]string{"device": "tablet"}},
+		{SubjectId: "a", Session: "2"},
+		{SubjectId: "b", Session: "1", Tags: map[string]string{"device": "tablet", "room": "3"}},
+	} {
+		metadata.SampleRateHertz, metadata.Channels = 8000, 1
+		recording, err := uploadRecording(t, client, metadata, make([]byte, 16))
+		require.NoError(t, err)
+		ids = append(ids, recording.GetId())
+	}
+
+	for name, test := range map[string]struct {
+		req  *ListRecordingsRequest
+		want []string
+	}{
+		"all":     {&ListRecordingsRequest{}, ids},
+		"id":      {&ListRecordingsRequest{Id: ids[1]}, ids[1:2]},
+		"subject": {&ListRecordingsRequest{SubjectId: "a"}, ids[:2]},
+		"session": {&ListRecordingsRequest{Session: "1"}, []string{ids[0], ids[2]}},
+		"tags":    {&ListRecordingsRequest{Tags: map[string]string{"device": "tablet", "room": "3"}}, ids[2:]},
+		"none":    {&ListRecordingsRequest{SubjectId: "a", Tags: map[string]string{"room": "3"}}, nil},
+	} {
+		t.Run(name, func(t *testing.T) {
+			res, err := client.ListRecordings(context.Background(), test.req)
+			require.NoError(t, err)
+			var got []string
+			for _, r := range res.GetRecordings() {
+				got = append(got, r.GetId())
+			}
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestListRecordingsAccount(t *testing.T) {
+	guard := auth.New([]auth.Key{{Name: "alice", Key: "alice-key"}, {Name: "bob", Key: "bob-key"}})
+	client := dialServer(t, NewServer(WithRecordings(t.TempDir(), 0)),
+		grpc.ChainUnaryInterceptor(guard.UnaryInterceptor()),
+		grpc.ChainStreamInterceptor(guard.StreamInterceptor()),
+	)
+	alice := metadata.AppendToOutgoingContext(context.Background(), auth.APIKeyHeader, "alice-key")
+	bob := metadata.AppendToOutgoingContext(context.Background(), auth.APIKeyHeader, "bob-key")
+
+	recording, err := uploadRecordingAs(alice, t, client, &RecordingMetadata{SubjectId: "a", SampleRateHertz: 8000, Channels: 1}, make([]byte, 16))
+	require.NoError(t, err)
+	assert.Equal(t, "alice", recording.GetAccount())
+
+	// the recordings of other accounts are not listed, even by id
+	res, err := client.ListRecordings(bob, &ListRecordingsRequest{Id: recording.GetId()})
+	require.NoError(t, err)
+	assert.Empty(t, res.GetRecordings())
+
+	res, err = client.ListRecordings(alice, &ListRecordingsRequest{})
+	require.NoError(t, err)
+	require.Len(t, res.GetRecordings(), 1)
+	assert.Equal(t, recording.GetId(), res.GetRecordings()[0].GetId())
+}
+
+func TestUploadDisabled(t *testing.T) {
+	client := dialServer(t, NewServer())
+	_, err := uploadRecording(t, client, &RecordingMetadata{SubjectId: "s", SampleRateHertz: 8000, Channels: 1})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	_, err = client.ListRecordings(context.Background(), &ListRecordingsRequest{})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
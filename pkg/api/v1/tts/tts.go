@@ -0,0 +1,319 @@
+package tts
+
+// Copyright (c) 2018 Bhojpur Consulting Private Limited, India. All rights reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bhojpur/speech/pkg/audio"
+	"github.com/bhojpur/speech/pkg/auth"
+	"github.com/bhojpur/speech/pkg/espeak/wav"
+	"github.com/bhojpur/speech/pkg/metrics"
+	"github.com/bhojpur/speech/pkg/tts"
+	"github.com/bhojpur/speech/pkg/wave/g711"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+const (
+	ADDR string = "localhost"
+	PORT int    = 4003
+)
+
+// DefaultMaxTextLength bounds the length of the texts to synthesize.
+const DefaultMaxTextLength = 5000
+
+// TtsServer implements the TtsService on top of a speech synthesis engine.
+type TtsServer struct {
+	engine        tts.Engine
+	maxTextLength int
+}
+
+// Option configures a TtsServer.
+type Option func(*TtsServer)
+
+// WithMaxTextLength bounds the length, in bytes, of the texts to
+// synthesize.
+func WithMaxTextLength(n int) Option {
+	return func(s *TtsServer) {
+		s.maxTextLength = n
+	}
+}
+
+// NewServer creates a server synthesizing speech with the engine.
+func NewServer(engine tts.Engine, opts ...Option) *TtsServer {
+	s := &TtsServer{
+		engine:        engine,
+		maxTextLength: DefaultMaxTextLength,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// ListVoices lists the voices of the engine matching the request.
+func (s *TtsServer) ListVoices(ctx context.Context, req *ListVoicesRequest) (*ListVoicesResponse, error) {
+	voices, err := s.engine.Voices(tts.VoiceSpec{
+		Language: req.GetLanguageCode(),
+		Gender:   tts.Gender(req.GetGender()),
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list voices: %v", err)
+	}
+	res := &ListVoicesResponse{}
+	for _, v := range voices {
+		res.Voices = append(res.Voices, voiceOf(v))
+	}
+	return res, nil
+}
+
+// Synthesize synthesizes the whole text, as WAV unless another encoding is
+// requested.
+func (s *TtsServer) Synthesize(ctx context.Context, req *SynthesizeRequest) (*SynthesizeResponse, error) {
+	encoding := req.GetAudioEncoding()
+	if encoding == AudioEncoding_AUDIO_ENCODING_UNSPECIFIED {
+		encoding = AudioEncoding_WAV
+	}
+	enc, err := s.newEncoder(req, encoding)
+	if err != nil {
+		return nil, err
+	}
+
+	var content bytes.Buffer
+	voice, err := s.synthesize(ctx, req, func(samples []int16) error {
+		content.Write(enc.encode(samples))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	content.Write(enc.flush())
+
+	data := content.Bytes()
+	if encoding == AudioEncoding_WAV {
+		var file bytes.Buffer
+		if _, err := wav.NewWriter(&file, int32(enc.sampleRate)).WriteSamples(audio.Int16s(data)); err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to write WAV: %v", err)
+		}
+		data = file.Bytes()
+	}
+	return &SynthesizeResponse{
+		AudioContent:    data,
+		AudioEncoding:   encoding,
+		SampleRateHertz: int64(enc.sampleRate),
+		Duration:        durationpb.New(enc.duration()),
+		Voice:           voiceOf(voice),
+	}, nil
+}
+
+// StreamingSynthesize sends the audio as soon as the engine produces it.
+func (s *TtsServer) StreamingSynthesize(req *SynthesizeRequest, stream TtsService_StreamingSynthesizeServer) error {
+	encoding := req.GetAudioEncoding()
+	switch encoding {
+	case AudioEncoding_AUDIO_ENCODING_UNSPECIFIED:
+		encoding = AudioEncoding_LINEAR16_PCM
+	case AudioEncoding_WAV:
+		return status.Error(codes.InvalidArgument, "WAV can not be streamed, use LINEAR16_PCM")
+	}
+	enc, err := s.newEncoder(req, encoding)
+	if err != nil {
+		return err
+	}
+
+	send := func(data []byte) error {
+		if len(data) == 0 {
+			return nil
+		}
+		return stream.Send(&SynthesizeResponse{
+			AudioContent:    data,
+			AudioEncoding:   encoding,
+			SampleRateHertz: int64(enc.sampleRate),
+			Duration:        durationpb.New(enc.duration()),
+		})
+	}
+	voice, err := s.synthesize(stream.Context(), req, func(samples []int16) error {
+		return send(enc.encode(samples))
+	})
+	if err != nil {
+		return err
+	}
+	if err := send(enc.flush()); err != nil {
+		return err
+	}
+	// the voice is only known once the synthesis succeeded
+	return stream.Send(&SynthesizeResponse{
+		AudioEncoding:   encoding,
+		SampleRateHertz: int64(enc.sampleRate),
+		Duration:        durationpb.New(enc.duration()),
+		Voice:           voiceOf(voice),
+	})
+}
+
+func (s *TtsServer) mustEmbedUnimplementedTtsServiceServer() {}
+
+// synthesize validates the request and synthesizes its text, passing the
+// samples to fn. The synthesized audio is charged to the account of the
+// call.
+func (s *TtsServer) synthesize(ctx context.Context, req *SynthesizeRequest, fn func([]int16) error) (tts.Voice, error) {
+	if len(req.GetText()) == 0 {
+		return tts.Voice{}, status.Error(codes.InvalidArgument, "text is empty")
+	}
+	if s.maxTextLength > 0 && len(req.GetText()) > s.maxTextLength {
+		return tts.Voice{}, status.Errorf(codes.InvalidArgument, "text longer than %d bytes", s.maxTextLength)
+	}
+	params, err := parametersOf(req)
+	if err != nil {
+		return tts.Voice{}, err
+	}
+
+	start := time.Now()
+	rate := float64(s.engine.SampleRate())
+	voice, err := s.engine.Synthesize(req.GetText(), voiceSpecOf(req.GetVoice()), params, func(samples []int16) error {
+		if err := ctx.Err(); err != nil {
+			return status.FromContextError(err).Err()
+		}
+		if err := auth.Charge(ctx, float64(len(samples))/rate); err != nil {
+			return err
+		}
+		return fn(samples)
+	})
+	switch {
+	case errors.Is(err, tts.ErrEmptyText):
+		return tts.Voice{}, status.Error(codes.InvalidArgument, "text is empty")
+	case errors.Is(err, tts.ErrNoVoice):
+		return tts.Voice{}, status.Error(codes.NotFound, "no voice matches the selection")
+	case err != nil:
+		if _, ok := status.FromError(err); ok {
+			return tts.Voice{}, err
+		}
+		return tts.Voice{}, status.Errorf(codes.Internal, "failed to synthesize speech: %v", err)
+	}
+	metrics.Since(metrics.SynthesisSeconds.WithLabelValues(s.engine.Name()), start)
+	return voice, nil
+}
+
+// parametersOf validates the synthesis parameters of a request.
+func parametersOf(req *SynthesizeRequest) (tts.Parameters, error) {
+	params := tts.DefaultParameters
+	params.SSML = req.GetSsml()
+	p := req.GetParameters()
+	if p == nil {
+		p = &SynthesisParameters{}
+	}
+	for _, field := range []struct {
+		name     string
+		value    *int32
+		min, max int32
+		target   *int
+	}{
+		{"rate", p.Rate, 80, 450, &params.Rate},
+		{"pitch", p.Pitch, 0, 100, &params.Pitch},
+		{"volume", p.Volume, 0, 200, &params.Volume},
+		{"range", p.Range, 0, 100, &params.Range},
+		{"word_gap", p.WordGap, 0, 1000, &params.WordGap},
+	} {
+		if field.value == nil {
+			continue
+		}
+		if *field.value < field.min || *field.value > field.max {
+			return params, status.Errorf(codes.InvalidArgument, "%s must be between %d and %d", field.name, field.min, field.max)
+		}
+		*field.target = int(*field.value)
+	}
+	return params, nil
+}
+
+func voiceSpecOf(sel *VoiceSelection) tts.VoiceSpec {
+	return tts.VoiceSpec{
+		Name:     sel.GetName(),
+		Language: sel.GetLanguageCode(),
+		Gender:   tts.Gender(sel.GetGender()),
+		Age:      int(sel.GetAge()),
+		Variant:  int(sel.GetVariant()),
+	}
+}
+
+func voiceOf(v tts.Voice) *Voice {
+	return &Voice{
+		Name:          v.Name,
+		LanguageCodes: v.Languages,
+		Identifier:    v.Identifier,
+		Gender:        Gender(v.Gender),
+		Age:           int32(v.Age),
+	}
+}
+
+// encoder converts the samples of the engine into the requested encoding
+// and sample rate.
+type encoder struct {
+	encoding   AudioEncoding
+	sampleRate int
+	resampler  *audio.Resampler
+	samples    int64 // samples produced
+}
+
+func (s *TtsServer) newEncoder(req *SynthesizeRequest, encoding AudioEncoding) (*encoder, error) {
+	if _, ok := AudioEncoding_name[int32(encoding)]; !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported audio encoding %v", encoding)
+	}
+	rate := s.engine.SampleRate()
+	if req.GetSampleRateHertz() != 0 {
+		if req.GetSampleRateHertz() < 8000 || req.GetSampleRateHertz() > 48000 {
+			return nil, status.Error(codes.InvalidArgument, "sample_rate_hertz must be between 8000 and 48000")
+		}
+		rate = int(req.GetSampleRateHertz())
+	}
+	return &encoder{
+		encoding:   encoding,
+		sampleRate: rate,
+		resampler:  audio.NewResampler(s.engine.SampleRate(), rate),
+	}, nil
+}
+
+func (e *encoder) encode(samples []int16) []byte {
+	return e.convert(e.resampler.Process(samples))
+}
+
+func (e *encoder) flush() []byte {
+	return e.convert(e.resampler.Flush())
+}
+
+func (e *encoder) convert(samples []int16) []byte {
+	e.samples += int64(len(samples))
+	pcm := audio.Bytes(samples)
+	switch e.encoding {
+	case AudioEncoding_MULAW:
+		return g711.EncodeUlaw(pcm)
+	case AudioEncoding_ALAW:
+		return g711.EncodeAlaw(pcm)
+	}
+	return pcm
+}
+
+// duration returns the duration of the audio produced so far.
+func (e *encoder) duration() time.Duration {
+	return time.Duration(e.samples) * time.Second / time.Duration(e.sampleRate)
+}
@@ -0,0 +1,211 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package tts
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// TtsServiceClient is the client API for TtsService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type TtsServiceClient interface {
+	// ListVoices lists the voices of the synthesizer, the best matches of the
+	// request first.
+	ListVoices(ctx context.Context, in *ListVoicesRequest, opts ...grpc.CallOption) (*ListVoicesResponse, error)
+	// Synthesize synthesizes the whole text and returns its audio.
+	Synthesize(ctx context.Context, in *SynthesizeRequest, opts ...grpc.CallOption) (*SynthesizeResponse, error)
+	// StreamingSynthesize sends the audio as PCM chunks as soon as they are
+	// synthesized. The first response carries the voice used as well.
+	StreamingSynthesize(ctx context.Context, in *SynthesizeRequest, opts ...grpc.CallOption) (TtsService_StreamingSynthesizeClient, error)
+}
+
+type ttsServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTtsServiceClient(cc grpc.ClientConnInterface) TtsServiceClient {
+	return &ttsServiceClient{cc}
+}
+
+func (c *ttsServiceClient) ListVoices(ctx context.Context, in *ListVoicesRequest, opts ...grpc.CallOption) (*ListVoicesResponse, error) {
+	out := new(ListVoicesResponse)
+	err := c.cc.Invoke(ctx, "/v1.tts.TtsService/ListVoices", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ttsServiceClient) Synthesize(ctx context.Context, in *SynthesizeRequest, opts ...grpc.CallOption) (*SynthesizeResponse, error) {
+	out := new(SynthesizeResponse)
+	err := c.cc.Invoke(ctx, "/v1.tts.TtsService/Synthesize", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ttsServiceClient) StreamingSynthesize(ctx context.Context, in *SynthesizeRequest, opts ...grpc.CallOption) (TtsService_StreamingSynthesizeClient, error) {
+	stream, err := c.cc.NewStream(ctx, &TtsService_ServiceDesc.Streams[0], "/v1.tts.TtsService/StreamingSynthesize", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &ttsServiceStreamingSynthesizeClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TtsService_StreamingSynthesizeClient interface {
+	Recv() (*SynthesizeResponse, error)
+	grpc.ClientStream
+}
+
+type ttsServiceStreamingSynthesizeClient struct {
+	grpc.ClientStream
+}
+
+func (x *ttsServiceStreamingSynthesizeClient) Recv() (*SynthesizeResponse, error) {
+	m := new(SynthesizeResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// TtsServiceServer is the server API for TtsService service.
+// All implementations must embed UnimplementedTtsServiceServer
+// for forward compatibility
+type TtsServiceServer interface {
+	// ListVoices lists the voices of the synthesizer, the best matches of the
+	// request first.
+	ListVoices(context.Context, *ListVoicesRequest) (*ListVoicesResponse, error)
+	// Synthesize synthesizes the whole text and returns its audio.
+	Synthesize(context.Context, *SynthesizeRequest) (*SynthesizeResponse, error)
+	// StreamingSynthesize sends the audio as PCM chunks as soon as they are
+	// synthesized. The first response carries the voice used as well.
+	StreamingSynthesize(*SynthesizeRequest, TtsService_StreamingSynthesizeServer) error
+	mustEmbedUnimplementedTtsServiceServer()
+}
+
+// UnimplementedTtsServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedTtsServiceServer struct {
+}
+
+func (UnimplementedTtsServiceServer) ListVoices(context.Context, *ListVoicesRequest) (*ListVoicesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListVoices not implemented")
+}
+func (UnimplementedTtsServiceServer) Synthesize(context.Context, *SynthesizeRequest) (*SynthesizeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Synthesize not implemented")
+}
+func (UnimplementedTtsServiceServer) StreamingSynthesize(*SynthesizeRequest, TtsService_StreamingSynthesizeServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamingSynthesize not implemented")
+}
+func (UnimplementedTtsServiceServer) mustEmbedUnimplementedTtsServiceServer() {}
+
+// UnsafeTtsServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TtsServiceServer will
+// result in compilation errors.
+type UnsafeTtsServiceServer interface {
+	mustEmbedUnimplementedTtsServiceServer()
+}
+
+func RegisterTtsServiceServer(s grpc.ServiceRegistrar, srv TtsServiceServer) {
+	s.RegisterService(&TtsService_ServiceDesc, srv)
+}
+
+func _TtsService_ListVoices_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListVoicesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TtsServiceServer).ListVoices(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.tts.TtsService/ListVoices",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TtsServiceServer).ListVoices(ctx, req.(*ListVoicesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TtsService_Synthesize_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SynthesizeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TtsServiceServer).Synthesize(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.tts.TtsService/Synthesize",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TtsServiceServer).Synthesize(ctx, req.(*SynthesizeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TtsService_StreamingSynthesize_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SynthesizeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TtsServiceServer).StreamingSynthesize(m, &ttsServiceStreamingSynthesizeServer{stream})
+}
+
+type TtsService_StreamingSynthesizeServer interface {
+	Send(*SynthesizeResponse) error
+	grpc.ServerStream
+}
+
+type ttsServiceStreamingSynthesizeServer struct {
+	grpc.ServerStream
+}
+
+func (x *ttsServiceStreamingSynthesizeServer) Send(m *SynthesizeResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// TtsService_ServiceDesc is the grpc.ServiceDesc for TtsService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TtsService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "v1.tts.TtsService",
+	HandlerType: (*TtsServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListVoices",
+			Handler:    _TtsService_ListVoices_Handler,
+		},
+		{
+			MethodName: "Synthesize",
+			Handler:    _TtsService_Synthesize_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamingSynthesize",
+			Handler:       _TtsService_StreamingSynthesize_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "pkg/api/v1/tts/tts.proto",
+}
@@ -0,0 +1,164 @@
+package tts
+
+// Copyright (c) 2018 Bhojpur Consulting Private Limited, India. All rights reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/bhojpur/speech/internal/grpctest"
+	"github.com/bhojpur/speech/pkg/tts"
+	"github.com/bhojpur/speech/pkg/tts/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func dialServer(t *testing.T, server *TtsServer) TtsServiceClient {
+	conn := grpctest.Dial(t, func(s *grpc.Server) { RegisterTtsServiceServer(s, server) })
+	return NewTtsServiceClient(conn)
+}
+
+func newClient(t *testing.T, opts ...Option) TtsServiceClient {
+	engine, err := tts.Open("fake", "")
+	require.NoError(t, err)
+	return dialServer(t, NewServer(engine, opts...))
+}
+
+func TestListVoices(t *testing.T) {
+	client := newClient(t)
+
+	res, err := client.ListVoices(context.Background(), &ListVoicesRequest{})
+	require.NoError(t, err)
+	assert.Len(t, res.GetVoices(), len(fake.Voices))
+
+	res, err = client.ListVoices(context.Background(), &ListVoicesRequest{LanguageCode: "en-US", Gender: Gender_FEMALE})
+	require.NoError(t, err)
+	require.NotEmpty(t, res.GetVoices())
+	assert.Equal(t, "english-us-f", res.GetVoices()[0].GetName())
+	assert.Equal(t, Gender_FEMALE, res.GetVoices()[0].GetGender())
+}
+
+func TestSynthesize(t *testing.T) {
+	client := newClient(t)
+
+	res, err := client.Synthesize(context.Background(), &SynthesizeRequest{
+		Text:  "hello world",
+		Voice: &VoiceSelection{LanguageCode: "hi"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, AudioEncoding_WAV, res.GetAudioEncoding())
+	assert.Equal(t, int64(fake.SampleRate), res.GetSampleRateHertz())
+	assert.Equal(t, "hindi", res.GetVoice().GetName())
+	assert.True(t, bytes.HasPrefix(res.GetAudioContent(), []byte("RIFF")))
+
+	// two words at the default rate of 175 words per minute
+	samples := 2 * (fake.SampleRate * 60 / tts.DefaultParameters.Rate)
+	assert.Equal(t, samples, int(res.GetDuration().AsDuration().Seconds()*fake.SampleRate+0.5))
+}
+
+func TestSynthesizeEncodings(t *testing.T) {
+	client := newClient(t)
+
+	rate := int32(300)
+	pcm, err := client.Synthesize(context.Background(), &SynthesizeRequest{
+		Text:          "one two three",
+		Parameters:    &SynthesisParameters{Rate: &rate},
+		AudioEncoding: AudioEncoding_LINEAR16_PCM,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2*3*(fake.SampleRate*60/300), len(pcm.GetAudioContent()))
+
+	mulaw, err := client.Synthesize(context.Background(), &SynthesizeRequest{
+		Text:            "one two three",
+		Parameters:      &SynthesisParameters{Rate: &rate},
+		AudioEncoding:   AudioEncoding_MULAW,
+		SampleRateHertz: 8000,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(8000), mulaw.GetSampleRateHertz())
+	assert.InDelta(t, len(pcm.GetAudioContent())/4, len(mulaw.GetAudioContent()), 8)
+}
+
+func TestSynthesizeInvalid(t *testing.T) {
+	client := newClient(t, WithMaxTextLength(10))
+
+	pitch := int32(120)
+	for name, req := range map[string]*SynthesizeRequest{
+		"empty":       {Text: " "},
+		"long":        {Text: "a text longer than ten bytes"},
+		"pitch":       {Text: "hello", Parameters: &SynthesisParameters{Pitch: &pitch}},
+		"sample rate": {Text: "hello", SampleRateHertz: 96000},
+		"encoding":    {Text: "hello", AudioEncoding: AudioEncoding(42)},
+	} {
+		_, err := client.Synthesize(context.Background(), req)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err), name)
+	}
+
+	_, err := client.Synthesize(context.Background(), &SynthesizeRequest{
+		Text:  "hello",
+		Voice: &VoiceSelection{LanguageCode: "fr"},
+	})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestStreamingSynthesize(t *testing.T) {
+	client := newClient(t)
+
+	stream, err := client.StreamingSynthesize(context.Background(), &SynthesizeRequest{
+		Text:  "hello streaming world",
+		Voice: &VoiceSelection{Gender: Gender_FEMALE},
+	})
+	require.NoError(t, err)
+
+	var audio []byte
+	var voice *Voice
+	chunks := 0
+	for {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		assert.Equal(t, AudioEncoding_LINEAR16_PCM, res.GetAudioEncoding())
+		audio = append(audio, res.GetAudioContent()...)
+		if res.GetVoice() != nil {
+			voice = res.GetVoice()
+		}
+		chunks++
+	}
+	assert.Greater(t, chunks, 2)
+	assert.Equal(t, 2*3*(fake.SampleRate*60/tts.DefaultParameters.Rate), len(audio))
+	require.NotNil(t, voice)
+	assert.Equal(t, "english-us-f", voice.GetName())
+
+	stream, err = client.StreamingSynthesize(context.Background(), &SynthesizeRequest{
+		Text:          "hello",
+		AudioEncoding: AudioEncoding_WAV,
+	})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
@@ -119,6 +119,16 @@ void vosk_recognizer_set_nlsml(VoskRecognizer *recognizer, int nlsml)
     ((Recognizer *)recognizer)->SetNLSML((bool)nlsml);
 }
 
+void vosk_recognizer_set_endpointer_mode(VoskRecognizer *recognizer, VoskEndpointerMode mode)
+{
+    ((Recognizer *)recognizer)->SetEndpointerMode(mode);
+}
+
+void vosk_recognizer_set_endpointer_delays(VoskRecognizer *recognizer, float t_start_max, float t_end, float t_max)
+{
+    ((Recognizer *)recognizer)->SetEndpointerDelays(t_start_max, t_end, t_max);
+}
+
 void vosk_recognizer_set_spk_model(VoskRecognizer *recognizer, VoskSpkModel *spk_model)
 {
     if (recognizer == nullptr || spk_model == nullptr) {
@@ -215,6 +215,29 @@ void vosk_recognizer_set_partial_words(VoskRecognizer *recognizer, int partial_w
 void vosk_recognizer_set_nlsml(VoskRecognizer *recognizer, int nlsml);
 
 
+/** Endpointer delay mode */
+typedef enum VoskEndpointerMode {
+    VOSK_EP_ANSWER_DEFAULT = 0,
+    VOSK_EP_ANSWER_SHORT = 1,
+    VOSK_EP_ANSWER_LONG = 2,
+    VOSK_EP_ANSWER_VERY_LONG = 3,
+} VoskEndpointerMode;
+
+/** Set endpointer scaling factor
+ *
+ * @param mode - Endpointer mode
+ **/
+void vosk_recognizer_set_endpointer_mode(VoskRecognizer *recognizer, VoskEndpointerMode mode);
+
+/** Set endpointer delays
+ *
+ * @param t_start_max  timeout in seconds for stopping recognition in case of initial silence (usually around 5.0)
+ * @param t_end        timeout in seconds for stopping recognition after we recognized something (usually around 0.5 - 1.0)
+ * @param t_max        timeout in seconds for forcing utterance end (usually around 20-30)
+ **/
+void vosk_recognizer_set_endpointer_delays(VoskRecognizer *recognizer, float t_start_max, float t_end, float t_max);
+
+
 /** Accept voice data
  *
  *  accept and process new chunk of voice data
@@ -0,0 +1,190 @@
+package asr
+
+// Copyright (c) 2018 Bhojpur Consulting Private Limited, India. All rights reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// It defines a common abstraction over the speech recognition engines, so
+// that services can be written once and run on top of Vosk, Coqui or a
+// scripted engine used in tests.
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Word is a recognized word with its position in the audio stream.
+type Word struct {
+	Word       string
+	Start      time.Duration
+	End        time.Duration
+	Confidence float64
+}
+
+// Alternative is a single recognition hypothesis.
+type Alternative struct {
+	Text       string
+	Confidence float64
+	Words      []Word
+}
+
+// Result holds the hypotheses of an utterance, best first. Partial results
+// are tentative and may change as more audio is accepted.
+type Result struct {
+	Alternatives []Alternative
+	Final        bool
+	// Speaker is the embedding (x-vector) of the utterance's speaker, set
+	// for final results when Config.Speaker is enabled.
+	Speaker []float64
+	// SpeakerFrames is the number of frames the embedding was computed
+	// from.
+	SpeakerFrames int
+}
+
+// Text returns the text of the best hypothesis.
+func (r *Result) Text() string {
+	if r == nil || len(r.Alternatives) == 0 {
+		return ""
+	}
+	return r.Alternatives[0].Text
+}
+
+// Empty reports whether nothing was recognized.
+func (r *Result) Empty() bool {
+	return r.Text() == ""
+}
+
+// Config configures a recognizer.
+type Config struct {
+	// SampleRate of the audio fed to the recognizer. Zero means the
+	// engine's own sample rate.
+	SampleRate float64
+	// MaxAlternatives is the number of hypotheses to produce, values
+	// below 2 produce a single hypothesis.
+	MaxAlternatives int
+	// Words enables word timings in the results.
+	Words bool
+	// Speaker enables speaker embeddings in the results. Engines without
+	// a speaker model fail with ErrNoSpeakerModel.
+	Speaker bool
+	// Grammar restricts the recognition to the phrases. The UnknownPhrase
+	// among them stands for any speech outside of the other phrases.
+	// Engines without grammar support fail with ErrNoGrammar.
+	Grammar []string
+}
+
+// UnknownPhrase is the grammar phrase matching out-of-grammar speech, and
+// the text recognized for it.
+const UnknownPhrase = "[unk]"
+
+// Vocabulary is implemented by the engines which can tell whether a word
+// is known to their model.
+type Vocabulary interface {
+	// HasWord reports whether the word can be recognized.
+	HasWord(word string) bool
+}
+
+// Engine is a loaded speech recognition model.
+type Engine interface {
+	// Name returns the name the engine was registered with.
+	Name() string
+	// SampleRate returns the sample rate the model was trained on.
+	SampleRate() float64
+	// NewRecognizer creates an independent recognition stream.
+	NewRecognizer(config Config) (Recognizer, error)
+	// Close releases the model. Recognizers must be closed before.
+	Close() error
+}
+
+// Recognizer is a streaming recognition state. It is not safe for
+// concurrent use.
+type Recognizer interface {
+	// AcceptWaveform feeds 16-bit little-endian mono PCM. It reports true
+	// when the end of an utterance was detected and Result is ready.
+	AcceptWaveform(pcm []byte) (bool, error)
+	// Result returns the result of the utterance that just ended.
+	Result() (*Result, error)
+	// PartialResult returns the tentative result of the current utterance.
+	PartialResult() (*Result, error)
+	// FinalResult flushes the recognizer and returns the result of the
+	// remaining audio without waiting for silence.
+	FinalResult() (*Result, error)
+	// Reset discards the current utterance.
+	Reset() error
+	// Close releases the recognizer.
+	Close() error
+}
+
+// OpenFunc loads the model found at path.
+type OpenFunc func(path string) (Engine, error)
+
+var ErrUnknownEngine = errors.New("unknown speech recognition engine")
+
+// ErrNoSpeakerModel is returned for recognizers asking for speaker
+// embeddings when the engine has no speaker model.
+var ErrNoSpeakerModel = errors.New("no speaker model")
+
+// ErrNoGrammar is returned for recognizers restricted to a grammar when the
+// engine does not support grammars.
+var ErrNoGrammar = errors.New("grammars are not supported")
+
+var (
+	enginesMu sync.RWMutex
+	engines   = map[string]OpenFunc{}
+)
+
+// Register makes an engine available by name. It is meant to be called from
+// the init function of the package implementing the engine.
+func Register(name string, open OpenFunc) {
+	enginesMu.Lock()
+	defer enginesMu.Unlock()
+	if open == nil {
+		panic("asr: Register open function is nil")
+	}
+	if _, dup := engines[name]; dup {
+		panic("asr: Register called twice for engine " + name)
+	}
+	engines[name] = open
+}
+
+// Engines returns the sorted names of the registered engines.
+func Engines() []string {
+	enginesMu.RLock()
+	defer enginesMu.RUnlock()
+	names := make([]string, 0, len(engines))
+	for name := range engines {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Open loads the model at path with the named engine.
+func Open(name, path string) (Engine, error) {
+	enginesMu.RLock()
+	open, ok := engines[name]
+	enginesMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownEngine, name)
+	}
+	return open(path)
+}
@@ -0,0 +1,363 @@
+package fake
+
+// Copyright (c) 2018 Bhojpur Consulting Private Limited, India. All rights reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// It implements a deterministic, scripted speech recognition engine. It does
+// not look at the audio at all: every recognizer replays the same script of
+// utterances, advancing through it as audio is accepted. It allows services
+// and tests to run without the native recognition libraries.
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bhojpur/speech/pkg/asr"
+)
+
+func init() {
+	asr.Register("fake", Open)
+}
+
+// DefaultSampleRate is used by scripts which do not define a sample rate.
+const DefaultSampleRate = 16000
+
+// Utterance is a scripted recognition result. It ends once Seconds worth of
+// audio has been accepted after the end of the previous utterance.
+type Utterance struct {
+	Text         string   `json:"text"`
+	Seconds      float64  `json:"seconds"`
+	Confidence   float64  `json:"confidence,omitempty"`
+	Alternatives []string `json:"alternatives,omitempty"`
+	// Speaker is the embedding reported when speaker embeddings are
+	// enabled.
+	Speaker []float64 `json:"speaker,omitempty"`
+}
+
+// Script is the JSON document read by Open.
+type Script struct {
+	SampleRate float64     `json:"sample_rate,omitempty"`
+	Utterances []Utterance `json:"utterances"`
+}
+
+// Engine replays a script of utterances.
+type Engine struct {
+	sampleRate float64
+	utterances []Utterance
+
+	mu     sync.Mutex
+	active int
+	closed bool
+}
+
+// NewEngine creates an engine replaying the given utterances. A zero sample
+// rate selects DefaultSampleRate.
+func NewEngine(sampleRate float64, utterances ...Utterance) *Engine {
+	if sampleRate <= 0 {
+		sampleRate = DefaultSampleRate
+	}
+	return &Engine{
+		sampleRate: sampleRate,
+		utterances: utterances,
+	}
+}
+
+// Open reads a JSON Script from path.
+func Open(path string) (asr.Engine, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var script Script
+	if err := json.Unmarshal(data, &script); err != nil {
+		return nil, err
+	}
+	return NewEngine(script.SampleRate, script.Utterances...), nil
+}
+
+// HasWord implements asr.Vocabulary. The vocabulary is made of the words of
+// the script.
+func (e *Engine) HasWord(word string) bool {
+	for _, u := range e.utterances {
+		for _, text := range append([]string{u.Text}, u.Alternatives...) {
+			for _, w := range strings.Fields(text) {
+				if w == word {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// Name implements asr.Engine.
+func (e *Engine) Name() string {
+	return "fake"
+}
+
+// SampleRate implements asr.Engine.
+func (e *Engine) SampleRate() float64 {
+	return e.sampleRate
+}
+
+// NewRecognizer implements asr.Engine.
+func (e *Engine) NewRecognizer(config asr.Config) (asr.Recognizer, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.closed {
+		return nil, errors.New("fake: engine is closed")
+	}
+	e.active++
+
+	sampleRate := config.SampleRate
+	if sampleRate <= 0 {
+		sampleRate = e.sampleRate
+	}
+	return &Recognizer{
+		engine:     e,
+		config:     config,
+		sampleRate: sampleRate,
+	}, nil
+}
+
+// Close implements asr.Engine.
+func (e *Engine) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.closed = true
+	return nil
+}
+
+// Active returns the number of recognizers which have not been closed yet.
+func (e *Engine) Active() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.active
+}
+
+// Recognizer is the recognition state of a single stream.
+type Recognizer struct {
+	engine     *Engine
+	config     asr.Config
+	sampleRate float64
+
+	samples int64 // samples accepted so far
+	start   int64 // first sample of the current utterance
+	next    int   // index of the current utterance
+	ended   []int // utterances ended but not returned by Result yet
+	closed  bool
+}
+
+// AcceptWaveform implements asr.Recognizer.
+func (r *Recognizer) AcceptWaveform(pcm []byte) (bool, error) {
+	if r.closed {
+		return false, errors.New("fake: recognizer is closed")
+	}
+	r.samples += int64(len(pcm) / 2)
+	for r.next < len(r.engine.utterances) {
+		end := r.start + r.length(r.next)
+		if r.samples < end {
+			break
+		}
+		r.ended = append(r.ended, r.next)
+		r.start = end
+		r.next++
+	}
+	return len(r.ended) > 0, nil
+}
+
+// Result implements asr.Recognizer.
+func (r *Recognizer) Result() (*asr.Result, error) {
+	if len(r.ended) == 0 {
+		return &asr.Result{Final: true}, nil
+	}
+	i := r.ended[0]
+	r.ended = r.ended[1:]
+	return r.result(i, r.utteranceStart(i), -1, true), nil
+}
+
+// PartialResult implements asr.Recognizer.
+func (r *Recognizer) PartialResult() (*asr.Result, error) {
+	if r.next >= len(r.engine.utterances) {
+		return &asr.Result{}, nil
+	}
+	return r.result(r.next, r.start, r.revealed(), false), nil
+}
+
+// FinalResult implements asr.Recognizer. Queued utterances are returned
+// first, otherwise the words of the current utterance heard so far.
+func (r *Recognizer) FinalResult() (*asr.Result, error) {
+	if len(r.ended) > 0 {
+		return r.Result()
+	}
+	if r.next >= len(r.engine.utterances) || r.samples == r.start {
+		return &asr.Result{Final: true}, nil
+	}
+	res := r.result(r.next, r.start, r.revealed(), true)
+	r.start = r.samples
+	r.next++
+	return res, nil
+}
+
+// Reset implements asr.Recognizer. The current utterance starts over.
+func (r *Recognizer) Reset() error {
+	r.ended = nil
+	r.start = r.samples
+	return nil
+}
+
+// Close implements asr.Recognizer.
+func (r *Recognizer) Close() error {
+	if r.closed {
+		return nil
+	}
+	r.closed = true
+	r.engine.mu.Lock()
+	r.engine.active--
+	r.engine.mu.Unlock()
+	return nil
+}
+
+// length returns the length of utterance i in samples.
+func (r *Recognizer) length(i int) int64 {
+	n := int64(r.engine.utterances[i].Seconds * r.sampleRate)
+	if n < 1 {
+		n = 1
+	}
+	return n
+}
+
+// utteranceStart returns the first sample of the ended utterance i.
+func (r *Recognizer) utteranceStart(i int) int64 {
+	start := r.start
+	for j := r.next - 1; j >= i; j-- {
+		start -= r.length(j)
+	}
+	return start
+}
+
+// revealed returns how many words of the current utterance were heard.
+func (r *Recognizer) revealed() int {
+	words := strings.Fields(r.engine.utterances[r.next].Text)
+	heard := r.samples - r.start
+	return int(int64(len(words)) * heard / r.length(r.next))
+}
+
+// result builds the result of utterance i starting at sample start. A
+// non-negative limit truncates the hypotheses to their first words.
+func (r *Recognizer) result(i int, start int64, limit int, final bool) *asr.Result {
+	u := r.engine.utterances[i]
+	confidence := u.Confidence
+	if confidence == 0 {
+		confidence = 1
+	}
+
+	texts := append([]string{u.Text}, u.Alternatives...)
+	if len(r.config.Grammar) > 0 {
+		texts = []string{r.constrain(u.Text)}
+	}
+	n := r.config.MaxAlternatives
+	if n < 1 {
+		n = 1
+	}
+	if len(texts) > n {
+		texts = texts[:n]
+	}
+
+	res := &asr.Result{Final: final}
+	if final && r.config.Speaker && len(u.Speaker) > 0 {
+		res.Speaker = append([]float64(nil), u.Speaker...)
+		res.SpeakerFrames = int(u.Seconds * 100)
+	}
+	for k, text := range texts {
+		all := strings.Fields(text)
+		words := all
+		if limit >= 0 && len(words) > limit {
+			words = words[:limit]
+		}
+		if len(words) == 0 {
+			continue
+		}
+		alt := asr.Alternative{
+			Text:       strings.Join(words, " "),
+			Confidence: confidence / float64(k+1),
+		}
+		if r.config.Words {
+			alt.Words = r.timings(words, len(all), start, r.length(i), alt.Confidence)
+		}
+		res.Alternatives = append(res.Alternatives, alt)
+	}
+	return res
+}
+
+// constrain maps a text to the grammar: to the same phrase if there is one,
+// to the unknown phrase if the grammar has it, or else to the phrase sharing
+// the most words with the text.
+func (r *Recognizer) constrain(text string) string {
+	words := map[string]bool{}
+	for _, w := range strings.Fields(text) {
+		words[w] = true
+	}
+
+	best, shared := "", -1
+	for _, phrase := range r.config.Grammar {
+		if phrase == text {
+			return phrase
+		}
+		if phrase == asr.UnknownPhrase {
+			best, shared = phrase, len(words)+1
+			continue
+		}
+		n := 0
+		for _, w := range strings.Fields(phrase) {
+			if words[w] {
+				n++
+			}
+		}
+		if n > shared {
+			best, shared = phrase, n
+		}
+	}
+	return best
+}
+
+// timings spreads the total words of an utterance evenly over its length
+// and returns the timings of the given leading words.
+func (r *Recognizer) timings(words []string, total int, start, length int64, confidence float64) []asr.Word {
+	step := float64(length) / float64(total)
+	timings := make([]asr.Word, len(words))
+	for k, w := range words {
+		timings[k] = asr.Word{
+			Word:       w,
+			Start:      r.duration(float64(start) + step*float64(k)),
+			End:        r.duration(float64(start) + step*float64(k+1)),
+			Confidence: confidence,
+		}
+	}
+	return timings
+}
+
+func (r *Recognizer) duration(samples float64) time.Duration {
+	return time.Duration(samples / r.sampleRate * float64(time.Second))
+}
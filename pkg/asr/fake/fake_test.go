@@ -0,0 +1,153 @@
+package fake
+
+// Copyright (c) 2018 Bhojpur Consulting Private Limited, India. All rights reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bhojpur/speech/pkg/asr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// silence returns the PCM bytes of the given number of seconds at 16 kHz.
+func silence(seconds float64) []byte {
+	return make([]byte, 2*int(seconds*DefaultSampleRate))
+}
+
+func TestRecognizerEndpoints(t *testing.T) {
+	engine := NewEngine(0,
+		Utterance{Text: "hello world", Seconds: 1},
+		Utterance{Text: "good bye", Seconds: 0.5},
+	)
+	rec, err := engine.NewRecognizer(asr.Config{})
+	require.NoError(t, err)
+	defer rec.Close()
+
+	endpoint, err := rec.AcceptWaveform(silence(0.5))
+	require.NoError(t, err)
+	assert.False(t, endpoint)
+
+	partial, err := rec.PartialResult()
+	require.NoError(t, err)
+	assert.Equal(t, "hello", partial.Text())
+	assert.False(t, partial.Final)
+
+	endpoint, err = rec.AcceptWaveform(silence(0.5))
+	require.NoError(t, err)
+	assert.True(t, endpoint)
+
+	res, err := rec.Result()
+	require.NoError(t, err)
+	assert.Equal(t, "hello world", res.Text())
+	assert.True(t, res.Final)
+
+	endpoint, err = rec.AcceptWaveform(silence(0.25))
+	require.NoError(t, err)
+	assert.False(t, endpoint)
+
+	res, err = rec.FinalResult()
+	require.NoError(t, err)
+	assert.Equal(t, "good", res.Text())
+
+	res, err = rec.FinalResult()
+	require.NoError(t, err)
+	assert.True(t, res.Empty())
+}
+
+func TestRecognizerWordsAndAlternatives(t *testing.T) {
+	engine := NewEngine(8000, Utterance{
+		Text:         "one two",
+		Seconds:      2,
+		Confidence:   0.8,
+		Alternatives: []string{"won too", "one to"},
+	})
+	rec, err := engine.NewRecognizer(asr.Config{MaxAlternatives: 2, Words: true})
+	require.NoError(t, err)
+	defer rec.Close()
+
+	// one chunk spanning the whole utterance and some more audio
+	endpoint, err := rec.AcceptWaveform(make([]byte, 2*8000*3))
+	require.NoError(t, err)
+	assert.True(t, endpoint)
+
+	res, err := rec.Result()
+	require.NoError(t, err)
+	require.Len(t, res.Alternatives, 2)
+	assert.Equal(t, "won too", res.Alternatives[1].Text)
+	assert.Equal(t, 0.8, res.Alternatives[0].Confidence)
+	assert.Equal(t, []asr.Word{
+		{Word: "one", Start: 0, End: time.Second, Confidence: 0.8},
+		{Word: "two", Start: time.Second, End: 2 * time.Second, Confidence: 0.8},
+	}, res.Alternatives[0].Words)
+}
+
+func TestRecognizerGrammar(t *testing.T) {
+	engine := NewEngine(0,
+		Utterance{Text: "open the door", Seconds: 1},
+		Utterance{Text: "close door please", Seconds: 1},
+		Utterance{Text: "sing a song", Seconds: 1},
+	)
+	assert.True(t, engine.HasWord("door"))
+	assert.False(t, engine.HasWord("window"))
+
+	recognize := func(grammar ...string) []string {
+		rec, err := engine.NewRecognizer(asr.Config{Grammar: grammar})
+		require.NoError(t, err)
+		defer rec.Close()
+		var texts []string
+		for i := 0; i < 3; i++ {
+			_, err := rec.AcceptWaveform(silence(1))
+			require.NoError(t, err)
+			res, err := rec.Result()
+			require.NoError(t, err)
+			texts = append(texts, res.Text())
+		}
+		return texts
+	}
+
+	assert.Equal(t, []string{"open the door", "close door", "open the door"},
+		recognize("open the door", "close door"))
+	assert.Equal(t, []string{"open the door", "[unk]", "[unk]"},
+		recognize("open the door", "close door", asr.UnknownPhrase))
+}
+
+func TestEngineActiveAndOpen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "script.json")
+	err := os.WriteFile(path, []byte(`{"sample_rate": 8000, "utterances": [{"text": "yes", "seconds": 1}]}`), 0644)
+	require.NoError(t, err)
+
+	engine, err := asr.Open("fake", path)
+	require.NoError(t, err)
+	assert.Equal(t, 8000.0, engine.SampleRate())
+
+	rec, err := engine.NewRecognizer(asr.Config{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, engine.(*Engine).Active())
+	assert.NoError(t, rec.Close())
+	assert.Equal(t, 0, engine.(*Engine).Active())
+
+	_, err = asr.Open("missing", path)
+	assert.ErrorIs(t, err, asr.ErrUnknownEngine)
+}
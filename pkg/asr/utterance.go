@@ -0,0 +1,62 @@
+package asr
+
+// Copyright (c) 2018 Bhojpur Consulting Private Limited, India. All rights reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Accept feeds 16-bit PCM to rec and returns the final result of the
+// utterance it ended, if any: at an endpoint of the recognizer, or when end
+// is true, by flushing the recognizer, as at the end of a speech segment
+// found by a voice activity detector. Utterances in which nothing was
+// recognized are dropped.
+func Accept(rec Recognizer, pcm []byte, end bool) (*Result, error) {
+	endpoint, err := rec.AcceptWaveform(pcm)
+	if err != nil {
+		return nil, err
+	}
+	var res *Result
+	switch {
+	case endpoint:
+		res, err = rec.Result()
+	case end:
+		res, err = rec.FinalResult()
+	default:
+		return nil, nil
+	}
+	if err != nil || res.Empty() {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Drain flushes rec at the end of the audio and returns the final results
+// of the utterances it still holds, in order, until an empty one.
+func Drain(rec Recognizer) ([]*Result, error) {
+	var results []*Result
+	for {
+		res, err := rec.FinalResult()
+		if err != nil {
+			return nil, err
+		}
+		if res.Empty() {
+			return results, nil
+		}
+		results = append(results, res)
+	}
+}
@@ -0,0 +1,65 @@
+package asr_test
+
+// Copyright (c) 2018 Bhojpur Consulting Private Limited, India. All rights reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"testing"
+
+	"github.com/bhojpur/speech/pkg/asr"
+	"github.com/bhojpur/speech/pkg/asr/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcceptAndDrain(t *testing.T) {
+	engine := fake.NewEngine(0,
+		fake.Utterance{Text: "hello world", Seconds: 1},
+		fake.Utterance{Text: "good bye", Seconds: 1},
+		fake.Utterance{Text: "see you", Seconds: 1},
+	)
+	rec, err := engine.NewRecognizer(asr.Config{})
+	require.NoError(t, err)
+	defer rec.Close()
+	second := make([]byte, 2*fake.DefaultSampleRate)
+
+	res, err := asr.Accept(rec, second[:len(second)/2], false)
+	require.NoError(t, err)
+	assert.Nil(t, res)
+
+	// the endpoint of the recognizer ends the utterance
+	res, err = asr.Accept(rec, second[len(second)/2:], false)
+	require.NoError(t, err)
+	assert.Equal(t, "hello world", res.Text())
+
+	// so does the caller, with what was heard so far
+	res, err = asr.Accept(rec, second[:len(second)/2], true)
+	require.NoError(t, err)
+	assert.Equal(t, "good", res.Text())
+
+	// the end of the audio flushes the last utterance
+	res, err = asr.Accept(rec, second[:len(second)/2], false)
+	require.NoError(t, err)
+	assert.Nil(t, res)
+	results, err := asr.Drain(rec)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "see", results[0].Text())
+}
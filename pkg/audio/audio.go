@@ -0,0 +1,206 @@
+package audio
+
+// Copyright (c) 2018 Bhojpur Consulting Private Limited, India. All rights reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// It converts the audio formats accepted by the speech services into 16-bit
+// little-endian mono PCM at the sample rate of a recognition model. The
+// decoders work on streams: input may be split at arbitrary byte positions.
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Encoding of the input audio.
+type Encoding int
+
+const (
+	Linear16 Encoding = iota // 16-bit signed little-endian mono PCM
+	Mulaw                    // G.711 u-law mono
+	Alaw                     // G.711 A-law mono
+	Wav                      // RIFF WAVE file, format taken from the header
+	Mp3                      // MPEG-1/2 layer III stream
+	Aiff                     // AIFF or AIFF-C file, format taken from the header
+)
+
+// String implements the stringer interface.
+func (e Encoding) String() string {
+	switch e {
+	case Linear16:
+		return "LINEAR16"
+	case Mulaw:
+		return "MULAW"
+	case Alaw:
+		return "ALAW"
+	case Wav:
+		return "WAV"
+	case Mp3:
+		return "MP3"
+	case Aiff:
+		return "AIFF"
+	default:
+		return fmt.Sprintf("Encoding(%d)", int(e))
+	}
+}
+
+// ParseEncoding returns the encoding named s, case insensitively. Besides
+// the names returned by String, it accepts the aliases PCM, ULAW and AIFC.
+func ParseEncoding(s string) (Encoding, error) {
+	switch strings.ToUpper(s) {
+	case "LINEAR16", "PCM":
+		return Linear16, nil
+	case "MULAW", "ULAW":
+		return Mulaw, nil
+	case "ALAW":
+		return Alaw, nil
+	case "WAV":
+		return Wav, nil
+	case "MP3":
+		return Mp3, nil
+	case "AIFF", "AIFC":
+		return Aiff, nil
+	}
+	return 0, fmt.Errorf("%w: %q", ErrUnsupportedFormat, s)
+}
+
+// Detect recognizes the container of a stream from its first bytes. It
+// reports false for header-less encodings, which can not be told apart.
+func Detect(header []byte) (Encoding, bool) {
+	switch {
+	case len(header) >= 12 && bytes.HasPrefix(header, []byte("RIFF")) && string(header[8:12]) == "WAVE":
+		return Wav, true
+	case len(header) >= 12 && bytes.HasPrefix(header, []byte("FORM")) &&
+		(string(header[8:12]) == "AIFF" || string(header[8:12]) == "AIFC"):
+		return Aiff, true
+	case bytes.HasPrefix(header, []byte("ID3")):
+		return Mp3, true
+	case len(header) >= 2 && header[0] == 0xff && header[1]&0xe0 == 0xe0:
+		// the frame sync of an MPEG audio frame
+		return Mp3, true
+	}
+	return 0, false
+}
+
+// ErrSampleRateMismatch is returned when the sample rate found in the stream
+// differs from the declared one.
+var ErrSampleRateMismatch = errors.New("sample rate mismatch")
+
+// ErrUnsupportedFormat is returned for streams which can not be decoded.
+var ErrUnsupportedFormat = errors.New("unsupported audio format")
+
+// source decodes an encoded stream into interleaved 16-bit samples.
+type source interface {
+	write(p []byte) ([]int16, error)
+	close() ([]int16, error)
+	// channels and rate are valid once the first samples are returned.
+	channels() int
+	rate() int
+}
+
+// Decoder converts an encoded stream into 16-bit little-endian mono PCM at
+// the target sample rate.
+type Decoder struct {
+	src          source
+	declaredRate int
+	targetRate   int
+	resampler    *Resampler
+}
+
+// NewDecoder creates a decoder. The sample rate is mandatory for the raw
+// encodings, for WAV, AIFF and MP3 it is checked against the stream when
+// non-zero.
+func NewDecoder(encoding Encoding, sampleRate, targetRate int) (*Decoder, error) {
+	if targetRate <= 0 {
+		return nil, fmt.Errorf("invalid target sample rate %d", targetRate)
+	}
+	if sampleRate < 0 {
+		return nil, fmt.Errorf("invalid sample rate %d", sampleRate)
+	}
+
+	var src source
+	switch encoding {
+	case Linear16, Mulaw, Alaw:
+		if sampleRate == 0 {
+			return nil, fmt.Errorf("sample rate is required for %v audio", encoding)
+		}
+		src = &rawSource{encoding: encoding, sampleRate: sampleRate}
+	case Wav:
+		src = &wavSource{}
+	case Mp3:
+		src = newMp3Source()
+	case Aiff:
+		src = &aiffSource{}
+	default:
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedFormat, encoding)
+	}
+
+	return &Decoder{
+		src:          src,
+		declaredRate: sampleRate,
+		targetRate:   targetRate,
+	}, nil
+}
+
+// SampleRate returns the sample rate of the input stream, or zero while it
+// is not known yet.
+func (d *Decoder) SampleRate() int {
+	return d.src.rate()
+}
+
+// Write decodes the next part of the stream and returns the PCM available
+// so far, which may be empty.
+func (d *Decoder) Write(p []byte) ([]byte, error) {
+	samples, err := d.src.write(p)
+	if err != nil {
+		return nil, err
+	}
+	return d.convert(samples, false)
+}
+
+// Close ends the stream and returns the remaining PCM.
+func (d *Decoder) Close() ([]byte, error) {
+	samples, err := d.src.close()
+	if err != nil {
+		return nil, err
+	}
+	return d.convert(samples, true)
+}
+
+func (d *Decoder) convert(samples []int16, final bool) ([]byte, error) {
+	if d.resampler == nil {
+		if len(samples) == 0 {
+			return nil, nil
+		}
+		rate := d.src.rate()
+		if d.declaredRate != 0 && rate != d.declaredRate {
+			return nil, fmt.Errorf("%w: declared %d Hz, stream is %d Hz", ErrSampleRateMismatch, d.declaredRate, rate)
+		}
+		d.resampler = NewResampler(rate, d.targetRate)
+	}
+
+	out := d.resampler.Process(Downmix(samples, d.src.channels()))
+	if final {
+		out = append(out, d.resampler.Flush()...)
+	}
+	return Bytes(out), nil
+}
@@ -0,0 +1,278 @@
+package audio
+
+// Copyright (c) 2018 Bhojpur Consulting Private Limited, India. All rights reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io/ioutil"
+	"math"
+	"testing"
+
+	"github.com/bhojpur/speech/pkg/wave"
+	"github.com/bhojpur/speech/pkg/wave/g711"
+	"github.com/stretchr/testify/assert"
+)
+
+// wavFile builds a WAV file holding 16-bit samples.
+func wavFile(sampleRate, channels int, samples []int16) []byte {
+	var buf bytes.Buffer
+	wave.NewWriter(&buf, uint32(len(samples)/channels), uint16(channels), uint32(sampleRate), 16).Write(Bytes(samples))
+	return buf.Bytes()
+}
+
+// aiffFile builds an AIFF-C file holding samples of the given width in
+// bytes, already encoded with the given compression.
+func aiffFile(compression string, channels, bits int, data []byte) []byte {
+	var comm bytes.Buffer
+	binary.Write(&comm, binary.BigEndian, uint16(channels))
+	binary.Write(&comm, binary.BigEndian, uint32(len(data)/channels/((bits+7)/8)))
+	binary.Write(&comm, binary.BigEndian, uint16(bits))
+	// 16000 Hz as an 80-bit extended float
+	comm.Write([]byte{0x40, 0x0c, 0xfa, 0, 0, 0, 0, 0, 0, 0})
+	comm.WriteString(compression)
+	comm.Write([]byte{0, 0}) // empty compression name, padded
+
+	var buf bytes.Buffer
+	buf.WriteString("FORM")
+	binary.Write(&buf, binary.BigEndian, uint32(4+8+comm.Len()+16+len(data)))
+	buf.WriteString("AIFC")
+	buf.WriteString("COMM")
+	binary.Write(&buf, binary.BigEndian, uint32(comm.Len()))
+	buf.Write(comm.Bytes())
+	buf.WriteString("SSND")
+	binary.Write(&buf, binary.BigEndian, uint32(8+len(data)))
+	binary.Write(&buf, binary.BigEndian, uint32(0))
+	binary.Write(&buf, binary.BigEndian, uint32(0))
+	buf.Write(data)
+	return buf.Bytes()
+}
+
+// decodeAll feeds the input to the decoder in chunks of the given size.
+func decodeAll(t *testing.T, d *Decoder, input []byte, chunk int) []int16 {
+	var out []byte
+	for len(input) > 0 {
+		n := chunk
+		if n > len(input) {
+			n = len(input)
+		}
+		pcm, err := d.Write(input[:n])
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		out = append(out, pcm...)
+		input = input[n:]
+	}
+	pcm, err := d.Close()
+	assert.NoError(t, err)
+	return Int16s(append(out, pcm...))
+}
+
+func TestDecodeLinear16(t *testing.T) {
+	samples := []int16{0, 1, -1, 32767, -32768, 1234}
+	d, err := NewDecoder(Linear16, 16000, 16000)
+	assert.NoError(t, err)
+	// odd chunks split the samples
+	assert.Equal(t, samples, decodeAll(t, d, Bytes(samples), 3))
+
+	_, err = NewDecoder(Linear16, 0, 16000)
+	assert.Error(t, err)
+}
+
+func TestDecodeG711(t *testing.T) {
+	samples := []int16{0, 1000, -1000, 8000, -8000, 30000}
+	pcm := Bytes(samples)
+
+	d, err := NewDecoder(Mulaw, 8000, 8000)
+	assert.NoError(t, err)
+	assert.Equal(t, Int16s(g711.DecodeUlaw(g711.EncodeUlaw(pcm))), decodeAll(t, d, g711.EncodeUlaw(pcm), 4))
+
+	d, err = NewDecoder(Alaw, 8000, 8000)
+	assert.NoError(t, err)
+	assert.Equal(t, Int16s(g711.DecodeAlaw(g711.EncodeAlaw(pcm))), decodeAll(t, d, g711.EncodeAlaw(pcm), 4))
+}
+
+func TestDecodeWav(t *testing.T) {
+	stereo := []int16{100, 300, -100, -300, 32767, 32767}
+	file := wavFile(16000, 2, stereo)
+
+	for _, chunk := range []int{1, 7, len(file)} {
+		d, err := NewDecoder(Wav, 0, 16000)
+		assert.NoError(t, err)
+		assert.Equal(t, []int16{200, -200, 32767}, decodeAll(t, d, file, chunk))
+		assert.Equal(t, 16000, d.SampleRate())
+	}
+}
+
+func TestDecodeWavMismatch(t *testing.T) {
+	d, err := NewDecoder(Wav, 8000, 16000)
+	assert.NoError(t, err)
+	_, err = d.Write(wavFile(16000, 1, []int16{1, 2, 3}))
+	assert.True(t, errors.Is(err, ErrSampleRateMismatch))
+}
+
+func TestDecodeWavUnsupported(t *testing.T) {
+	file := wavFile(16000, 1, []int16{1, 2, 3})
+	// switch the format tag to ADPCM
+	binary.LittleEndian.PutUint16(file[20:], 2)
+	d, err := NewDecoder(Wav, 0, 16000)
+	assert.NoError(t, err)
+	_, err = d.Write(file)
+	assert.True(t, errors.Is(err, ErrUnsupportedFormat))
+}
+
+func TestDecodeAiff(t *testing.T) {
+	stereo := []int16{100, 300, -100, -300, 32767, 32767}
+	var be, le []byte
+	for _, v := range stereo {
+		be = append(be, byte(uint16(v)>>8), byte(v))
+		le = append(le, byte(v), byte(uint16(v)>>8))
+	}
+
+	for _, file := range [][]byte{aiffFile("NONE", 2, 16, be), aiffFile("sowt", 2, 16, le)} {
+		for _, chunk := range []int{1, 7, len(file)} {
+			d, err := NewDecoder(Aiff, 0, 16000)
+			assert.NoError(t, err)
+			assert.Equal(t, []int16{200, -200, 32767}, decodeAll(t, d, file, chunk))
+			assert.Equal(t, 16000, d.SampleRate())
+		}
+	}
+}
+
+func TestDecodeAiffUnsizedData(t *testing.T) {
+	// 32-bit samples with the sizes left unset, as written by the recorder
+	data := []byte{0x12, 0x34, 0x56, 0x78, 0xff, 0xfe, 0, 0}
+	file := aiffFile("NONE", 1, 32, data)
+	binary.BigEndian.PutUint32(file[len(file)-len(data)-12:], 0)
+
+	d, err := NewDecoder(Aiff, 16000, 16000)
+	assert.NoError(t, err)
+	assert.Equal(t, []int16{0x1234, -2}, decodeAll(t, d, file, 3))
+}
+
+func TestDecodeAiffUnsupported(t *testing.T) {
+	d, err := NewDecoder(Aiff, 0, 16000)
+	assert.NoError(t, err)
+	_, err = d.Write(aiffFile("ima4", 1, 16, make([]byte, 34)))
+	assert.True(t, errors.Is(err, ErrUnsupportedFormat))
+
+	d, err = NewDecoder(Aiff, 0, 16000)
+	assert.NoError(t, err)
+	_, err = d.Write(aiffFile("NONE", 1, 16, nil)[:20])
+	assert.NoError(t, err)
+	_, err = d.Close()
+	assert.Error(t, err)
+}
+
+func TestDetect(t *testing.T) {
+	for _, tc := range []struct {
+		header   []byte
+		encoding Encoding
+		ok       bool
+	}{
+		{wavFile(16000, 1, nil), Wav, true},
+		{aiffFile("NONE", 1, 16, nil), Aiff, true},
+		{[]byte("ID3\x04\x00"), Mp3, true},
+		{[]byte{0xff, 0xfb, 0x90, 0x64}, Mp3, true},
+		{Bytes([]int16{1, 2, 3}), 0, false},
+		{nil, 0, false},
+	} {
+		encoding, ok := Detect(tc.header)
+		assert.Equal(t, tc.ok, ok)
+		assert.Equal(t, tc.encoding, encoding)
+	}
+}
+
+func TestParseEncoding(t *testing.T) {
+	for _, e := range []Encoding{Linear16, Mulaw, Alaw, Wav, Mp3, Aiff} {
+		parsed, err := ParseEncoding(e.String())
+		assert.NoError(t, err)
+		assert.Equal(t, e, parsed)
+	}
+	parsed, err := ParseEncoding("ulaw")
+	assert.NoError(t, err)
+	assert.Equal(t, Mulaw, parsed)
+	_, err = ParseEncoding("flac")
+	assert.True(t, errors.Is(err, ErrUnsupportedFormat))
+}
+
+func TestDecodeMp3(t *testing.T) {
+	file, err := ioutil.ReadFile("../../audios/chrono.mp3")
+	if err != nil {
+		t.Skip(err)
+	}
+	file = file[:64*1024]
+
+	d, err := NewDecoder(Mp3, 0, 16000)
+	assert.NoError(t, err)
+	out := decodeAll(t, d, file, 4096)
+	assert.Equal(t, 44100, d.SampleRate())
+	assert.NotEmpty(t, out)
+
+	d, err = NewDecoder(Mp3, 8000, 16000)
+	assert.NoError(t, err)
+	_, err = d.Write(file)
+	if err == nil {
+		_, err = d.Close()
+	}
+	assert.True(t, errors.Is(err, ErrSampleRateMismatch))
+}
+
+func TestDecodeMp3Invalid(t *testing.T) {
+	d, err := NewDecoder(Mp3, 0, 16000)
+	assert.NoError(t, err)
+	_, err = d.Write(bytes.Repeat([]byte("not an mp3 stream "), 1000))
+	if err == nil {
+		_, err = d.Close()
+	}
+	assert.Error(t, err)
+}
+
+func TestResampleLength(t *testing.T) {
+	for _, rates := range [][2]int{{8000, 16000}, {48000, 16000}, {44100, 16000}, {16000, 16000}} {
+		r := NewResampler(rates[0], rates[1])
+		var n int
+		for i := 0; i < 10; i++ {
+			n += len(r.Process(make([]int16, 4410)))
+		}
+		n += len(r.Flush())
+		want := int(math.Ceil(44100 * float64(rates[1]) / float64(rates[0])))
+		assert.Equal(t, want, n, "%d to %d", rates[0], rates[1])
+	}
+}
+
+func TestResampleSine(t *testing.T) {
+	const from, to, freq = 48000, 16000, 440.0
+	in := make([]int16, from)
+	for i := range in {
+		in[i] = int16(10000 * math.Sin(2*math.Pi*freq*float64(i)/from))
+	}
+	r := NewResampler(from, to)
+	out := append(r.Process(in), r.Flush()...)
+	assert.Len(t, out, to)
+
+	// away from the edges the output follows the same sine
+	for i := 100; i < to-100; i++ {
+		want := 10000 * math.Sin(2*math.Pi*freq*float64(i)/to)
+		assert.InDelta(t, want, float64(out[i]), 50)
+	}
+}
@@ -0,0 +1,133 @@
+package audio
+
+// Copyright (c) 2018 Bhojpur Consulting Private Limited, India. All rights reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"math"
+)
+
+// resamplerZeroCrossings is the number of zero crossings of the
+// interpolation kernel on each side, at the lower of the two rates.
+const resamplerZeroCrossings = 16
+
+// Resampler converts a stream of mono samples between two sample rates using
+// band-limited (windowed sinc) interpolation. When downsampling, the kernel
+// also acts as the anti-aliasing low-pass filter.
+type Resampler struct {
+	from, to  int
+	cutoff    float64
+	halfWidth int // kernel half width in input samples
+
+	buf   []float64 // input samples from index base on
+	base  int64
+	total int64 // input samples received
+	next  int64 // index of the next output sample
+}
+
+// NewResampler creates a resampler from one sample rate to another.
+func NewResampler(from, to int) *Resampler {
+	cutoff := 1.0
+	if to < from {
+		cutoff = float64(to) / float64(from)
+	}
+	return &Resampler{
+		from:      from,
+		to:        to,
+		cutoff:    cutoff,
+		halfWidth: int(math.Ceil(resamplerZeroCrossings / cutoff)),
+	}
+}
+
+// Process resamples the next part of the stream. Output lags behind the
+// input by the kernel half width until Flush is called.
+func (r *Resampler) Process(in []int16) []int16 {
+	if r.from == r.to {
+		return in
+	}
+	for _, s := range in {
+		r.buf = append(r.buf, float64(s))
+	}
+	r.total += int64(len(in))
+	return r.produce(float64(r.total - int64(r.halfWidth)))
+}
+
+// Flush returns the rest of the stream, as if followed by silence.
+func (r *Resampler) Flush() []int16 {
+	if r.from == r.to {
+		return nil
+	}
+	return r.produce(float64(r.total))
+}
+
+// produce computes the output samples positioned before the input index
+// limit.
+func (r *Resampler) produce(limit float64) []int16 {
+	var out []int16
+	for {
+		t := float64(r.next) * float64(r.from) / float64(r.to)
+		if t >= limit {
+			break
+		}
+		out = append(out, clamp(r.interpolate(t)))
+		r.next++
+	}
+
+	// forget the input no longer reachable by the kernel
+	t := float64(r.next) * float64(r.from) / float64(r.to)
+	keep := int64(math.Floor(t)) - int64(r.halfWidth)
+	if drop := keep - r.base; drop > 0 {
+		if drop > int64(len(r.buf)) {
+			drop = int64(len(r.buf))
+		}
+		r.buf = r.buf[drop:]
+		r.base += drop
+	}
+	return out
+}
+
+func (r *Resampler) interpolate(t float64) float64 {
+	center := int64(math.Floor(t))
+	var sum float64
+	for j := center - int64(r.halfWidth) + 1; j <= center+int64(r.halfWidth); j++ {
+		i := j - r.base
+		if i < 0 || i >= int64(len(r.buf)) {
+			continue
+		}
+		sum += r.buf[i] * r.kernel(t-float64(j))
+	}
+	return sum
+}
+
+// kernel is a Blackman windowed sinc low-pass filter.
+func (r *Resampler) kernel(d float64) float64 {
+	w := float64(r.halfWidth)
+	if d <= -w || d >= w {
+		return 0
+	}
+	x := r.cutoff * d
+	sinc := 1.0
+	if x != 0 {
+		sinc = math.Sin(math.Pi*x) / (math.Pi * x)
+	}
+	n := (d + w) / (2 * w)
+	window := 0.42 - 0.5*math.Cos(2*math.Pi*n) + 0.08*math.Cos(4*math.Pi*n)
+	return r.cutoff * sinc * window
+}
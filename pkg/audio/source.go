@@ -0,0 +1,523 @@
+package audio
+
+// Copyright (c) 2018 Bhojpur Consulting Private Limited, India. All rights reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sync"
+
+	"github.com/bhojpur/speech/pkg/mp3"
+	"github.com/bhojpur/speech/pkg/wave"
+	"github.com/bhojpur/speech/pkg/wave/g711"
+)
+
+// maxHeaderSize bounds the bytes buffered while looking for a WAV or AIFF
+// header.
+const maxHeaderSize = 1 << 20
+
+// rawSource decodes the header-less mono encodings.
+type rawSource struct {
+	encoding   Encoding
+	sampleRate int
+	carry      []byte
+}
+
+func (s *rawSource) write(p []byte) ([]int16, error) {
+	switch s.encoding {
+	case Mulaw:
+		return Int16s(g711.DecodeUlaw(p)), nil
+	case Alaw:
+		return Int16s(g711.DecodeAlaw(p)), nil
+	}
+
+	data := p
+	if len(s.carry) > 0 {
+		data = append(s.carry, p...)
+		s.carry = nil
+	}
+	if len(data)%2 == 1 {
+		s.carry = []byte{data[len(data)-1]}
+		data = data[:len(data)-1]
+	}
+	return Int16s(data), nil
+}
+
+func (s *rawSource) close() ([]int16, error) {
+	return nil, nil
+}
+
+func (s *rawSource) channels() int {
+	return 1
+}
+
+func (s *rawSource) rate() int {
+	return s.sampleRate
+}
+
+// wavSource buffers the input until the WAV header is complete and then
+// decodes the sample blocks.
+type wavSource struct {
+	header    []byte
+	format    *wave.WavFormat
+	remaining int64 // bytes left in the data chunk, negative when unknown
+	carry     []byte
+}
+
+func (s *wavSource) write(p []byte) ([]int16, error) {
+	if s.format == nil {
+		s.header = append(s.header, p...)
+		r := bytes.NewReader(s.header)
+		format, dataSize, err := wave.ReadHeader(r)
+		if err == io.ErrUnexpectedEOF {
+			if len(s.header) > maxHeaderSize {
+				return nil, errors.New("WAV header is too large")
+			}
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := checkWavFormat(format); err != nil {
+			return nil, err
+		}
+		s.format = format
+		// streamed files often leave the size unset
+		s.remaining = int64(dataSize)
+		if dataSize == 0 || dataSize == math.MaxUint32 {
+			s.remaining = -1
+		}
+		p = s.header[len(s.header)-r.Len():]
+		s.header = nil
+	}
+
+	if s.remaining >= 0 {
+		if int64(len(p)) > s.remaining {
+			p = p[:s.remaining]
+		}
+		s.remaining -= int64(len(p))
+	}
+
+	data := p
+	if len(s.carry) > 0 {
+		data = append(s.carry, p...)
+		s.carry = nil
+	}
+	blockAlign := int(s.format.BlockAlign)
+	n := len(data) / blockAlign * blockAlign
+	if n < len(data) {
+		s.carry = append([]byte(nil), data[n:]...)
+	}
+	return s.decode(data[:n]), nil
+}
+
+func (s *wavSource) close() ([]int16, error) {
+	if s.format == nil {
+		if len(s.header) == 0 {
+			return nil, nil
+		}
+		return nil, errors.New("truncated WAV header")
+	}
+	return nil, nil
+}
+
+func (s *wavSource) channels() int {
+	if s.format == nil {
+		return 1
+	}
+	return int(s.format.NumChannels)
+}
+
+func (s *wavSource) rate() int {
+	if s.format == nil {
+		return 0
+	}
+	return int(s.format.SampleRate)
+}
+
+// decode converts whole sample blocks into interleaved 16-bit samples.
+func (s *wavSource) decode(data []byte) []int16 {
+	width := int(s.format.BitsPerSample) / 8
+	samples := make([]int16, len(data)/width)
+	for i := range samples {
+		b := data[i*width : (i+1)*width]
+		switch s.format.AudioFormat {
+		case wave.AudioFormatIEEEFloat:
+			samples[i] = clamp(float64(math.Float32frombits(binary.LittleEndian.Uint32(b))) * math.MaxInt16)
+		case wave.AudioFormatALaw:
+			samples[i] = g711.DecodeAlawFrame(b[0])
+		case wave.AudioFormatMULaw:
+			samples[i] = g711.DecodeUlawFrame(b[0])
+		default:
+			switch width {
+			case 1:
+				samples[i] = int16(int(b[0])-128) << 8
+			case 2:
+				samples[i] = int16(binary.LittleEndian.Uint16(b))
+			case 3:
+				samples[i] = int16(int32(uint32(b[0])<<8|uint32(b[1])<<16|uint32(b[2])<<24) >> 16)
+			case 4:
+				samples[i] = int16(int32(binary.LittleEndian.Uint32(b)) >> 16)
+			}
+		}
+	}
+	return samples
+}
+
+func checkWavFormat(format *wave.WavFormat) error {
+	if format.NumChannels == 0 || format.SampleRate == 0 {
+		return fmt.Errorf("%w: WAV with %d channels at %d Hz", ErrUnsupportedFormat, format.NumChannels, format.SampleRate)
+	}
+	bits := format.BitsPerSample
+	ok := false
+	switch format.AudioFormat {
+	case wave.AudioFormatPCM:
+		ok = bits == 8 || bits == 16 || bits == 24 || bits == 32
+	case wave.AudioFormatIEEEFloat:
+		ok = bits == 32
+	case wave.AudioFormatALaw, wave.AudioFormatMULaw:
+		ok = bits == 8
+	}
+	if !ok || int(format.BlockAlign) != int(format.NumChannels)*int(bits)/8 {
+		return fmt.Errorf("%w: WAV format %d with %d bits per sample", ErrUnsupportedFormat, format.AudioFormat, bits)
+	}
+	return nil
+}
+
+// aiffFormat is the sample format of an AIFF file.
+type aiffFormat struct {
+	channels    int
+	sampleRate  int
+	width       int    // bytes per sample
+	compression string // AIFF-C compression type, NONE for plain AIFF
+}
+
+// aiffSource buffers the input until the sound data chunk of an AIFF file
+// begins and then decodes the sample frames.
+type aiffSource struct {
+	header    []byte
+	format    *aiffFormat
+	remaining int64 // bytes left in the sound data chunk, negative when unknown
+	carry     []byte
+}
+
+func (s *aiffSource) write(p []byte) ([]int16, error) {
+	if s.format == nil {
+		s.header = append(s.header, p...)
+		format, start, dataSize, err := readAiffHeader(s.header)
+		if err == io.ErrUnexpectedEOF {
+			if len(s.header) > maxHeaderSize {
+				return nil, errors.New("AIFF header is too large")
+			}
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		s.format = format
+		s.remaining = dataSize
+		p = s.header[start:]
+		s.header = nil
+	}
+
+	if s.remaining >= 0 {
+		if int64(len(p)) > s.remaining {
+			p = p[:s.remaining]
+		}
+		s.remaining -= int64(len(p))
+	}
+
+	data := p
+	if len(s.carry) > 0 {
+		data = append(s.carry, p...)
+		s.carry = nil
+	}
+	frame := s.format.channels * s.format.width
+	n := len(data) / frame * frame
+	if n < len(data) {
+		s.carry = append([]byte(nil), data[n:]...)
+	}
+	return s.format.decode(data[:n]), nil
+}
+
+func (s *aiffSource) close() ([]int16, error) {
+	if s.format == nil && len(s.header) > 0 {
+		return nil, errors.New("truncated AIFF header")
+	}
+	return nil, nil
+}
+
+func (s *aiffSource) channels() int {
+	if s.format == nil {
+		return 1
+	}
+	return s.format.channels
+}
+
+func (s *aiffSource) rate() int {
+	if s.format == nil {
+		return 0
+	}
+	return s.format.sampleRate
+}
+
+// readAiffHeader walks the chunks of an AIFF file up to its sound data. It
+// returns the format, the offset of the first sample and the size of the
+// sound data, which is negative when the file leaves it unset. It returns
+// io.ErrUnexpectedEOF while the header is incomplete.
+func readAiffHeader(b []byte) (*aiffFormat, int, int64, error) {
+	if len(b) < 12 {
+		return nil, 0, 0, io.ErrUnexpectedEOF
+	}
+	kind := string(b[8:12])
+	if string(b[:4]) != "FORM" || (kind != "AIFF" && kind != "AIFC") {
+		return nil, 0, 0, fmt.Errorf("%w: not an AIFF file", ErrUnsupportedFormat)
+	}
+
+	var format *aiffFormat
+	pos := 12
+	for {
+		if len(b) < pos+8 {
+			return nil, 0, 0, io.ErrUnexpectedEOF
+		}
+		size := int(binary.BigEndian.Uint32(b[pos+4:]))
+		body := pos + 8
+		switch string(b[pos : pos+4]) {
+		case "COMM":
+			if len(b) < body+size {
+				return nil, 0, 0, io.ErrUnexpectedEOF
+			}
+			var err error
+			if format, err = parseAiffCommon(b[body:body+size], kind == "AIFC"); err != nil {
+				return nil, 0, 0, err
+			}
+		case "SSND":
+			if format == nil {
+				return nil, 0, 0, fmt.Errorf("%w: AIFF sound data before the common chunk", ErrUnsupportedFormat)
+			}
+			if len(b) < body+8 {
+				return nil, 0, 0, io.ErrUnexpectedEOF
+			}
+			offset := int(binary.BigEndian.Uint32(b[body:]))
+			start := body + 8 + offset
+			if len(b) < start {
+				return nil, 0, 0, io.ErrUnexpectedEOF
+			}
+			// recorders write the sizes once done, if ever
+			dataSize := int64(size - 8 - offset)
+			if size == 0 || dataSize < 0 {
+				dataSize = -1
+			}
+			return format, start, dataSize, nil
+		}
+		// chunks are padded to an even size
+		pos = body + size + size&1
+	}
+}
+
+// parseAiffCommon parses the COMM chunk holding the sample format.
+func parseAiffCommon(c []byte, aifc bool) (*aiffFormat, error) {
+	if len(c) < 18 {
+		return nil, errors.New("truncated AIFF common chunk")
+	}
+	format := &aiffFormat{
+		channels:    int(int16(binary.BigEndian.Uint16(c))),
+		sampleRate:  int(math.Round(extended(c[8:18]))),
+		compression: "NONE",
+	}
+	bits := int(int16(binary.BigEndian.Uint16(c[6:])))
+	if aifc && len(c) >= 22 {
+		format.compression = string(c[18:22])
+	}
+
+	switch format.compression {
+	case "NONE", "twos", "sowt":
+		format.width = (bits + 7) / 8
+		if format.width < 1 || format.width > 4 || (format.compression == "sowt" && format.width == 1) {
+			return nil, fmt.Errorf("%w: AIFF with %d bits per sample", ErrUnsupportedFormat, bits)
+		}
+	case "fl32", "FL32":
+		format.compression, format.width = "fl32", 4
+	case "ulaw", "ULAW":
+		format.compression, format.width = "ulaw", 1
+	case "alaw", "ALAW":
+		format.compression, format.width = "alaw", 1
+	default:
+		return nil, fmt.Errorf("%w: AIFF-C compression %q", ErrUnsupportedFormat, format.compression)
+	}
+	if format.channels <= 0 || format.sampleRate <= 0 {
+		return nil, fmt.Errorf("%w: AIFF with %d channels at %d Hz", ErrUnsupportedFormat, format.channels, format.sampleRate)
+	}
+	return format, nil
+}
+
+// extended converts an 80-bit IEEE 754 extended precision number, which is
+// how AIFF stores the sample rate.
+func extended(b []byte) float64 {
+	exp := int(binary.BigEndian.Uint16(b) & 0x7fff)
+	mantissa := binary.BigEndian.Uint64(b[2:])
+	v := math.Ldexp(float64(mantissa), exp-16383-63)
+	if b[0]&0x80 != 0 {
+		v = -v
+	}
+	return v
+}
+
+// decode converts whole sample frames into interleaved 16-bit samples.
+func (f *aiffFormat) decode(data []byte) []int16 {
+	width := f.width
+	samples := make([]int16, len(data)/width)
+	for i := range samples {
+		b := data[i*width : (i+1)*width]
+		switch f.compression {
+		case "fl32":
+			samples[i] = clamp(float64(math.Float32frombits(binary.BigEndian.Uint32(b))) * math.MaxInt16)
+		case "ulaw":
+			samples[i] = g711.DecodeUlawFrame(b[0])
+		case "alaw":
+			samples[i] = g711.DecodeAlawFrame(b[0])
+		case "sowt":
+			// little-endian, the two most significant bytes come last
+			samples[i] = int16(uint16(b[width-1])<<8 | uint16(b[width-2]))
+		default:
+			if width == 1 {
+				// unlike WAV, 8-bit AIFF samples are signed
+				samples[i] = int16(int8(b[0])) << 8
+			} else {
+				samples[i] = int16(binary.BigEndian.Uint16(b))
+			}
+		}
+	}
+	return samples
+}
+
+// mp3Source runs the MP3 decoder in a goroutine fed through a pipe, since
+// the decoder pulls its input from an io.Reader.
+type mp3Source struct {
+	pw      *io.PipeWriter
+	done    chan struct{}
+	written int
+
+	mu         sync.Mutex
+	out        []int16
+	sampleRate int
+	err        error
+}
+
+func newMp3Source() *mp3Source {
+	pr, pw := io.Pipe()
+	s := &mp3Source{
+		pw:   pw,
+		done: make(chan struct{}),
+	}
+	go s.run(pr)
+	return s
+}
+
+func (s *mp3Source) run(pr *io.PipeReader) {
+	defer close(s.done)
+
+	decoder, err := mp3.NewDecoder(pr)
+	if err == io.EOF {
+		return
+	}
+	if err != nil {
+		s.fail(pr, err)
+		return
+	}
+	s.mu.Lock()
+	s.sampleRate = decoder.SampleRate()
+	s.mu.Unlock()
+
+	// the decoder always produces 16-bit stereo
+	buf := make([]byte, 4608)
+	var carry []byte
+	for {
+		n, err := decoder.Read(buf)
+		if n > 0 {
+			data := append(carry, buf[:n]...)
+			whole := len(data) / 4 * 4
+			samples := Int16s(data[:whole])
+			carry = append([]byte(nil), data[whole:]...)
+			s.mu.Lock()
+			s.out = append(s.out, samples...)
+			s.mu.Unlock()
+		}
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			s.fail(pr, err)
+			return
+		}
+	}
+}
+
+func (s *mp3Source) fail(pr *io.PipeReader, err error) {
+	s.mu.Lock()
+	s.err = fmt.Errorf("invalid MP3 stream: %w", err)
+	s.mu.Unlock()
+	pr.CloseWithError(err)
+}
+
+func (s *mp3Source) take() ([]int16, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := s.out
+	s.out = nil
+	return out, s.err
+}
+
+func (s *mp3Source) write(p []byte) ([]int16, error) {
+	s.written += len(p)
+	if _, err := s.pw.Write(p); err != nil {
+		_, serr := s.take()
+		if serr != nil {
+			return nil, serr
+		}
+		return nil, err
+	}
+	return s.take()
+}
+
+func (s *mp3Source) close() ([]int16, error) {
+	s.pw.Close()
+	<-s.done
+	out, err := s.take()
+	if err == nil && s.written > 0 && s.rate() == 0 {
+		err = fmt.Errorf("%w: no MP3 frames found", ErrUnsupportedFormat)
+	}
+	return out, err
+}
+
+func (s *mp3Source) channels() int {
+	return 2
+}
+
+func (s *mp3Source) rate() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sampleRate
+}
@@ -0,0 +1,410 @@
+package auth
+
+// Copyright (c) 2018 Bhojpur Consulting Private Limited, India. All rights reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// It authenticates the callers of the gRPC servers by API key, bearer token
+// or TLS client certificate, and enforces the limits of their accounts: the
+// number of concurrent streams and the seconds of audio processed per
+// minute. The interceptors of a Guard are shared by all the servers.
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// APIKeyHeader is the metadata key carrying an API key. Bearer tokens are
+// sent in the authorization metadata instead.
+const APIKeyHeader = "x-api-key"
+
+// Key is a credential of an account, along with the account limits.
+type Key struct {
+	// Name identifies the account in the usage records.
+	Name string `json:"name"`
+	// Key is the API key or bearer token. It may be empty for accounts
+	// authenticated by client certificate only.
+	Key string `json:"key,omitempty"`
+	// CommonName is the common name of the client certificate of the
+	// account. When set, the key is only accepted along with this
+	// certificate.
+	CommonName string `json:"common_name,omitempty"`
+	// MaxStreams limits the concurrent streams, zero means no limit.
+	MaxStreams int `json:"max_streams,omitempty"`
+	// AudioSecondsPerMinute limits the audio processed, zero means no
+	// limit. Up to a minute of the allowance is saved up. Audio is accepted
+	// while some allowance is left, so that a recording longer than the
+	// allowance is processed and puts the account in debt, which blocks
+	// its calls until the allowance is earned back.
+	AudioSecondsPerMinute float64 `json:"audio_seconds_per_minute,omitempty"`
+}
+
+// LoadKeys reads a JSON array of keys.
+func LoadKeys(path string) ([]Key, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var keys []Key
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return nil, fmt.Errorf("auth: invalid keys file %s: %w", path, err)
+	}
+	for i, key := range keys {
+		if key.Name == "" {
+			return nil, fmt.Errorf("auth: key %d of %s has no name", i, path)
+		}
+		if key.Key == "" && key.CommonName == "" {
+			return nil, fmt.Errorf("auth: key %q of %s has neither key nor common name", key.Name, path)
+		}
+	}
+	return keys, nil
+}
+
+// Account is the authenticated caller of a call.
+type Account struct {
+	guard *Guard
+	key   Key
+
+	// guarded by guard.mu
+	streams int
+	tokens  float64 // seconds of audio left
+	updated time.Time
+}
+
+// Name returns the name of the account.
+func (a *Account) Name() string {
+	return a.key.Name
+}
+
+// Guard authenticates the calls and enforces the account limits. It is safe
+// for concurrent use.
+type Guard struct {
+	keys        []*Account
+	clientCerts bool
+	public      map[string]bool
+	usage       *UsageStore
+	now         func() time.Time
+
+	mu       sync.Mutex
+	accounts map[string]*Account // accounts of unknown client certificates
+}
+
+// Option configures a Guard.
+type Option func(*Guard)
+
+// WithClientCertificates requires a verified TLS client certificate on
+// every call. Certificates whose common name matches no key authenticate an
+// account of that name without limits, the server's TLS configuration
+// having verified them against the trusted authorities.
+func WithClientCertificates() Option {
+	return func(g *Guard) {
+		g.clientCerts = true
+	}
+}
+
+// WithPublicServices lets anyone call the services, such as the health
+// service probed by the orchestrator. Services are named by their full
+// name, e.g. grpc.health.v1.Health.
+func WithPublicServices(names ...string) Option {
+	return func(g *Guard) {
+		for _, name := range names {
+			g.public[name] = true
+		}
+	}
+}
+
+// WithUsageStore records the usage of the accounts in the store.
+func WithUsageStore(store *UsageStore) Option {
+	return func(g *Guard) {
+		g.usage = store
+	}
+}
+
+// WithClock sets the clock the audio allowances refill with.
+func WithClock(now func() time.Time) Option {
+	return func(g *Guard) {
+		g.now = now
+	}
+}
+
+// New creates a guard accepting the keys.
+func New(keys []Key, opts ...Option) *Guard {
+	g := &Guard{
+		now:      time.Now,
+		public:   map[string]bool{},
+		accounts: map[string]*Account{},
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	for _, key := range keys {
+		g.keys = append(g.keys, &Account{
+			guard:   g,
+			key:     key,
+			tokens:  key.AudioSecondsPerMinute,
+			updated: g.now(),
+		})
+	}
+	return g
+}
+
+// ServerOptions returns the options installing the interceptors of the
+// guard on a gRPC server.
+func (g *Guard) ServerOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(g.UnaryInterceptor()),
+		grpc.ChainStreamInterceptor(g.StreamInterceptor()),
+	}
+}
+
+// UnaryInterceptor authenticates unary calls.
+func (g *Guard) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if g.isPublic(info.FullMethod) {
+			return handler(ctx, req)
+		}
+		account, err := g.admit(ctx, false)
+		if err != nil {
+			return nil, err
+		}
+		return handler(NewContext(ctx, account), req)
+	}
+}
+
+// StreamInterceptor authenticates streams and limits the concurrent streams
+// of the accounts.
+func (g *Guard) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if g.isPublic(info.FullMethod) {
+			return handler(srv, stream)
+		}
+		account, err := g.admit(stream.Context(), true)
+		if err != nil {
+			return err
+		}
+		defer g.release(account)
+		return handler(srv, &accountStream{ServerStream: stream, ctx: NewContext(stream.Context(), account)})
+	}
+}
+
+// isPublic reports whether the method, /service/method, belongs to a public
+// service.
+func (g *Guard) isPublic(method string) bool {
+	service := strings.TrimPrefix(method, "/")
+	if i := strings.LastIndex(service, "/"); i >= 0 {
+		service = service[:i]
+	}
+	return g.public[service]
+}
+
+type accountStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *accountStream) Context() context.Context {
+	return s.ctx
+}
+
+// admit authenticates a call and checks that the account has audio left,
+// and a stream left for streams.
+func (g *Guard) admit(ctx context.Context, stream bool) (*Account, error) {
+	account, err := g.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.refill(account)
+	key := account.key
+	switch {
+	case key.AudioSecondsPerMinute > 0 && account.tokens <= 0:
+		g.record(account.Name(), 0, 0, 1)
+		return nil, status.Errorf(codes.ResourceExhausted, "audio quota of %v seconds per minute exceeded", key.AudioSecondsPerMinute)
+	case stream && key.MaxStreams > 0 && account.streams >= key.MaxStreams:
+		g.record(account.Name(), 0, 0, 1)
+		return nil, status.Errorf(codes.ResourceExhausted, "limit of %d concurrent streams reached", key.MaxStreams)
+	}
+	if stream {
+		account.streams++
+	}
+	g.record(account.Name(), 1, 0, 0)
+	return account, nil
+}
+
+func (g *Guard) release(account *Account) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	account.streams--
+}
+
+// authenticate finds the account of the credentials of a call.
+func (g *Guard) authenticate(ctx context.Context) (*Account, error) {
+	token := credential(ctx)
+	var commonName string
+	if g.clientCerts {
+		commonName = peerCommonName(ctx)
+		if commonName == "" {
+			return nil, status.Error(codes.Unauthenticated, "a verified client certificate is required")
+		}
+	}
+
+	if token != "" {
+		for _, account := range g.keys {
+			key := account.key
+			if key.Key == "" || subtle.ConstantTimeCompare([]byte(key.Key), []byte(token)) != 1 {
+				continue
+			}
+			if g.clientCerts && key.CommonName != "" && key.CommonName != commonName {
+				return nil, status.Error(codes.PermissionDenied, "the key does not belong to the client certificate")
+			}
+			return account, nil
+		}
+		return nil, status.Error(codes.Unauthenticated, "invalid API key")
+	}
+
+	if commonName == "" {
+		return nil, status.Error(codes.Unauthenticated, "missing API key")
+	}
+	for _, account := range g.keys {
+		if account.key.CommonName == commonName {
+			return account, nil
+		}
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	account, ok := g.accounts[commonName]
+	if !ok {
+		account = &Account{guard: g, key: Key{Name: commonName, CommonName: commonName}}
+		g.accounts[commonName] = account
+	}
+	return account, nil
+}
+
+// credential returns the API key or bearer token of a call.
+func credential(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(APIKeyHeader); len(values) > 0 {
+		return strings.TrimSpace(values[0])
+	}
+	for _, value := range md.Get("authorization") {
+		if fields := strings.Fields(value); len(fields) == 2 && strings.EqualFold(fields[0], "bearer") {
+			return fields[1]
+		}
+	}
+	return ""
+}
+
+// peerCommonName returns the common name of the verified client certificate
+// of a call.
+func peerCommonName(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(info.State.VerifiedChains) == 0 || len(info.State.VerifiedChains[0]) == 0 {
+		return ""
+	}
+	return info.State.VerifiedChains[0][0].Subject.CommonName
+}
+
+// refill adds the audio allowance earned since the last update.
+func (g *Guard) refill(account *Account) {
+	limit := account.key.AudioSecondsPerMinute
+	if limit <= 0 {
+		return
+	}
+	now := g.now()
+	account.tokens += now.Sub(account.updated).Seconds() * limit / 60
+	if account.tokens > limit {
+		account.tokens = limit
+	}
+	account.updated = now
+}
+
+func (g *Guard) record(name string, calls int64, seconds float64, rejected int64) {
+	if g.usage != nil {
+		g.usage.Record(name, calls, seconds, rejected)
+	}
+}
+
+// charge takes seconds of audio from the allowance of the account. The
+// allowance may go negative, only an exhausted one refuses audio.
+func (g *Guard) charge(account *Account, seconds float64) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.refill(account)
+	limit := account.key.AudioSecondsPerMinute
+	if limit > 0 && account.tokens <= 0 {
+		g.record(account.Name(), 0, 0, 1)
+		return status.Errorf(codes.ResourceExhausted, "audio quota of %v seconds per minute exceeded", limit)
+	}
+	account.tokens -= seconds
+	g.record(account.Name(), 0, seconds, 0)
+	return nil
+}
+
+type accountKey struct{}
+
+// NewContext returns a context carrying the account.
+func NewContext(ctx context.Context, account *Account) context.Context {
+	return context.WithValue(ctx, accountKey{}, account)
+}
+
+// FromContext returns the account of a call.
+func FromContext(ctx context.Context) (*Account, bool) {
+	account, ok := ctx.Value(accountKey{}).(*Account)
+	return account, ok
+}
+
+// Detach returns a context which is never canceled but carries the account
+// of ctx, for the work outliving a call.
+func Detach(ctx context.Context) context.Context {
+	detached := context.Background()
+	if account, ok := FromContext(ctx); ok {
+		detached = NewContext(detached, account)
+	}
+	return detached
+}
+
+// Charge takes the seconds of audio processed by a call from the allowance
+// of its account. It returns a RESOURCE_EXHAUSTED error when the allowance
+// is exceeded, and does nothing for calls without an account.
+func Charge(ctx context.Context, seconds float64) error {
+	account, ok := FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	return account.guard.charge(account, seconds)
+}
@@ -0,0 +1,257 @@
+package auth
+
+// Copyright (c) 2018 Bhojpur Consulting Private Limited, India. All rights reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	return c.now
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+func withKey(key string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs(APIKeyHeader, key))
+}
+
+func withCertificate(ctx context.Context, commonName string) context.Context {
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: commonName}}
+	return peer.NewContext(ctx, &peer.Peer{AuthInfo: credentials.TLSInfo{
+		State: tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}},
+	}})
+}
+
+func TestUnaryInterceptor(t *testing.T) {
+	guard := New([]Key{{Name: "alice", Key: "secret"}})
+	interceptor := guard.UnaryInterceptor()
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		account, ok := FromContext(ctx)
+		require.True(t, ok)
+		return account.Name(), nil
+	}
+
+	name, err := interceptor(withKey("secret"), nil, &grpc.UnaryServerInfo{}, handler)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", name)
+
+	bearer := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer secret"))
+	name, err = interceptor(bearer, nil, &grpc.UnaryServerInfo{}, handler)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", name)
+
+	_, err = interceptor(withKey("wrong"), nil, &grpc.UnaryServerInfo{}, handler)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = interceptor(context.Background(), nil, &grpc.UnaryServerInfo{}, handler)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestPublicServices(t *testing.T) {
+	guard := New(nil, WithPublicServices("grpc.health.v1.Health"))
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		_, ok := FromContext(ctx)
+		return ok, nil
+	}
+
+	ok, err := guard.UnaryInterceptor()(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}, handler)
+	require.NoError(t, err)
+	assert.Equal(t, false, ok)
+	_, err = guard.UnaryInterceptor()(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/speech.Service/Check"}, handler)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestClientCertificates(t *testing.T) {
+	guard := New([]Key{{Name: "alice", Key: "secret", CommonName: "alice.example.com"}}, WithClientCertificates())
+	interceptor := guard.UnaryInterceptor()
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		account, _ := FromContext(ctx)
+		return account.Name(), nil
+	}
+
+	_, err := interceptor(withKey("secret"), nil, &grpc.UnaryServerInfo{}, handler)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	name, err := interceptor(withCertificate(withKey("secret"), "alice.example.com"), nil, &grpc.UnaryServerInfo{}, handler)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", name)
+
+	_, err = interceptor(withCertificate(withKey("secret"), "mallory.example.com"), nil, &grpc.UnaryServerInfo{}, handler)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	name, err = interceptor(withCertificate(context.Background(), "bob.example.com"), nil, &grpc.UnaryServerInfo{}, handler)
+	require.NoError(t, err)
+	assert.Equal(t, "bob.example.com", name)
+}
+
+func TestMaxStreams(t *testing.T) {
+	guard := New([]Key{{Name: "alice", Key: "secret", MaxStreams: 1}})
+	interceptor := guard.StreamInterceptor()
+
+	entered := make(chan struct{})
+	leave := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- interceptor(nil, &serverStream{ctx: withKey("secret")}, &grpc.StreamServerInfo{}, func(srv interface{}, stream grpc.ServerStream) error {
+			close(entered)
+			<-leave
+			return nil
+		})
+	}()
+	<-entered
+
+	handler := func(srv interface{}, stream grpc.ServerStream) error {
+		_, ok := FromContext(stream.Context())
+		assert.True(t, ok)
+		return nil
+	}
+	err := interceptor(nil, &serverStream{ctx: withKey("secret")}, &grpc.StreamServerInfo{}, handler)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	close(leave)
+	require.NoError(t, <-done)
+	assert.NoError(t, interceptor(nil, &serverStream{ctx: withKey("secret")}, &grpc.StreamServerInfo{}, handler))
+}
+
+func TestCharge(t *testing.T) {
+	c := &clock{now: time.Unix(1000, 0)}
+	usage, err := OpenUsage("")
+	require.NoError(t, err)
+	guard := New([]Key{{Name: "alice", Key: "secret", AudioSecondsPerMinute: 60}}, WithClock(c.Now), WithUsageStore(usage))
+	interceptor := guard.UnaryInterceptor()
+
+	var ctx context.Context
+	_, err = interceptor(withKey("secret"), nil, &grpc.UnaryServerInfo{}, func(c context.Context, req interface{}) (interface{}, error) {
+		ctx = c
+		return nil, nil
+	})
+	require.NoError(t, err)
+
+	assert.NoError(t, Charge(ctx, 45))
+	// the last fifteen seconds admit a longer recording, leaving a debt
+	assert.NoError(t, Charge(ctx, 30))
+	assert.Equal(t, codes.ResourceExhausted, status.Code(Charge(ctx, 1)))
+
+	// fifteen seconds pay the debt back, fifteen more earn an allowance
+	c.now = c.now.Add(15 * time.Second)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(Charge(ctx, 1)))
+	c.now = c.now.Add(15 * time.Second)
+	assert.NoError(t, Charge(ctx, 90))
+	_, err = interceptor(withKey("secret"), nil, &grpc.UnaryServerInfo{}, func(context.Context, interface{}) (interface{}, error) {
+		return nil, nil
+	})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	u, ok := usage.Get("alice")
+	require.True(t, ok)
+	assert.Equal(t, int64(1), u.Calls)
+	assert.Equal(t, 165.0, u.AudioSeconds)
+	assert.Equal(t, int64(3), u.Rejected)
+
+	assert.NoError(t, Charge(context.Background(), 1000))
+}
+
+func TestUsageStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "usage.json")
+	usage, err := OpenUsage(path, WithFlushInterval(0))
+	require.NoError(t, err)
+	usage.Record("bob", 1, 2.5, 0)
+	usage.Record("alice", 2, 1, 1)
+	require.NoError(t, usage.Close())
+
+	usage, err = OpenUsage(path)
+	require.NoError(t, err)
+	defer usage.Close()
+	list := usage.List()
+	require.Len(t, list, 2)
+	assert.Equal(t, "alice", list[0].Account)
+	assert.Equal(t, int64(2), list[0].Calls)
+	assert.Equal(t, 2.5, list[1].AudioSeconds)
+}
+
+func TestLoadKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	require.NoError(t, ioutil.WriteFile(path, []byte(`[{"name": "alice", "key": "secret", "max_streams": 2}]`), 0644))
+	keys, err := LoadKeys(path)
+	require.NoError(t, err)
+	assert.Equal(t, []Key{{Name: "alice", Key: "secret", MaxStreams: 2}}, keys)
+
+	require.NoError(t, ioutil.WriteFile(path, []byte(`[{"name": "alice"}]`), 0644))
+	_, err = LoadKeys(path)
+	assert.Error(t, err)
+}
+
+func TestFromEnv(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "private.key")
+
+	// without keys nor authorities, nothing is authenticated
+	t.Setenv("CLIENT_CA", "")
+	t.Setenv("API_KEYS", "")
+	opts, guard, closer, err := FromEnv(certFile, keyFile)
+	require.NoError(t, err)
+	assert.Empty(t, opts)
+	assert.Nil(t, guard)
+	assert.NoError(t, closer.Close())
+
+	keys := filepath.Join(dir, "keys.json")
+	require.NoError(t, ioutil.WriteFile(keys, []byte(`[{"name": "alice", "key": "secret"}]`), 0644))
+	t.Setenv("API_KEYS", keys)
+	t.Setenv("USAGE", filepath.Join(dir, "usage.json"))
+	opts, guard, closer, err = FromEnv(certFile, keyFile, WithPublicServices("grpc.health.v1.Health"))
+	require.NoError(t, err)
+	assert.Len(t, opts, 2)
+	require.NotNil(t, guard)
+	assert.True(t, guard.isPublic("/grpc.health.v1.Health/Check"))
+	assert.NoError(t, closer.Close())
+
+	// client certificates require the server certificate
+	t.Setenv("CLIENT_CA", filepath.Join(dir, "ca.pem"))
+	_, _, _, err = FromEnv(certFile, keyFile)
+	assert.Error(t, err)
+}
@@ -0,0 +1,81 @@
+package auth
+
+// Copyright (c) 2018 Bhojpur Consulting Private Limited, India. All rights reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// FromEnv sets up the authentication of a server from the environment:
+// CLIENT_CA is the file of the authorities whose client certificates are
+// accepted, API_KEYS the file of the keys and USAGE the file storing the
+// usage. The server options install the TLS credentials of certFile and
+// keyFile, which are optional without CLIENT_CA, and the guard. The guard,
+// given opts, is nil when neither CLIENT_CA nor API_KEYS is set. The closer
+// saves the usage and must be closed once the server stopped.
+func FromEnv(certFile, keyFile string, opts ...Option) ([]grpc.ServerOption, *Guard, io.Closer, error) {
+	var serverOpts []grpc.ServerOption
+	clientCA := os.Getenv("CLIENT_CA")
+	if clientCA != "" {
+		// only clients holding a certificate of the authority are accepted
+		creds, err := ServerTLS(certFile, keyFile, clientCA)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("auth: failed to load TLS credentials: %w", err)
+		}
+		serverOpts = append(serverOpts, grpc.Creds(creds))
+	} else if creds, err := credentials.NewServerTLSFromFile(certFile, keyFile); err == nil {
+		serverOpts = append(serverOpts, grpc.Creds(creds))
+	}
+
+	keysPath := os.Getenv("API_KEYS")
+	if keysPath == "" && clientCA == "" {
+		return serverOpts, nil, nopCloser{}, nil
+	}
+	var keys []Key
+	if keysPath != "" {
+		var err error
+		keys, err = LoadKeys(keysPath)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	usage, err := OpenUsage(os.Getenv("USAGE"))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	guardOpts := append([]Option{WithUsageStore(usage)}, opts...)
+	if clientCA != "" {
+		guardOpts = append(guardOpts, WithClientCertificates())
+	}
+	guard := New(keys, guardOpts...)
+	return append(serverOpts, guard.ServerOptions()...), guard, usage, nil
+}
+
+type nopCloser struct{}
+
+func (nopCloser) Close() error {
+	return nil
+}
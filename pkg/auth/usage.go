@@ -0,0 +1,210 @@
+package auth
+
+// Copyright (c) 2018 Bhojpur Consulting Private Limited, India. All rights reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultFlushInterval is the interval the usage is written to its file at.
+const DefaultFlushInterval = 10 * time.Second
+
+// Usage is the accounting of an account.
+type Usage struct {
+	Account string `json:"account"`
+	// Calls counts the calls and streams admitted.
+	Calls int64 `json:"calls"`
+	// AudioSeconds sums the seconds of audio processed.
+	AudioSeconds float64 `json:"audio_seconds"`
+	// Rejected counts the calls refused or stopped over quota.
+	Rejected int64 `json:"rejected"`
+	// LastUsed is the time of the last call.
+	LastUsed time.Time `json:"last_used"`
+}
+
+// UsageStore keeps the usage of the accounts in a JSON file. The usage is
+// written every flush interval and on Close. It is safe for concurrent use.
+type UsageStore struct {
+	path     string
+	interval time.Duration
+	now      func() time.Time
+
+	mu    sync.Mutex
+	usage map[string]*Usage
+	dirty bool
+
+	done   chan struct{}
+	closed sync.WaitGroup
+}
+
+// UsageOption configures a UsageStore.
+type UsageOption func(*UsageStore)
+
+// WithFlushInterval sets the interval the usage is written at.
+func WithFlushInterval(interval time.Duration) UsageOption {
+	return func(s *UsageStore) {
+		s.interval = interval
+	}
+}
+
+// OpenUsage loads the usage stored in the JSON file path. A missing file is
+// an empty store; the empty path keeps the usage in memory only.
+func OpenUsage(path string, opts ...UsageOption) (*UsageStore, error) {
+	s := &UsageStore{
+		path:     path,
+		interval: DefaultFlushInterval,
+		now:      time.Now,
+		usage:    map[string]*Usage{},
+		done:     make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if path != "" {
+		data, err := ioutil.ReadFile(path)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		if err == nil {
+			var usage []*Usage
+			if err := json.Unmarshal(data, &usage); err != nil {
+				return nil, fmt.Errorf("auth: invalid usage file %s: %w", path, err)
+			}
+			for _, u := range usage {
+				s.usage[u.Account] = u
+			}
+		}
+	}
+
+	if path != "" && s.interval > 0 {
+		s.closed.Add(1)
+		go s.flush()
+	}
+	return s, nil
+}
+
+// Record adds to the usage of an account.
+func (s *UsageStore) Record(account string, calls int64, seconds float64, rejected int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u, ok := s.usage[account]
+	if !ok {
+		u = &Usage{Account: account}
+		s.usage[account] = u
+	}
+	u.Calls += calls
+	u.AudioSeconds += seconds
+	u.Rejected += rejected
+	u.LastUsed = s.now()
+	s.dirty = true
+}
+
+// Get returns the usage of an account.
+func (s *UsageStore) Get(account string) (Usage, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u, ok := s.usage[account]
+	if !ok {
+		return Usage{}, false
+	}
+	return *u, true
+}
+
+// List returns the usage of all the accounts sorted by account.
+func (s *UsageStore) List() []Usage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	usage := make([]Usage, 0, len(s.usage))
+	for _, u := range s.usage {
+		usage = append(usage, *u)
+	}
+	sort.Slice(usage, func(i, j int) bool { return usage[i].Account < usage[j].Account })
+	return usage
+}
+
+// Save writes the usage if it changed since the last write.
+func (s *UsageStore) Save() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.path == "" || !s.dirty {
+		return nil
+	}
+	usage := make([]*Usage, 0, len(s.usage))
+	for _, u := range s.usage {
+		usage = append(usage, u)
+	}
+	sort.Slice(usage, func(i, j int) bool { return usage[i].Account < usage[j].Account })
+	data, err := json.MarshalIndent(usage, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return err
+	}
+	s.dirty = false
+	return nil
+}
+
+// Close stops the periodic writes and writes the usage a last time.
+func (s *UsageStore) Close() error {
+	select {
+	case <-s.done:
+	default:
+		close(s.done)
+	}
+	s.closed.Wait()
+	return s.Save()
+}
+
+func (s *UsageStore) flush() {
+	defer s.closed.Done()
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.Save()
+		case <-s.done:
+			return
+		}
+	}
+}
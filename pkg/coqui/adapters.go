@@ -0,0 +1,129 @@
+package coqui
+
+// Copyright (c) 2018 Bhojpur Consulting Private Limited, India. All rights reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"context"
+	"encoding/binary"
+	"time"
+)
+
+// Writer feeds 16-bit little-endian mono PCM to a Stream. A sample split
+// between two writes is fed once complete.
+type Writer struct {
+	stream  *Stream
+	pending []byte // the first byte of a split sample
+}
+
+// NewWriter returns a Writer feeding the stream.
+func NewWriter(stream *Stream) *Writer {
+	return &Writer{stream: stream}
+}
+
+// Write feeds the samples of p. It fails once the stream is finished.
+func (w *Writer) Write(p []byte) (int, error) {
+	n := len(p)
+	if len(w.pending) > 0 && len(p) > 0 {
+		p = append(w.pending, p...)
+		w.pending = nil
+	}
+	buffer := make([]int16, len(p)/2)
+	for i := range buffer {
+		buffer[i] = int16(binary.LittleEndian.Uint16(p[2*i:]))
+	}
+	if len(p)%2 == 1 {
+		w.pending = []byte{p[len(p)-1]}
+	}
+	if err := w.stream.FeedAudioContent(buffer); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// fedSamples returns the number of samples fed.
+func (s *Stream) fedSamples() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.fed
+}
+
+// IntermediateResult is an intermediate decoding of a stream.
+type IntermediateResult struct {
+	Text string
+	// Audio is the duration of the audio decoded.
+	Audio time.Duration
+	Err   error
+}
+
+// IntermediateResults decodes the stream every time at least the duration
+// every of audio was fed since the previous decoding, and sends the text
+// when it changed. The channel is closed when the stream is finished, when the
+// context is done, or after a result carrying an error. The sample rate is
+// the one of the model.
+func (s *Stream) IntermediateResults(ctx context.Context, sampleRate int, every time.Duration) <-chan IntermediateResult {
+	results := make(chan IntermediateResult)
+	step := int64(every.Seconds() * float64(sampleRate))
+	if step < 1 {
+		step = 1
+	}
+	go func() {
+		defer close(results)
+		var decoded int64
+		var last string
+		for {
+			select {
+			case <-s.fedc:
+			case <-s.done:
+				return
+			case <-ctx.Done():
+				return
+			}
+			fed := s.fedSamples()
+			if fed-decoded < step {
+				continue
+			}
+
+			text, err := s.IntermediateDecode()
+			if err == ErrStreamFinished {
+				return
+			}
+			decoded = fed
+			if err == nil && text == last {
+				continue
+			}
+			last = text
+			res := IntermediateResult{
+				Text:  text,
+				Audio: time.Duration(fed) * time.Second / time.Duration(sampleRate),
+				Err:   err,
+			}
+			select {
+			case results <- res:
+			case <-ctx.Done():
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	return results
+}
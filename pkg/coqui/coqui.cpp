@@ -81,6 +81,21 @@ extern "C" {
                 return STT_SpeechToTextWithMetadata(model, aBuffer, aBufferSize, aNumResults);
             }
 
+            int addHotWord(const char* aWord, float aBoost)
+            {
+                return STT_AddHotWord(model, aWord, aBoost);
+            }
+
+            int eraseHotWord(const char* aWord)
+            {
+                return STT_EraseHotWord(model, aWord);
+            }
+
+            int clearHotWords()
+            {
+                return STT_ClearHotWords(model);
+            }
+
             ModelState* getModel()
             {
                 return model;
@@ -131,6 +146,21 @@ extern "C" {
         return w->setScorerAlphaBeta(aAlpha, aBeta);
     }
 
+    int Model_AddHotWord(ModelWrapper* w, const char* aWord, float aBoost)
+    {
+        return w->addHotWord(aWord, aBoost);
+    }
+
+    int Model_EraseHotWord(ModelWrapper* w, const char* aWord)
+    {
+        return w->eraseHotWord(aWord);
+    }
+
+    int Model_ClearHotWords(ModelWrapper* w)
+    {
+        return w->clearHotWords();
+    }
+
     char* Model_STT(ModelWrapper* w, const short* aBuffer, unsigned int aBufferSize)
     {
         return w->stt(aBuffer, aBufferSize);
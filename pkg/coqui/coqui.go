@@ -29,6 +29,7 @@ package coqui
 import "C"
 import (
 	"errors"
+	"sync"
 	"unsafe"
 )
 
@@ -94,18 +95,42 @@ func (m *Model) SetScorerAlphaBeta(alpha, beta float32) error {
 	return errorFromCode(C.Model_SetScorerAlphaBeta(m.w, C.float(alpha), C.float(beta)))
 }
 
-// sliceHeader represents a slice header
-type sliceHeader struct {
-	Data uintptr
-	Len  int
-	Cap  int
+// AddHotWord adds a hot-word, or updates its boost. A positive boost makes
+// the word more likely to be recognized, a negative one less likely, while
+// an excessive boost tends to split the word following the hot-word into
+// letters. Hot-words apply to all the streams of the model, and need an
+// external scorer.
+func (m *Model) AddHotWord(word string, boost float32) error {
+	cWord := C.CString(word)
+	defer C.free(unsafe.Pointer(cWord))
+	return errorFromCode(C.Model_AddHotWord(m.w, cWord, C.float(boost)))
+}
+
+// EraseHotWord removes a hot-word.
+func (m *Model) EraseHotWord(word string) error {
+	cWord := C.CString(word)
+	defer C.free(unsafe.Pointer(cWord))
+	return errorFromCode(C.Model_EraseHotWord(m.w, cWord))
+}
+
+// ClearHotWords removes all the hot-words.
+func (m *Model) ClearHotWords() error {
+	return errorFromCode(C.Model_ClearHotWords(m.w))
+}
+
+// samples returns the address of the first sample of a buffer, nil when
+// it is empty.
+func samples(buffer []int16) *C.short {
+	if len(buffer) == 0 {
+		return nil
+	}
+	return (*C.short)(unsafe.Pointer(&buffer[0]))
 }
 
 // SpeechToText uses the model to convert speech to text.
 // buffer is 16-bit, mono raw audio signal at the appropriate sample rate (matching what the model was trained on).
 func (m *Model) SpeechToText(buffer []int16) (string, error) {
-	hdr := (*sliceHeader)(unsafe.Pointer(&buffer))
-	str := C.Model_STT(m.w, (*C.short)(unsafe.Pointer(hdr.Data)), C.uint(hdr.Len))
+	str := C.Model_STT(m.w, samples(buffer), C.uint(len(buffer)))
 	if str == nil {
 		return "", errors.New("conversion failed")
 	}
@@ -177,20 +202,28 @@ func (m *Metadata) Close() {
 // numResults is the maximum number of CandidateTranscript structs to return. Returned value might be smaller than this.
 // If an error is not returned, the returned metadata's Close method must be called later to free resources.
 func (m *Model) SpeechToTextWithMetadata(buffer []int16, numResults uint) (*Metadata, error) {
-	hdr := (*sliceHeader)(unsafe.Pointer(&buffer))
 	md := (*Metadata)(unsafe.Pointer(C.Model_STTWithMetadata(
-		m.w, (*C.short)(unsafe.Pointer(hdr.Data)), C.uint(hdr.Len), C.uint(numResults))))
+		m.w, samples(buffer), C.uint(len(buffer)), C.uint(numResults))))
 	if md == nil {
 		return nil, errors.New("conversion failed")
 	}
 	return md, nil
 }
 
-// Stream represents a streaming inference state.
+// Stream represents a streaming inference state. Its methods are safe for
+// concurrent use, so that IntermediateResults decodes while the audio is
+// fed.
 type Stream struct {
-	sw *C.StreamWrapper
+	mu   sync.Mutex
+	sw   *C.StreamWrapper
+	fed  int64         // samples fed so far
+	fedc chan struct{} // signaled when samples are fed
+	done chan struct{} // closed once the stream is finished or discarded
 }
 
+// ErrStreamFinished is returned when using a finished or discarded stream.
+var ErrStreamFinished = errors.New("stream already finished")
+
 // NewStream creates a new streaming inference state.
 // If an error is not returned, exactly one of the returned stream's Finish,
 // FinishWithMetadata, or Discard methods must be called later to free resources.
@@ -200,15 +233,33 @@ func (m *Model) NewStream() (*Stream, error) {
 	if ret != 0 {
 		return nil, errorFromCode(ret)
 	}
-	return &Stream{sw}, nil
+	return &Stream{
+		sw:   sw,
+		fedc: make(chan struct{}, 1),
+		done: make(chan struct{}),
+	}, nil
 }
 
 // FeedAudioContent feeds audio samples to an ongoing streaming inference.
 // buffer is an array of 16-bit, mono raw audio samples at the appropriate sample rate
-// (matching what the model was trained on).
-func (s *Stream) FeedAudioContent(buffer []int16) {
-	hdr := (*sliceHeader)(unsafe.Pointer(&buffer))
-	C.Stream_FeedAudioContent(s.sw, (*C.short)(unsafe.Pointer(hdr.Data)), C.uint(hdr.Len))
+// (matching what the model was trained on). It returns ErrStreamFinished
+// once the stream is finished or discarded.
+func (s *Stream) FeedAudioContent(buffer []int16) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.sw == nil {
+		return ErrStreamFinished
+	}
+	if len(buffer) == 0 {
+		return nil
+	}
+	C.Stream_FeedAudioContent(s.sw, samples(buffer), C.uint(len(buffer)))
+	s.fed += int64(len(buffer))
+	select {
+	case s.fedc <- struct{}{}:
+	default:
+	}
+	return nil
 }
 
 // IntermediateDecode computes the intermediate decoding of an ongoing streaming inference.
@@ -216,6 +267,11 @@ func (s *Stream) FeedAudioContent(buffer []int16) {
 // currently capable of streaming, so it always starts from the beginning
 // of the audio.
 func (s *Stream) IntermediateDecode() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.sw == nil {
+		return "", ErrStreamFinished
+	}
 	// STT_IntermediateDecode isn't documented as returning null, but future-proofing this seems safer.
 	str := C.Stream_IntermediateDecode(s.sw)
 	if str == nil {
@@ -230,6 +286,11 @@ func (s *Stream) IntermediateDecode() (string, error) {
 // numResults is the number of candidate transcripts to return.
 // If an error is not returned, the metadata's Close method must be called.
 func (s *Stream) IntermediateDecodeWithMetadata(numResults uint) (*Metadata, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.sw == nil {
+		return nil, ErrStreamFinished
+	}
 	md := (*Metadata)(unsafe.Pointer(C.Stream_IntermediateDecodeWithMetadata(s.sw, C.uint(numResults))))
 	if md == nil {
 		return nil, errors.New("decoding failed")
@@ -240,9 +301,14 @@ func (s *Stream) IntermediateDecodeWithMetadata(numResults uint) (*Metadata, err
 // Finish computes the final decoding of an ongoing streaming inference and returns the result.
 // This signals the end of an ongoing streaming inference.
 func (s *Stream) Finish() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.sw == nil {
+		return "", ErrStreamFinished
+	}
 	// STT_FinishStream isn't documented as returning null, but future-proofing this seems safer.
 	str := C.Stream_Finish(s.sw) // deletes s.sw
-	s.sw = nil
+	s.finished()
 
 	if str == nil {
 		return "", errors.New("decoding failed")
@@ -255,8 +321,13 @@ func (s *Stream) Finish() (string, error) {
 // results including metadata. This signals the end of an ongoing streaming inference.
 // If an error is not returned, the metadata's Close method must be called.
 func (s *Stream) FinishWithMetadata(numResults uint) (*Metadata, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.sw == nil {
+		return nil, ErrStreamFinished
+	}
 	md := (*Metadata)(unsafe.Pointer(C.Stream_FinishWithMetadata(s.sw, C.uint(numResults)))) // deletes s.sw
-	s.sw = nil
+	s.finished()
 
 	if md == nil {
 		return nil, errors.New("decoding failed")
@@ -268,8 +339,19 @@ func (s *Stream) FinishWithMetadata(numResults uint) (*Metadata, error) {
 // This can be used if you no longer need the result of an ongoing streaming
 // inference and don't want to perform a costly decode operation.
 func (s *Stream) Discard() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.sw == nil {
+		return
+	}
 	C.Stream_Discard(s.sw) // deletes s.sw
+	s.finished()
+}
+
+// finished forgets the deleted state. It must be called with s.mu held.
+func (s *Stream) finished() {
 	s.sw = nil
+	close(s.done)
 }
 
 // Version returns the version of the C library.
@@ -47,6 +47,9 @@ extern "C" {
     int Model_EnableExternalScorer(ModelWrapper* w, const char* aScorerPath);
     int Model_DisableExternalScorer(ModelWrapper* w);
     int Model_SetScorerAlphaBeta(ModelWrapper* w, float aAlpha, float aBeta);
+    int Model_AddHotWord(ModelWrapper* w, const char* aWord, float aBoost);
+    int Model_EraseHotWord(ModelWrapper* w, const char* aWord);
+    int Model_ClearHotWords(ModelWrapper* w);
     char* Model_STT(ModelWrapper* w, const short* aBuffer, unsigned int aBufferSize);
     Metadata* Model_STTWithMetadata(ModelWrapper* w, const short* aBuffer, unsigned int aBufferSize, unsigned int aNumResults);
 
@@ -0,0 +1,242 @@
+package coqui
+
+// Copyright (c) 2018 Bhojpur Consulting Private Limited, India. All rights reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/bhojpur/speech/pkg/asr"
+)
+
+func init() {
+	asr.Register("coqui", Open)
+}
+
+// timestep is the duration of a Coqui token timestep.
+const timestep = 20 * time.Millisecond
+
+// Engine adapts a Coqui Model to the asr.Engine interface. Coqui has no
+// endpoint detection, so its recognizers only produce results on
+// FinalResult.
+type Engine struct {
+	model *Model
+}
+
+// Open loads the Coqui model graph found at path.
+func Open(path string) (asr.Engine, error) {
+	model, err := New(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewEngine(model), nil
+}
+
+// NewEngine wraps an already loaded model. The engine takes the ownership of
+// the model and closes it on Close.
+func NewEngine(model *Model) *Engine {
+	return &Engine{model: model}
+}
+
+// Model returns the underlying Coqui model.
+func (e *Engine) Model() *Model {
+	return e.model
+}
+
+// Name implements asr.Engine.
+func (e *Engine) Name() string {
+	return "coqui"
+}
+
+// SampleRate implements asr.Engine.
+func (e *Engine) SampleRate() float64 {
+	return float64(e.model.SampleRate())
+}
+
+// NewRecognizer implements asr.Engine. Coqui can not resample, the audio
+// must be at the model's sample rate.
+func (e *Engine) NewRecognizer(config asr.Config) (asr.Recognizer, error) {
+	if config.SampleRate > 0 && config.SampleRate != e.SampleRate() {
+		return nil, fmt.Errorf("coqui: sample rate %v does not match the model sample rate %v", config.SampleRate, e.SampleRate())
+	}
+	if config.Speaker {
+		return nil, asr.ErrNoSpeakerModel
+	}
+	if len(config.Grammar) > 0 {
+		return nil, asr.ErrNoGrammar
+	}
+	return &engineRecognizer{model: e.model, config: config}, nil
+}
+
+// Close implements asr.Engine.
+func (e *Engine) Close() error {
+	e.model.Close()
+	return nil
+}
+
+// engineRecognizer adapts a Coqui Stream to the asr.Recognizer interface.
+// The stream is created lazily and recreated after every final result.
+type engineRecognizer struct {
+	model  *Model
+	config asr.Config
+	stream *Stream
+	writer *Writer
+	offset time.Duration // position of the current stream in the audio
+	fed    int64         // samples fed to the current stream
+}
+
+func (r *engineRecognizer) AcceptWaveform(pcm []byte) (bool, error) {
+	if r.stream == nil {
+		stream, err := r.model.NewStream()
+		if err != nil {
+			return false, err
+		}
+		r.stream = stream
+		r.writer = NewWriter(stream)
+		r.fed = 0
+	}
+	if _, err := r.writer.Write(pcm); err != nil {
+		return false, err
+	}
+	r.fed = r.stream.fedSamples()
+	return false, nil
+}
+
+func (r *engineRecognizer) Result() (*asr.Result, error) {
+	return r.FinalResult()
+}
+
+func (r *engineRecognizer) PartialResult() (*asr.Result, error) {
+	res := &asr.Result{}
+	if r.stream == nil {
+		return res, nil
+	}
+	text, err := r.stream.IntermediateDecode()
+	if err != nil {
+		return nil, err
+	}
+	if text != "" {
+		res.Alternatives = []asr.Alternative{{Text: text}}
+	}
+	return res, nil
+}
+
+func (r *engineRecognizer) FinalResult() (*asr.Result, error) {
+	res := &asr.Result{Final: true}
+	if r.stream == nil {
+		return res, nil
+	}
+
+	n := r.config.MaxAlternatives
+	if n < 1 {
+		n = 1
+	}
+	md, err := r.stream.FinishWithMetadata(uint(n))
+	r.stream = nil
+	offset := r.offset
+	r.offset += time.Duration(float64(r.fed) / float64(r.model.SampleRate()) * float64(time.Second))
+	if err != nil {
+		return nil, err
+	}
+	defer md.Close()
+
+	transcripts := md.Transcripts()
+	for i := range transcripts {
+		alt := transcript(&transcripts[i], offset)
+		if alt.Text == "" {
+			continue
+		}
+		if !r.config.Words {
+			alt.Words = nil
+		}
+		res.Alternatives = append(res.Alternatives, alt)
+	}
+	return res, nil
+}
+
+func (r *engineRecognizer) Reset() error {
+	if r.stream != nil {
+		r.stream.Discard()
+		r.stream = nil
+		r.offset += time.Duration(float64(r.fed) / float64(r.model.SampleRate()) * float64(time.Second))
+	}
+	return nil
+}
+
+func (r *engineRecognizer) Close() error {
+	if r.stream != nil {
+		r.stream.Discard()
+		r.stream = nil
+	}
+	return nil
+}
+
+// transcript groups the character tokens of a candidate transcript into
+// words. Coqui reports a log-likelihood as confidence, it is converted into
+// the per-token geometric mean probability so that it is comparable with the
+// confidence of the other engines.
+func transcript(tr *CandidateTranscript, offset time.Duration) asr.Alternative {
+	tokens := tr.Tokens()
+
+	var alt asr.Alternative
+	var word strings.Builder
+	var start, end time.Duration
+	flush := func() {
+		if word.Len() == 0 {
+			return
+		}
+		alt.Words = append(alt.Words, asr.Word{
+			Word:  word.String(),
+			Start: start,
+			End:   end,
+		})
+		word.Reset()
+	}
+	for i := range tokens {
+		text := tokens[i].Text()
+		at := offset + time.Duration(float64(tokens[i].StartTime())*float64(time.Second))
+		if text == " " {
+			flush()
+			continue
+		}
+		if word.Len() == 0 {
+			start = at
+		}
+		word.WriteString(text)
+		end = at + timestep
+	}
+	flush()
+
+	texts := make([]string, len(alt.Words))
+	for i, w := range alt.Words {
+		texts[i] = w.Word
+	}
+	alt.Text = strings.Join(texts, " ")
+	if len(tokens) > 0 {
+		alt.Confidence = math.Exp(tr.Confidence() / float64(len(tokens)))
+	}
+	for i := range alt.Words {
+		alt.Words[i].Confidence = alt.Confidence
+	}
+	return alt
+}
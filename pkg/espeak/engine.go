@@ -0,0 +1,132 @@
+package espeak
+
+// Copyright (c) 2018 Bhojpur Consulting Private Limited, India. All rights reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"errors"
+	"os"
+	"strings"
+
+	"github.com/bhojpur/speech/pkg/tts"
+)
+
+func init() {
+	tts.Register("espeak", Open)
+}
+
+// Engine adapts eSpeak to the tts.Engine interface. eSpeak having a single
+// global state, the syntheses of all the engines run one at a time.
+type Engine struct {
+	path       *string
+	sampleRate int
+	voices     []tts.Voice
+}
+
+// Open initializes eSpeak with the espeak-data directory found in path, the
+// empty path selecting the default one.
+func Open(path string) (tts.Engine, error) {
+	e := &Engine{}
+	if path != "" {
+		e.path = &path
+	}
+
+	synthMu.Lock()
+	id, rate, err := Init(Synchronous, 200, e.path, PhonemeEvents)
+	synthMu.Unlock()
+	if err != nil && !errors.Is(err, ErrAlreadyInitialized) {
+		return nil, err
+	}
+	registry.removeData(id)
+	e.sampleRate = int(rate)
+
+	voices, err := ListVoices(nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range voices {
+		e.voices = append(e.voices, tts.Voice{
+			Name:       v.Name,
+			Languages:  languages(v.Languages),
+			Identifier: v.Identifier,
+			Gender:     tts.Gender(v.Gender),
+			Age:        int(v.Age),
+		})
+	}
+	return e, nil
+}
+
+// languages strips the priority byte eSpeak puts before a language.
+func languages(s string) []string {
+	s = strings.TrimLeftFunc(s, func(r rune) bool { return r < ' ' })
+	if s == "" {
+		return nil
+	}
+	return []string{s}
+}
+
+// Name implements tts.Engine.
+func (e *Engine) Name() string {
+	return "espeak"
+}
+
+// SampleRate implements tts.Engine.
+func (e *Engine) SampleRate() int {
+	return e.sampleRate
+}
+
+// Voices implements tts.Engine.
+func (e *Engine) Voices(spec tts.VoiceSpec) ([]tts.Voice, error) {
+	return tts.Select(e.voices, spec), nil
+}
+
+// Synthesize implements tts.Engine.
+func (e *Engine) Synthesize(text string, spec tts.VoiceSpec, params tts.Parameters, fn func(samples []int16) error) (tts.Voice, error) {
+	if strings.TrimSpace(text) == "" {
+		return tts.Voice{}, tts.ErrEmptyText
+	}
+	voice, err := tts.Choose(e.voices, spec)
+	if err != nil {
+		return tts.Voice{}, err
+	}
+	flags := CharsAuto
+	if params.SSML {
+		flags |= SSML
+	}
+	p := &Parameters{
+		Rate:                params.Rate,
+		Volume:              params.Volume,
+		Pitch:               params.Pitch,
+		Range:               params.Range,
+		AnnouncePunctuation: PunctNone,
+		AnnounceCapitals:    CapitalNone,
+		WordGap:             params.WordGap,
+		Dir:                 os.TempDir(),
+	}
+	if err := stream(e.path, text, &Voice{Name: voice.Name}, p, flags, fn); err != nil {
+		return tts.Voice{}, err
+	}
+	return voice, nil
+}
+
+// Close implements tts.Engine.
+func (e *Engine) Close() error {
+	return nil
+}
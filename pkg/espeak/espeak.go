@@ -834,6 +834,8 @@ func GenSamples(text string, voice *Voice, params *Parameters) ([]int16, error)
 		voice = DefaultVoice
 	}
 
+	synthMu.Lock()
+	defer synthMu.Unlock()
 	id, _, err := Init(Synchronous, 200, nil, PhonemeEvents)
 	// if the error is of type ErrAllreadyInitialized, continue
 	if err != nil && !errors.Is(err, ErrAlreadyInitialized) {
@@ -873,6 +875,146 @@ func GenSamples(text string, voice *Voice, params *Parameters) ([]int16, error)
 	return *data, nil
 }
 
+// StreamSamples synthesizes text like GenSamples, but passes the samples to
+// fn as soon as eSpeak produces them instead of collecting them. An error
+// returned by fn stops the synthesis and is returned.
+func StreamSamples(text string, voice *Voice, params *Parameters, flags FlagType, fn func(samples []int16) error) error {
+	return stream(nil, text, voice, params, flags, fn)
+}
+
+// stream synthesizes text with the espeak-data directory found in path, nil
+// selecting the default one. The synthesis runs in its own goroutine and
+// never waits for fn, so that a slow consumer does not hold synthMu and
+// block the other syntheses. The samples of a text are queued meanwhile.
+func stream(path *string, text string, voice *Voice, params *Parameters, flags FlagType, fn func(samples []int16) error) error {
+	if text == "" {
+		return ErrEmptyText
+	}
+	if params == nil {
+		params = NewParameters()
+	}
+	if voice == nil {
+		voice = DefaultVoice
+	}
+
+	queue := newSampleQueue()
+	done := make(chan error, 1)
+	go func() {
+		done <- synthesize(path, text, voice, params, flags, queue)
+		queue.close()
+	}()
+	for {
+		samples, ok := queue.next()
+		if !ok {
+			break
+		}
+		if err := fn(samples); err != nil {
+			queue.stop()
+			<-done
+			return err
+		}
+	}
+	return <-done
+}
+
+// synthesize runs a synthesis under synthMu, pushing the samples to the
+// queue. It cancels the synthesis once the queue is stopped.
+func synthesize(path *string, text string, voice *Voice, params *Parameters, flags FlagType, queue *sampleQueue) error {
+	synthMu.Lock()
+	defer synthMu.Unlock()
+	id, _, err := Init(Synchronous, 200, path, PhonemeEvents)
+	if err != nil && !errors.Is(err, ErrAlreadyInitialized) {
+		return err
+	}
+	defer registry.removeData(id)
+	registry.setSink(id, queue.push)
+
+	if err := params.SetVoiceParams(); err != nil {
+		return err
+	}
+	SetSynthCallback(C.processSamples)
+	if err := SetVoiceByName(voice.Name); err != nil {
+		return err
+	}
+	err = Synth(text, flags|EndPause, 0, 0, Character, nil, unsafe.Pointer(&id))
+	if queue.stopped() {
+		Cancel()
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return Synchronize()
+}
+
+// sampleQueue hands the samples over from the synthesis to its consumer.
+// Pushing never blocks, the queue being bounded by the samples of one text.
+type sampleQueue struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	chunks [][]int16
+	closed bool
+	halted bool
+}
+
+func newSampleQueue() *sampleQueue {
+	q := &sampleQueue{}
+	q.cond = sync.NewCond(&q.mu)
+	return q
+}
+
+// push queues samples. It returns false once the consumer stopped, which
+// stops the synthesis.
+func (q *sampleQueue) push(samples []int16) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.halted {
+		return false
+	}
+	q.chunks = append(q.chunks, samples)
+	q.cond.Signal()
+	return true
+}
+
+// next waits for the next samples. It returns false once the queue is
+// closed and empty.
+func (q *sampleQueue) next() ([]int16, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for len(q.chunks) == 0 && !q.closed {
+		q.cond.Wait()
+	}
+	if len(q.chunks) == 0 {
+		return nil, false
+	}
+	samples := q.chunks[0]
+	q.chunks[0] = nil
+	q.chunks = q.chunks[1:]
+	return samples, true
+}
+
+// close ends the queue once the synthesis is over.
+func (q *sampleQueue) close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.closed = true
+	q.cond.Broadcast()
+}
+
+// stop drops the queued samples and the ones to come.
+func (q *sampleQueue) stop() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.halted = true
+	q.chunks = nil
+}
+
+func (q *sampleQueue) stopped() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.halted
+}
+
 // SampleRate return the produced sample rate.
 func SampleRate() int32 {
 	return sampleRate
@@ -884,12 +1026,17 @@ func processSamples(wav *C.short, numsamples C.int, events *C.espeak_EVENT) C.in
 		return 1
 	}
 	id := (*uintptr)(unsafe.Pointer(C.eventUserData(events)))
-	data := registry.getData(*id)
 	length := int(numsamples)
-	*data = append(
-		*data,
-		(*[1 << 28]int16)(unsafe.Pointer(wav))[:length:length]...,
-	)
+	samples := (*[1 << 28]int16)(unsafe.Pointer(wav))[:length:length]
+	if sink := registry.getSink(*id); sink != nil {
+		// the buffer belongs to eSpeak, the sink gets a copy
+		if !sink(append([]int16(nil), samples...)) {
+			return 1
+		}
+		return 0
+	}
+	data := registry.getData(*id)
+	*data = append(*data, samples...)
 	return 0
 }
 
@@ -961,11 +1108,16 @@ wav [ ][ ][ ][ ]
 
 var registry = &cache{
 	samples: make([]*[]int16, 0),
+	sinks:   map[uintptr]func([]int16) bool{},
 }
 
+// synthMu serializes the syntheses, eSpeak having a single global state.
+var synthMu sync.Mutex
+
 type cache struct {
 	sync.Mutex
 	samples []*[]int16
+	sinks   map[uintptr]func([]int16) bool // streamed data, by id
 }
 
 func (c *cache) newData() (uintptr, *[]int16, error) {
@@ -981,6 +1133,7 @@ func (c *cache) removeData(id uintptr) error {
 	c.Lock()
 	defer c.Unlock()
 
+	delete(c.sinks, id)
 	for i, data := range c.samples {
 		if uintptr(unsafe.Pointer(data)) == id {
 			c.samples[i] = c.samples[len(c.samples)-1]
@@ -1021,4 +1174,19 @@ func (c *cache) getData(id uintptr) *[]int16 {
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+// setSink streams the data of id to the sink, which returns false to stop
+// the synthesis.
+func (c *cache) setSink(id uintptr, sink func([]int16) bool) {
+	c.Lock()
+	defer c.Unlock()
+	c.sinks[id] = sink
+}
+
+// getSink returns the sink of the data, if any.
+func (c *cache) getSink(id uintptr) func([]int16) bool {
+	c.Lock()
+	defer c.Unlock()
+	return c.sinks[id]
+}
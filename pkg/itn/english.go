@@ -0,0 +1,255 @@
+package itn
+
+// Copyright (c) 2018 Bhojpur Consulting Private Limited, India. All rights reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var englishMonths = []string{
+	"january", "february", "march", "april", "may", "june",
+	"july", "august", "september", "october", "november", "december",
+}
+
+var englishOrdinals = map[string]int64{
+	"first": 1, "second": 2, "third": 3, "fourth": 4, "fifth": 5,
+	"sixth": 6, "seventh": 7, "eighth": 8, "ninth": 9, "tenth": 10,
+	"eleventh": 11, "twelfth": 12, "thirteenth": 13, "fourteenth": 14, "fifteenth": 15,
+	"sixteenth": 16, "seventeenth": 17, "eighteenth": 18, "nineteenth": 19,
+	"twentieth": 20, "thirtieth": 30, "fortieth": 40, "fiftieth": 50,
+	"sixtieth": 60, "seventieth": 70, "eightieth": 80, "ninetieth": 90,
+	"hundredth": 100, "thousandth": 1000, "millionth": 1000000,
+}
+
+type english struct {
+	lexicon
+}
+
+func newEnglish() *Normalizer {
+	e := &english{lexicon{
+		small: map[string]int64{
+			"zero": 0, "one": 1, "two": 2, "three": 3, "four": 4, "five": 5,
+			"six": 6, "seven": 7, "eight": 8, "nine": 9, "ten": 10,
+			"eleven": 11, "twelve": 12, "thirteen": 13, "fourteen": 14, "fifteen": 15,
+			"sixteen": 16, "seventeen": 17, "eighteen": 18, "nineteen": 19,
+			"twenty": 20, "thirty": 30, "forty": 40, "fifty": 50,
+			"sixty": 60, "seventy": 70, "eighty": 80, "ninety": 90,
+		},
+		compound: true,
+		hundred:  "hundred",
+		scales: map[string]int64{
+			"thousand": 1e3, "lakh": 1e5, "million": 1e6, "crore": 1e7, "billion": 1e9,
+		},
+		and: "and",
+		digits: map[string]int64{
+			"zero": 0, "oh": 0, "one": 1, "two": 2, "three": 3, "four": 4,
+			"five": 5, "six": 6, "seven": 7, "eight": 8, "nine": 9,
+		},
+		repeats: map[string]int{"double": 2, "triple": 3},
+		point:   "point",
+		plus:    "plus",
+	}}
+
+	currency := e.currencyRule(map[string]string{
+		"dollar": "$", "dollars": "$",
+		"rupee": "₹", "rupees": "₹",
+		"euro": "€", "euros": "€",
+		"pound": "£", "pounds": "£",
+	}, map[string]bool{
+		"cent": true, "cents": true, "paisa": true, "paise": true, "penny": true, "pence": true,
+	}, "and")
+
+	return &Normalizer{
+		fold: strings.ToLower,
+		rules: []rule{
+			e.phoneRule,
+			e.timeRule,
+			e.dateRule,
+			currency,
+			e.percentRule("percent", "per cent"),
+			e.ordinalRule,
+			e.cardinalRule,
+		},
+	}
+}
+
+// ordinal parses an ordinal number, such as "twenty third".
+func (e *english) ordinal(words []string) (int64, int) {
+	v, n := e.cardinal(words)
+	k := n
+	if n > 0 && k < len(words) && words[k] == e.and {
+		k++
+	}
+	if k >= len(words) {
+		return 0, 0
+	}
+	o, ok := englishOrdinals[words[k]]
+	switch {
+	case !ok:
+		return 0, 0
+	case n == 0:
+		return o, 1
+	case o >= 100:
+		return v * o, k + 1
+	case o < 10 && v%100 != 0 && (v%100 < 20 || v%10 != 0):
+		return 0, 0
+	case o >= 10 && v%100 != 0:
+		return 0, 0
+	}
+	return v + o, k + 1
+}
+
+// ordinalRule writes ordinals from the tenth on, e.g. "21st".
+func (e *english) ordinalRule(words []string) (string, int) {
+	v, n := e.ordinal(words)
+	if n == 0 || (n == 1 && v < 10) {
+		return "", 0
+	}
+	return strconv.FormatInt(v, 10) + ordinalSuffix(v), n
+}
+
+func ordinalSuffix(v int64) string {
+	if v%100 >= 11 && v%100 <= 13 {
+		return "th"
+	}
+	switch v % 10 {
+	case 1:
+		return "st"
+	case 2:
+		return "nd"
+	case 3:
+		return "rd"
+	}
+	return "th"
+}
+
+// englishMonth returns the capitalized name of a month.
+func englishMonth(word string) (string, bool) {
+	for _, month := range englishMonths {
+		if word == month {
+			return strings.ToUpper(month[:1]) + month[1:], true
+		}
+	}
+	return "", false
+}
+
+// dateRule writes "march third twenty twenty two" as "March 3, 2022" and
+// "third of march" as "3 March".
+func (e *english) dateRule(words []string) (string, int) {
+	if len(words) < 2 {
+		return "", 0
+	}
+	if month, ok := englishMonth(words[0]); ok {
+		day, n := e.ordinal(words[1:])
+		if n == 0 || day < 1 || day > 31 {
+			return "", 0
+		}
+		text := fmt.Sprintf("%s %d", month, day)
+		n++
+		if year, k := e.year(words[n:]); k > 0 {
+			text += fmt.Sprintf(", %d", year)
+			n += k
+		}
+		return text, n
+	}
+
+	day, n := e.ordinal(words)
+	if n == 0 || day < 1 || day > 31 || n+1 >= len(words) || words[n] != "of" {
+		return "", 0
+	}
+	month, ok := englishMonth(words[n+1])
+	if !ok {
+		return "", 0
+	}
+	text := fmt.Sprintf("%d %s", day, month)
+	n += 2
+	if year, k := e.year(words[n:]); k > 0 {
+		text += fmt.Sprintf(" %d", year)
+		n += k
+	}
+	return text, n
+}
+
+// year parses a year, either as a number ("two thousand five") or as pairs
+// of digits ("nineteen oh five", "twenty twenty two").
+func (e *english) year(words []string) (int64, int) {
+	if v, n := e.cardinal(words); v >= 1000 && v < 2100 {
+		return v, n
+	}
+	century, n := e.below100(words)
+	if n == 0 || century < 10 || n == len(words) {
+		return 0, 0
+	}
+	rest := words[n:]
+	if rest[0] == e.hundred {
+		return century * 100, n + 1
+	}
+	if rest[0] == "oh" && len(rest) > 1 {
+		if d, ok := e.small[rest[1]]; ok && d > 0 && d < 10 {
+			return century*100 + d, n + 2
+		}
+	}
+	if v, k := e.below100(rest); k > 0 && v >= 10 {
+		return century*100 + v, n + k
+	}
+	return 0, 0
+}
+
+// timeRule writes "three o'clock" as "3:00" and "ten oh five p m" as
+// "10:05 p.m.".
+func (e *english) timeRule(words []string) (string, int) {
+	hour, ok := e.small[firstOf(words)]
+	if !ok || hour < 1 || hour > 12 {
+		return "", 0
+	}
+	n := 1
+	if k := match(words[n:], "o'clock", "o clock"); k > 0 {
+		return fmt.Sprintf("%d:00", hour), n + k
+	}
+
+	text := strconv.FormatInt(hour, 10)
+	if len(words) > n+1 && words[n] == "oh" {
+		if d, ok := e.small[words[n+1]]; ok && d > 0 && d < 10 {
+			text += fmt.Sprintf(":%02d", d)
+			n += 2
+		}
+	} else if minutes, k := e.below100(words[n:]); k > 0 && minutes >= 10 && minutes < 60 {
+		text += fmt.Sprintf(":%02d", minutes)
+		n += k
+	}
+
+	if k := match(words[n:], "a m", "am", "a.m."); k > 0 {
+		return text + " a.m.", n + k
+	}
+	if k := match(words[n:], "p m", "pm", "p.m."); k > 0 {
+		return text + " p.m.", n + k
+	}
+	return "", 0
+}
+
+func firstOf(words []string) string {
+	if len(words) == 0 {
+		return ""
+	}
+	return words[0]
+}
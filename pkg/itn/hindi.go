@@ -0,0 +1,216 @@
+package itn
+
+// Copyright (c) 2018 Bhojpur Consulting Private Limited, India. All rights reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// hindiFolder folds the spelling variants of Devanagari: the precomposed
+// nukta letters and the chandrabindu.
+var hindiFolder = strings.NewReplacer(
+	"\u0958", "\u0915\u093c", "\u0959", "\u0916\u093c", "\u095a", "\u0917\u093c",
+	"\u095b", "\u091c\u093c", "\u095c", "\u0921\u093c", "\u095d", "\u0922\u093c",
+	"\u095e", "\u092b\u093c", "\u095f", "\u092f\u093c",
+	"\u0901", "\u0902",
+)
+
+func foldHindi(word string) string {
+	return hindiFolder.Replace(word)
+}
+
+// hindiNumbers are the words of the numbers below a hundred, each of which
+// is a single word in Hindi.
+var hindiNumbers = []string{
+	"शून्य", "एक", "दो", "तीन", "चार", "पांच", "छह", "सात", "आठ", "नौ",
+	"दस", "ग्यारह", "बारह", "तेरह", "चौदह", "पंद्रह", "सोलह", "सत्रह", "अठारह", "उन्नीस",
+	"बीस", "इक्कीस", "बाईस", "तेईस", "चौबीस", "पच्चीस", "छब्बीस", "सत्ताईस", "अट्ठाईस", "उनतीस",
+	"तीस", "इकतीस", "बत्तीस", "तैंतीस", "चौंतीस", "पैंतीस", "छत्तीस", "सैंतीस", "अड़तीस", "उनतालीस",
+	"चालीस", "इकतालीस", "बयालीस", "तैंतालीस", "चवालीस", "पैंतालीस", "छियालीस", "सैंतालीस", "अड़तालीस", "उनचास",
+	"पचास", "इक्यावन", "बावन", "तिरेपन", "चौवन", "पचपन", "छप्पन", "सत्तावन", "अट्ठावन", "उनसठ",
+	"साठ", "इकसठ", "बासठ", "तिरसठ", "चौंसठ", "पैंसठ", "छियासठ", "सड़सठ", "अड़सठ", "उनहत्तर",
+	"सत्तर", "इकहत्तर", "बहत्तर", "तिहत्तर", "चौहत्तर", "पचहत्तर", "छिहत्तर", "सतहत्तर", "अठहत्तर", "उन्यासी",
+	"अस्सी", "इक्यासी", "बयासी", "तिरासी", "चौरासी", "पचासी", "छियासी", "सत्तासी", "अट्ठासी", "नवासी",
+	"नब्बे", "इक्यानवे", "बानवे", "तिरानवे", "चौरानवे", "पचानवे", "छियानवे", "सत्तानवे", "अट्ठानवे", "निन्यानवे",
+}
+
+// hindiVariants are other common spellings of the numbers.
+var hindiVariants = map[string]int64{
+	"छः": 6, "छे": 6, "पन्द्रह": 15, "तिरपन": 53, "अड़सठ": 68, "उनासी": 79,
+}
+
+var hindiMonths = []string{
+	"जनवरी", "फ़रवरी", "फरवरी", "मार्च", "अप्रैल", "मई", "जून", "जुलाई", "अगस्त",
+	"सितंबर", "सितम्बर", "अक्टूबर", "अक्तूबर", "नवंबर", "नवम्बर", "दिसंबर", "दिसम्बर",
+}
+
+// hindiOrdinalSuffixes make the ordinals of the numbers from five on.
+var hindiOrdinalSuffixes = []string{"वां", "वीं", "वें"}
+
+// hindiQuarters are the words modifying the hour of a time.
+var hindiQuarters = map[string]int64{"सवा": 15, "साढ़े": 30, "पौने": -15}
+
+// hindiHalves are the hours and a half read as single words.
+var hindiHalves = map[string]int64{"डेढ़": 1, "ढाई": 2}
+
+type hindi struct {
+	lexicon
+	months   map[string]string
+	quarters map[string]int64
+	halves   map[string]int64
+}
+
+func newHindi() *Normalizer {
+	h := &hindi{
+		lexicon: lexicon{
+			small:   map[string]int64{},
+			hundred: foldHindi("सौ"),
+			scales: map[string]int64{
+				foldHindi("हज़ार"): 1e3, "हजार": 1e3,
+				"लाख":              1e5,
+				foldHindi("करोड़"): 1e7, "करोड": 1e7,
+			},
+			digits: map[string]int64{},
+			point:  "दशमलव",
+			plus:   "प्लस",
+		},
+		months:   map[string]string{},
+		quarters: map[string]int64{},
+		halves:   map[string]int64{},
+	}
+	for v, word := range hindiNumbers {
+		h.small[foldHindi(word)] = int64(v)
+		if v < 10 {
+			h.digits[foldHindi(word)] = int64(v)
+		}
+	}
+	for word, v := range hindiVariants {
+		h.small[foldHindi(word)] = v
+		if v < 10 {
+			h.digits[foldHindi(word)] = v
+		}
+	}
+	for _, month := range hindiMonths {
+		h.months[foldHindi(month)] = month
+	}
+	for word, v := range hindiQuarters {
+		h.quarters[foldHindi(word)] = v
+	}
+	for word, v := range hindiHalves {
+		h.halves[foldHindi(word)] = v
+	}
+
+	currency := h.currencyRule(map[string]string{
+		"रुपये": "₹", "रुपए": "₹", "रुपया": "₹", "रुपयों": "₹",
+		foldHindi("डॉलर"): "$",
+	}, map[string]bool{"पैसे": true, "पैसा": true}, "और")
+
+	return &Normalizer{
+		fold: foldHindi,
+		rules: []rule{
+			h.phoneRule,
+			h.timeRule,
+			h.dateRule,
+			currency,
+			h.percentRule("प्रतिशत", "परसेंट", foldHindi("फ़ीसदी"), "फीसदी"),
+			h.ordinalRule,
+			h.cardinalRule,
+		},
+	}
+}
+
+// ordinalRule writes ordinals from the tenth on with their suffix, e.g.
+// "पच्चीसवां" as "25वां".
+func (h *hindi) ordinalRule(words []string) (string, int) {
+	for _, suffix := range hindiOrdinalSuffixes {
+		stem := strings.TrimSuffix(firstOf(words), suffix)
+		if stem == firstOf(words) {
+			continue
+		}
+		if v, ok := h.small[stem]; ok && v >= 10 {
+			return strconv.FormatInt(v, 10) + suffix, 1
+		}
+	}
+	return "", 0
+}
+
+// dateRule writes "तीन मार्च दो हज़ार बाईस" as "3 मार्च 2022".
+func (h *hindi) dateRule(words []string) (string, int) {
+	if len(words) < 2 {
+		return "", 0
+	}
+	day, ok := h.small[words[0]]
+	month, isMonth := h.months[words[1]]
+	if !ok || !isMonth || day < 1 || day > 31 {
+		return "", 0
+	}
+	text := fmt.Sprintf("%d %s", day, month)
+	if year, k := h.cardinal(words[2:]); year >= 1000 && year < 2100 {
+		return fmt.Sprintf("%s %d", text, year), 2 + k
+	}
+	return text, 2
+}
+
+// timeRule writes "साढ़े तीन बजे" as "3:30 बजे" and "तीन बजकर दस मिनट" as
+// "3:10".
+func (h *hindi) timeRule(words []string) (string, int) {
+	if len(words) < 2 {
+		return "", 0
+	}
+	var hour, minutes int64
+	n := 0
+	if v, ok := h.halves[words[0]]; ok {
+		hour, minutes, n = v, 30, 1
+	} else {
+		quarter, hasQuarter := h.quarters[words[0]]
+		if hasQuarter {
+			n = 1
+		}
+		v, ok := h.small[firstOf(words[n:])]
+		if !ok || v < 1 || v > 12 {
+			return "", 0
+		}
+		hour, n = v, n+1
+		if quarter < 0 {
+			hour, minutes = (hour+10)%12+1, 60+quarter
+		} else {
+			minutes = quarter
+		}
+	}
+
+	if n >= len(words) {
+		return "", 0
+	}
+	if words[n] == "बजे" {
+		if minutes == 0 {
+			return fmt.Sprintf("%d बजे", hour), n + 1
+		}
+		return fmt.Sprintf("%d:%02d बजे", hour, minutes), n + 1
+	}
+	if words[n] == "बजकर" && minutes == 0 && n+2 < len(words) && words[n+2] == "मिनट" {
+		if m, ok := h.small[words[n+1]]; ok && m > 0 && m < 60 {
+			return fmt.Sprintf("%d:%02d", hour, m), n + 3
+		}
+	}
+	return "", 0
+}
@@ -0,0 +1,102 @@
+// Package itn implements the inverse text normalization of transcripts: it
+// rewrites the spoken form of numbers, dates, times, amounts of money,
+// percentages and phone numbers produced by the speech recognizers into
+// their written form, e.g. "twenty five rupees on march third" into
+// "₹25 on March 3".
+package itn
+
+// Copyright (c) 2018 Bhojpur Consulting Private Limited, India. All rights reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"strings"
+)
+
+// Span is the written form of the spoken words [Start, End) of a
+// transcript. Words left as they are make spans of a single word.
+type Span struct {
+	Text       string
+	Start, End int
+}
+
+// rule matches a construct at the start of folded words and returns its
+// written form along with the number of words it covers, zero when it does
+// not match.
+type rule func(words []string) (string, int)
+
+// Normalizer rewrites the transcripts of one language.
+type Normalizer struct {
+	fold  func(string) string
+	rules []rule
+}
+
+var (
+	// English normalizes English transcripts.
+	English = newEnglish()
+
+	// Hindi normalizes Hindi transcripts written in Devanagari.
+	Hindi = newHindi()
+)
+
+// For returns the normalizer of a BCP-47 language code, English when the
+// code is empty, or nil when the language is not supported.
+func For(language string) *Normalizer {
+	primary := strings.ToLower(strings.SplitN(strings.ReplaceAll(language, "_", "-"), "-", 2)[0])
+	switch primary {
+	case "", "en":
+		return English
+	case "hi":
+		return Hindi
+	}
+	return nil
+}
+
+// Spans splits the words of a transcript into the spans of their written
+// form. The rules are tried in order at every word, the first match wins.
+func (n *Normalizer) Spans(words []string) []Span {
+	folded := make([]string, len(words))
+	for i, word := range words {
+		folded[i] = n.fold(word)
+	}
+
+	var spans []Span
+	for i := 0; i < len(words); {
+		span := Span{Text: words[i], Start: i, End: i + 1}
+		for _, r := range n.rules {
+			if text, k := r(folded[i:]); k > 0 {
+				span = Span{Text: text, Start: i, End: i + k}
+				break
+			}
+		}
+		spans = append(spans, span)
+		i = span.End
+	}
+	return spans
+}
+
+// Normalize rewrites a transcript into its written form.
+func (n *Normalizer) Normalize(text string) string {
+	spans := n.Spans(strings.Fields(text))
+	texts := make([]string, len(spans))
+	for i, span := range spans {
+		texts[i] = span.Text
+	}
+	return strings.Join(texts, " ")
+}
@@ -0,0 +1,91 @@
+package itn
+
+// Copyright (c) 2018 Bhojpur Consulting Private Limited, India. All rights reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnglish(t *testing.T) {
+	for spoken, written := range map[string]string{
+		"twenty five rupees on march third":                    "₹25 on March 3",
+		"i have one dog and two cats":                          "i have one dog and two cats",
+		"twelve thousand three hundred and forty five":         "12345",
+		"two lakh fifty thousand":                              "250000",
+		"the twenty first time":                                "the 21st time",
+		"the first time":                                       "the first time",
+		"one hundred and second":                               "102nd",
+		"it takes one second":                                  "it takes one second",
+		"march third twenty twenty two":                        "March 3, 2022",
+		"fourth of july nineteen oh five":                      "4 July 1905",
+		"may first two thousand and five":                      "May 1, 2005",
+		"meet me at three o'clock":                             "meet me at 3:00",
+		"ten oh five p m":                                      "10:05 p.m.",
+		"seven thirty a m":                                     "7:30 a.m.",
+		"five dollars and fifty cents":                         "$5.50",
+		"twelve point five percent":                            "12.5%",
+		"pi is three point one four":                           "pi is 3.14",
+		"call nine eight seven six five four three two one oh": "call 9876543210",
+		"plus nine one double nine eight seven six five four":  "+919987654",
+		"hundred": "hundred",
+	} {
+		assert.Equal(t, written, English.Normalize(spoken), spoken)
+	}
+}
+
+func TestHindi(t *testing.T) {
+	for spoken, written := range map[string]string{
+		"पच्चीस रुपये":                 "₹25",
+		"सौ रुपये पचास पैसे":           "₹100.50",
+		"तीन मार्च दो ह\u095bार बाईस":  "3 मार्च 2022",
+		"तीन मार्च दो हज\u093cार बाईस": "3 मार्च 2022",
+		"उन्नीस सौ निन्यानवे":          "1999",
+		"साढ़े तीन बजे":                "3:30 बजे",
+		"पौने एक बजे":                  "12:45 बजे",
+		"ढाई बजे":                      "2:30 बजे",
+		"तीन बजकर दस मिनट":             "3:10",
+		"बीस प्रतिशत":                  "20%",
+		"पच्चीसवां जन्मदिन":            "25वां जन्मदिन",
+		"पाँचवाँ दिन":                  "पाँचवाँ दिन",
+		"एक आदमी":                      "एक आदमी",
+		"नौ आठ सात छह पांच चार तीन दो": "98765432",
+	} {
+		assert.Equal(t, written, Hindi.Normalize(spoken), spoken)
+	}
+}
+
+func TestSpans(t *testing.T) {
+	spans := English.Spans([]string{"pay", "Twenty", "Five", "rupees", "now"})
+	assert.Equal(t, []Span{
+		{Text: "pay", Start: 0, End: 1},
+		{Text: "₹25", Start: 1, End: 4},
+		{Text: "now", Start: 4, End: 5},
+	}, spans)
+}
+
+func TestFor(t *testing.T) {
+	assert.Equal(t, English, For(""))
+	assert.Equal(t, English, For("en-IN"))
+	assert.Equal(t, Hindi, For("hi"))
+	assert.Nil(t, For("de-DE"))
+}
@@ -0,0 +1,241 @@
+package itn
+
+// Copyright (c) 2018 Bhojpur Consulting Private Limited, India. All rights reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// minPhoneDigits is the shortest sequence of digits read as a phone number.
+const minPhoneDigits = 7
+
+// lexicon holds the number words of a language, folded.
+type lexicon struct {
+	small    map[string]int64 // numbers below a hundred
+	compound bool             // tens and units are separate words
+	hundred  string
+	scales   map[string]int64 // thousand and above
+	and      string           // conjunction allowed after a scale
+	digits   map[string]int64 // single digits of digit sequences
+	repeats  map[string]int   // "double" and "triple" digits
+	point    string           // decimal separator
+	plus     string           // international prefix of phone numbers
+}
+
+// cardinal parses the longest cardinal number at the start of words. A
+// single scale word is read as one of its kind.
+func (l *lexicon) cardinal(words []string) (int64, int) {
+	var total, hundreds, slot, lastScale int64
+	slotWords, n := 0, 0
+	for i := 0; i < len(words); i++ {
+		word := words[i]
+		if v, ok := l.small[word]; ok {
+			switch {
+			case slotWords == 0:
+			case l.compound && slotWords == 1 && slot >= 20 && slot%10 == 0 && v > 0 && v < 10:
+			default:
+				return total + hundreds + slot, n
+			}
+			slot += v
+			slotWords++
+			n = i + 1
+			continue
+		}
+
+		if word == l.hundred && hundreds == 0 && (slot > 0 || i == 0) {
+			if slot == 0 {
+				slot = 1
+			}
+			hundreds, slot, slotWords = slot*100, 0, 0
+			n = i + 1
+			continue
+		}
+
+		if scale, ok := l.scales[word]; ok && (lastScale == 0 || scale < lastScale) {
+			group := hundreds + slot
+			if group == 0 && i > 0 {
+				break
+			}
+			if group == 0 {
+				group = 1
+			}
+			total += group * scale
+			hundreds, slot, slotWords = 0, 0, 0
+			lastScale = scale
+			n = i + 1
+			continue
+		}
+
+		if l.and != "" && word == l.and && slotWords == 0 && (hundreds > 0 || total > 0) && i+1 < len(words) {
+			if _, ok := l.small[words[i+1]]; ok {
+				continue
+			}
+		}
+		break
+	}
+	return total + hundreds + slot, n
+}
+
+// below100 parses a number below a hundred.
+func (l *lexicon) below100(words []string) (int64, int) {
+	if len(words) == 0 {
+		return 0, 0
+	}
+	v, ok := l.small[words[0]]
+	if !ok {
+		return 0, 0
+	}
+	if l.compound && v >= 20 && v%10 == 0 && len(words) > 1 {
+		if u, ok := l.small[words[1]]; ok && u > 0 && u < 10 {
+			return v + u, 2
+		}
+	}
+	return v, 1
+}
+
+// number parses a cardinal number with optional decimals.
+func (l *lexicon) number(words []string) (string, int) {
+	v, n := l.cardinal(words)
+	if n == 0 {
+		return "", 0
+	}
+	text := strconv.FormatInt(v, 10)
+	if n+1 < len(words) && words[n] == l.point {
+		if digits, k := l.digitRun(words[n+1:]); k > 0 {
+			text += "." + digits
+			n += 1 + k
+		}
+	}
+	return text, n
+}
+
+// digitRun parses a sequence of single digits, such as "double five".
+func (l *lexicon) digitRun(words []string) (string, int) {
+	var digits strings.Builder
+	n := 0
+	for n < len(words) {
+		if d, ok := l.digits[words[n]]; ok {
+			digits.WriteString(strconv.FormatInt(d, 10))
+			n++
+			continue
+		}
+		times, ok := l.repeats[words[n]]
+		if !ok || n+1 == len(words) {
+			break
+		}
+		d, ok := l.digits[words[n+1]]
+		if !ok {
+			break
+		}
+		digits.WriteString(strings.Repeat(strconv.FormatInt(d, 10), times))
+		n += 2
+	}
+	return digits.String(), n
+}
+
+// cardinalRule writes cardinal and decimal numbers. Single words below ten
+// and lone scale words read better spelled out.
+func (l *lexicon) cardinalRule(words []string) (string, int) {
+	text, n := l.number(words)
+	if n == 1 {
+		if v, ok := l.small[words[0]]; !ok || v < 10 {
+			return "", 0
+		}
+	}
+	return text, n
+}
+
+// phoneRule writes long sequences of digits, such as phone numbers.
+func (l *lexicon) phoneRule(words []string) (string, int) {
+	prefix, i := "", 0
+	if l.plus != "" && len(words) > 0 && words[0] == l.plus {
+		prefix, i = "+", 1
+	}
+	digits, n := l.digitRun(words[i:])
+	if len(digits) < minPhoneDigits {
+		return "", 0
+	}
+	return prefix + digits, i + n
+}
+
+// percentRule writes percentages followed by one of the percent phrases.
+func (l *lexicon) percentRule(phrases ...string) rule {
+	return func(words []string) (string, int) {
+		amount, n := l.number(words)
+		if n == 0 {
+			return "", 0
+		}
+		if k := match(words[n:], phrases...); k > 0 {
+			return amount + "%", n + k
+		}
+		return "", 0
+	}
+}
+
+// currencyRule writes amounts of money followed by a currency word, and
+// optionally by an amount of its subunit, which the conjunction may join.
+func (l *lexicon) currencyRule(symbols map[string]string, subunits map[string]bool, conjunction string) rule {
+	return func(words []string) (string, int) {
+		amount, n := l.number(words)
+		if n == 0 || n == len(words) {
+			return "", 0
+		}
+		symbol, ok := symbols[words[n]]
+		if !ok {
+			return "", 0
+		}
+		n++
+
+		j := n
+		if j < len(words) && words[j] == conjunction {
+			j++
+		}
+		if cents, k := l.below100(words[j:]); k > 0 && j+k < len(words) && subunits[words[j+k]] && !strings.Contains(amount, ".") {
+			amount += fmt.Sprintf(".%02d", cents)
+			n = j + k + 1
+		}
+		return symbol + amount, n
+	}
+}
+
+// match returns the number of words of the first phrase found at the start
+// of words, or zero.
+func match(words []string, phrases ...string) int {
+	for _, phrase := range phrases {
+		fields := strings.Fields(phrase)
+		if len(fields) > len(words) {
+			continue
+		}
+		found := true
+		for i, field := range fields {
+			if words[i] != field {
+				found = false
+				break
+			}
+		}
+		if found {
+			return len(fields)
+		}
+	}
+	return 0
+}
@@ -0,0 +1,157 @@
+package metrics
+
+// Copyright (c) 2018 Bhojpur Consulting Private Limited, India. All rights reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// It exposes the Prometheus metrics of the speech servers, and registers
+// the standard gRPC health and reflection services along with them.
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+// Path is the HTTP path the metrics are served at.
+const Path = "/metrics"
+
+// latencyBuckets spans the latencies of interactive recognition.
+var latencyBuckets = []float64{.01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+var (
+	// ActiveStreams counts the streams in progress by gRPC method.
+	ActiveStreams = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "speech",
+		Name:      "active_streams",
+		Help:      "Number of gRPC streams in progress.",
+	}, []string{"method"})
+
+	// AudioSeconds counts the seconds of audio processed by model.
+	AudioSeconds = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "speech",
+		Name:      "audio_seconds_total",
+		Help:      "Seconds of audio processed.",
+	}, []string{"model"})
+
+	// RealTimeFactor observes the processing time of a recognition divided
+	// by the duration of its audio, by model.
+	RealTimeFactor = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "speech",
+		Name:      "real_time_factor",
+		Help:      "Processing time of a recognition divided by the duration of its audio.",
+		Buckets:   []float64{.05, .1, .2, .3, .5, .75, 1, 1.5, 2, 5},
+	}, []string{"model"})
+
+	// ResultLatency observes the time between the arrival of the audio
+	// and the sending of its result, by result type.
+	ResultLatency = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "speech",
+		Name:      "result_latency_seconds",
+		Help:      "Time between the arrival of the audio and the sending of its result.",
+		Buckets:   latencyBuckets,
+	}, []string{"type"})
+
+	// SynthesisSeconds observes the time taken to synthesize speech, by
+	// synthesis engine.
+	SynthesisSeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "speech",
+		Name:      "synthesis_seconds",
+		Help:      "Time taken to synthesize speech.",
+		Buckets:   latencyBuckets,
+	}, []string{"engine"})
+
+	// ModelLoadSeconds observes the time taken to load a model.
+	ModelLoadSeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "speech",
+		Name:      "model_load_seconds",
+		Help:      "Time taken to load a model.",
+		Buckets:   []float64{.1, .25, .5, 1, 2.5, 5, 10, 30, 60, 120},
+	}, []string{"model"})
+)
+
+// Result types of ResultLatency.
+const (
+	Partial = "partial"
+	Final   = "final"
+)
+
+// ObserveRecognition accounts for the recognition of seconds of audio by a
+// model, which took elapsed.
+func ObserveRecognition(model string, seconds float64, elapsed time.Duration) {
+	if seconds <= 0 {
+		return
+	}
+	AudioSeconds.WithLabelValues(model).Add(seconds)
+	RealTimeFactor.WithLabelValues(model).Observe(elapsed.Seconds() / seconds)
+}
+
+// Since observes the time elapsed since start in a histogram.
+func Since(observer prometheus.Observer, start time.Time) {
+	observer.Observe(time.Since(start).Seconds())
+}
+
+// StreamInterceptor counts the active streams.
+func StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		gauge := ActiveStreams.WithLabelValues(info.FullMethod)
+		gauge.Inc()
+		defer gauge.Dec()
+		return handler(srv, stream)
+	}
+}
+
+// Services are the services registered by Register, which probes and
+// tools are expected to call without credentials.
+var Services = []string{
+	healthpb.Health_ServiceDesc.ServiceName,
+	"grpc.reflection.v1alpha.ServerReflection",
+}
+
+// Register registers the health and reflection services on a gRPC server.
+// The health of the server and of all its services is reported as serving,
+// the returned health server updates it.
+func Register(server *grpc.Server) *health.Server {
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(server, healthServer)
+	reflection.Register(server)
+	for name := range server.GetServiceInfo() {
+		healthServer.SetServingStatus(name, healthpb.HealthCheckResponse_SERVING)
+	}
+	return healthServer
+}
+
+// Handler serves the metrics of the default registry.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// ListenAndServe serves the metrics at Path on addr.
+func ListenAndServe(addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle(Path, Handler())
+	return http.ListenAndServe(addr, mux)
+}
@@ -0,0 +1,77 @@
+package metrics
+
+// Copyright (c) 2018 Bhojpur Consulting Private Limited, India. All rights reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bhojpur/speech/internal/grpctest"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+type serverStream struct {
+	grpc.ServerStream
+}
+
+func TestStreamInterceptor(t *testing.T) {
+	info := &grpc.StreamServerInfo{FullMethod: "/test.Service/Stream"}
+	gauge := ActiveStreams.WithLabelValues(info.FullMethod)
+	err := StreamInterceptor()(nil, &serverStream{}, info, func(srv interface{}, stream grpc.ServerStream) error {
+		assert.Equal(t, 1.0, testutil.ToFloat64(gauge))
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 0.0, testutil.ToFloat64(gauge))
+}
+
+func TestObserveRecognition(t *testing.T) {
+	ObserveRecognition("test-model", 2, time.Second)
+	ObserveRecognition("test-model", 0, time.Second)
+	assert.Equal(t, 2.0, testutil.ToFloat64(AudioSeconds.WithLabelValues("test-model")))
+
+	recorder := httptest.NewRecorder()
+	Handler().ServeHTTP(recorder, httptest.NewRequest("GET", Path, nil))
+	assert.True(t, strings.Contains(recorder.Body.String(), `speech_real_time_factor_sum{model="test-model"} 0.5`))
+}
+
+func TestRegister(t *testing.T) {
+	conn := grpctest.Dial(t, func(server *grpc.Server) {
+		Register(server)
+		for _, service := range Services {
+			assert.Contains(t, server.GetServiceInfo(), service)
+		}
+	})
+
+	client := healthpb.NewHealthClient(conn)
+	for _, service := range []string{"", healthpb.Health_ServiceDesc.ServiceName} {
+		res, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+		require.NoError(t, err)
+		assert.Equal(t, healthpb.HealthCheckResponse_SERVING, res.GetStatus())
+	}
+}
@@ -0,0 +1,451 @@
+package models
+
+// Copyright (c) 2018 Bhojpur Consulting Private Limited, India. All rights reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// It keeps a registry of the speech recognition models installed in a
+// directory. Models are described by their metadata, loaded on first use
+// and shared by all the recognizers using them. Idle models are unloaded in
+// least recently used order when the loaded ones exceed a memory budget.
+
+import (
+	"bufio"
+	"container/list"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bhojpur/speech/pkg/asr"
+	"github.com/bhojpur/speech/pkg/metrics"
+)
+
+// MetadataFile is the optional file of a model directory overriding the
+// metadata derived from the directory itself.
+const MetadataFile = "model.json"
+
+// DefaultSampleRate is assumed for models which do not declare one.
+const DefaultSampleRate = 16000
+
+// ErrNotFound is returned when no model matches a name or a language.
+var ErrNotFound = errors.New("model not found")
+
+// Info describes an installed model.
+type Info struct {
+	Name string `json:"name"`
+	Path string `json:"-"`
+	// Language is the BCP-47 code of the model's language.
+	Language   string  `json:"language"`
+	SampleRate float64 `json:"sample_rate"`
+	// SpeakerModel is the path of the speaker identification model shipped
+	// with the model, if any.
+	SpeakerModel string `json:"speaker_model"`
+	// Size is the size of the model files, used as an estimate of the
+	// memory taken by the loaded model.
+	Size int64 `json:"-"`
+}
+
+// Registry loads the models of a directory on demand. It is safe for
+// concurrent use.
+type Registry struct {
+	engine      string
+	budget      int64
+	defaultName string
+
+	mu     sync.Mutex
+	models map[string]*entry
+	names  []string
+	idle   *list.List // idle loaded models, most recently used first
+	used   int64
+	closed bool
+}
+
+type entry struct {
+	info    Info
+	engine  asr.Engine
+	refs    int
+	loading chan struct{} // closed when the load attempt ends
+	elem    *list.Element
+}
+
+// Option configures a Registry.
+type Option func(*Registry)
+
+// WithEngine sets the speech recognition engine loading the models, "vosk"
+// by default.
+func WithEngine(name string) Option {
+	return func(r *Registry) {
+		r.engine = name
+	}
+}
+
+// WithMemoryBudget limits the total size of the loaded models. Zero means
+// no limit. Models in use are never unloaded, so the budget may be exceeded
+// while they are.
+func WithMemoryBudget(bytes int64) Option {
+	return func(r *Registry) {
+		r.budget = bytes
+	}
+}
+
+// WithDefault sets the model used when a lookup gives neither a name nor a
+// language. The first model by name is used otherwise.
+func WithDefault(name string) Option {
+	return func(r *Registry) {
+		r.defaultName = name
+	}
+}
+
+// New scans the models directory. Every subdirectory is a model.
+func New(dir string, opts ...Option) (*Registry, error) {
+	r := &Registry{
+		engine: "vosk",
+		models: map[string]*entry{},
+		idle:   list.New(),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		if !file.IsDir() {
+			continue
+		}
+		info, err := ReadInfo(filepath.Join(dir, file.Name()))
+		if err != nil {
+			return nil, err
+		}
+		if _, dup := r.models[info.Name]; dup {
+			return nil, fmt.Errorf("duplicate model name %q in %s", info.Name, dir)
+		}
+		r.models[info.Name] = &entry{info: info}
+		r.names = append(r.names, info.Name)
+	}
+	sort.Strings(r.names)
+
+	if r.defaultName != "" {
+		if _, ok := r.models[r.defaultName]; !ok {
+			return nil, fmt.Errorf("default model %q: %w", r.defaultName, ErrNotFound)
+		}
+	}
+	return r, nil
+}
+
+// ReadInfo reads the metadata of the model in the directory path. Fields
+// missing from its model.json are derived from the directory: the name is
+// the directory name, the language is parsed from names like
+// vosk-model-small-en-us-0.15, the sample rate is read from conf/mfcc.conf
+// and a spk subdirectory holds the speaker model.
+func ReadInfo(path string) (Info, error) {
+	info := Info{}
+	data, err := ioutil.ReadFile(filepath.Join(path, MetadataFile))
+	if err == nil {
+		if err := json.Unmarshal(data, &info); err != nil {
+			return info, fmt.Errorf("invalid %s of model %s: %w", MetadataFile, path, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return info, err
+	}
+
+	info.Path = path
+	if info.Name == "" {
+		info.Name = filepath.Base(path)
+	}
+	if info.Language == "" {
+		info.Language = languageOf(filepath.Base(path))
+	}
+	if info.SampleRate == 0 {
+		info.SampleRate = sampleRateOf(path)
+	}
+	if info.SpeakerModel == "" {
+		if stat, err := os.Stat(filepath.Join(path, "spk")); err == nil && stat.IsDir() {
+			info.SpeakerModel = "spk"
+		}
+	}
+	if info.SpeakerModel != "" && !filepath.IsAbs(info.SpeakerModel) {
+		info.SpeakerModel = filepath.Join(path, info.SpeakerModel)
+	}
+
+	info.Size, err = dirSize(path)
+	return info, err
+}
+
+// languageOf parses the language out of a model name like
+// vosk-model-small-en-us-0.15, where it is followed by the version.
+func languageOf(name string) string {
+	parts := strings.Split(strings.ToLower(name), "-")
+	if len(parts) < 3 || parts[0] != "vosk" || parts[1] != "model" {
+		return ""
+	}
+	parts = parts[2:]
+	if parts[0] == "small" {
+		parts = parts[1:]
+	}
+
+	var tags []string
+	for _, part := range parts {
+		if len(tags) == 2 || part == "" || part[0] < 'a' || part[0] > 'z' {
+			break
+		}
+		if len(tags) == 1 && len(part) != 2 {
+			break
+		}
+		tags = append(tags, part)
+	}
+	return strings.Join(tags, "-")
+}
+
+// sampleRateOf reads the sample frequency from the feature extraction
+// configuration of a Kaldi model.
+func sampleRateOf(path string) float64 {
+	f, err := os.Open(filepath.Join(path, "conf", "mfcc.conf"))
+	if err != nil {
+		return DefaultSampleRate
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "--sample-frequency=") {
+			continue
+		}
+		value := strings.TrimPrefix(line, "--sample-frequency=")
+		if rate, err := strconv.ParseFloat(value, 64); err == nil && rate > 0 {
+			return rate
+		}
+	}
+	return DefaultSampleRate
+}
+
+func dirSize(path string) (int64, error) {
+	var size int64
+	err := filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
+
+// List returns the installed models sorted by name.
+func (r *Registry) List() []Info {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	infos := make([]Info, 0, len(r.names))
+	for _, name := range r.names {
+		infos = append(infos, r.models[name].info)
+	}
+	return infos
+}
+
+// Loaded reports whether the named model is loaded.
+func (r *Registry) Loaded(name string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e, ok := r.models[name]
+	return ok && e.engine != nil
+}
+
+// Lookup finds a model by name or, when the name is empty, by language.
+// A language matches the models of the same language or, failing that, of
+// the same primary language, preferring the loaded ones. Without name and
+// language the default model is returned.
+func (r *Registry) Lookup(name, language string) (Info, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if name != "" {
+		e, ok := r.models[name]
+		if !ok {
+			return Info{}, fmt.Errorf("%w: %q", ErrNotFound, name)
+		}
+		return e.info, nil
+	}
+
+	if language == "" {
+		name = r.defaultName
+		if name == "" && len(r.names) > 0 {
+			name = r.names[0]
+		}
+		if name == "" {
+			return Info{}, ErrNotFound
+		}
+		return r.models[name].info, nil
+	}
+
+	language = strings.ToLower(language)
+	primary := primaryLanguage(language)
+	var best *entry
+	bestScore := 0
+	for _, name := range r.names {
+		e := r.models[name]
+		score := 0
+		switch {
+		case e.info.Language == "":
+		case strings.EqualFold(e.info.Language, language):
+			score = 4
+		case primaryLanguage(strings.ToLower(e.info.Language)) == primary:
+			score = 2
+		}
+		if score == 0 {
+			continue
+		}
+		if e.engine != nil {
+			score++
+		}
+		if score > bestScore {
+			best, bestScore = e, score
+		}
+	}
+	if best == nil {
+		return Info{}, fmt.Errorf("%w: language %q", ErrNotFound, language)
+	}
+	return best.info, nil
+}
+
+func primaryLanguage(language string) string {
+	if i := strings.IndexAny(language, "-_"); i >= 0 {
+		return language[:i]
+	}
+	return language
+}
+
+// Acquire returns the engine of the named model, loading it if needed. The
+// engine stays loaded until the release function is called.
+func (r *Registry) Acquire(name string) (asr.Engine, func(), error) {
+	r.mu.Lock()
+	e, ok := r.models[name]
+	if !ok {
+		r.mu.Unlock()
+		return nil, nil, fmt.Errorf("%w: %q", ErrNotFound, name)
+	}
+
+	for {
+		if r.closed {
+			r.mu.Unlock()
+			return nil, nil, errors.New("model registry is closed")
+		}
+		if e.engine != nil {
+			break
+		}
+		if e.loading == nil {
+			e.loading = make(chan struct{})
+			r.mu.Unlock()
+			start := time.Now()
+			engine, err := asr.Open(r.engine, e.info.Path)
+			if err == nil {
+				metrics.Since(metrics.ModelLoadSeconds.WithLabelValues(name), start)
+			}
+			r.mu.Lock()
+			loading := e.loading
+			e.loading = nil
+			close(loading)
+			if err != nil {
+				r.mu.Unlock()
+				return nil, nil, fmt.Errorf("failed to load model %q: %w", name, err)
+			}
+			if r.closed {
+				r.mu.Unlock()
+				engine.Close()
+				return nil, nil, errors.New("model registry is closed")
+			}
+			e.engine = engine
+			r.used += e.info.Size
+			break
+		}
+		loading := e.loading
+		r.mu.Unlock()
+		<-loading
+		r.mu.Lock()
+	}
+
+	if e.elem != nil {
+		r.idle.Remove(e.elem)
+		e.elem = nil
+	}
+	e.refs++
+	r.evict()
+	engine := e.engine
+	r.mu.Unlock()
+
+	var once sync.Once
+	return engine, func() {
+		once.Do(func() { r.release(e) })
+	}, nil
+}
+
+func (r *Registry) release(e *entry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e.refs--
+	if e.refs > 0 || e.engine == nil {
+		return
+	}
+	e.elem = r.idle.PushFront(e)
+	r.evict()
+}
+
+// evict unloads the least recently used idle models while the budget is
+// exceeded. It is called with the lock held.
+func (r *Registry) evict() {
+	for r.budget > 0 && r.used > r.budget && r.idle.Len() > 0 {
+		e := r.idle.Remove(r.idle.Back()).(*entry)
+		e.elem = nil
+		r.unload(e)
+	}
+}
+
+func (r *Registry) unload(e *entry) {
+	e.engine.Close()
+	e.engine = nil
+	r.used -= e.info.Size
+}
+
+// Close unloads all the models. Engines still in use are closed as well, so
+// the recognizers must be closed before.
+func (r *Registry) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.closed = true
+	for _, e := range r.models {
+		if e.engine != nil {
+			r.unload(e)
+		}
+		e.elem = nil
+	}
+	r.idle.Init()
+	return nil
+}
@@ -0,0 +1,225 @@
+package models
+
+// Copyright (c) 2018 Bhojpur Consulting Private Limited, India. All rights reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/bhojpur/speech/pkg/asr"
+	"github.com/bhojpur/speech/pkg/asr/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var opened, closed int32
+
+type countingEngine struct {
+	*fake.Engine
+}
+
+func (e countingEngine) Close() error {
+	atomic.AddInt32(&closed, 1)
+	return e.Engine.Close()
+}
+
+func init() {
+	asr.Register("models-test", func(path string) (asr.Engine, error) {
+		atomic.AddInt32(&opened, 1)
+		info, err := ReadInfo(path)
+		if err != nil {
+			return nil, err
+		}
+		return countingEngine{fake.NewEngine(info.SampleRate)}, nil
+	})
+}
+
+// install creates a model directory holding size bytes.
+func install(t *testing.T, dir, name string, size int, files map[string]string) {
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.MkdirAll(filepath.Join(path, "conf"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(path, "final.mdl"), make([]byte, size), 0644))
+	for file, content := range files {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(path, file)), 0755))
+		require.NoError(t, ioutil.WriteFile(filepath.Join(path, file), []byte(content), 0644))
+	}
+}
+
+func models(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "models")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	install(t, dir, "vosk-model-small-en-us-0.15", 1000, map[string]string{
+		"conf/mfcc.conf": "--use-energy=false\n--sample-frequency=16000\n",
+	})
+	install(t, dir, "vosk-model-en-in-0.5", 1000, map[string]string{
+		"conf/mfcc.conf":    "--sample-frequency=8000\n",
+		"spk/final.ext.raw": "",
+	})
+	install(t, dir, "custom", 1000, map[string]string{
+		MetadataFile: `{"name": "kiosk", "language": "de-DE", "sample_rate": 22050}`,
+	})
+	return dir
+}
+
+func TestReadInfo(t *testing.T) {
+	dir := models(t)
+	r, err := New(dir, WithEngine("models-test"))
+	require.NoError(t, err)
+
+	infos := r.List()
+	require.Len(t, infos, 3)
+	assert.Equal(t, "kiosk", infos[0].Name)
+	assert.Equal(t, "de-DE", infos[0].Language)
+	assert.Equal(t, 22050.0, infos[0].SampleRate)
+
+	assert.Equal(t, "vosk-model-en-in-0.5", infos[1].Name)
+	assert.Equal(t, "en-in", infos[1].Language)
+	assert.Equal(t, 8000.0, infos[1].SampleRate)
+	assert.Equal(t, filepath.Join(dir, "vosk-model-en-in-0.5", "spk"), infos[1].SpeakerModel)
+	assert.Equal(t, int64(1000+len("--sample-frequency=8000\n")), infos[1].Size)
+
+	assert.Equal(t, "en-us", infos[2].Language)
+	assert.Equal(t, 16000.0, infos[2].SampleRate)
+	assert.Empty(t, infos[2].SpeakerModel)
+}
+
+func TestLanguageOf(t *testing.T) {
+	for name, language := range map[string]string{
+		"vosk-model-small-en-us-0.15":  "en-us",
+		"vosk-model-de-0.21":           "de",
+		"vosk-model-small-pt-0.3":      "pt",
+		"vosk-model-en-us-daanzu-2020": "en-us",
+		"vosk-model-small-ja-0.22":     "ja",
+		"model":                        "",
+		"vosk-model-spk-0.4":           "spk",
+	} {
+		assert.Equal(t, language, languageOf(name), name)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	r, err := New(models(t), WithEngine("models-test"), WithDefault("vosk-model-small-en-us-0.15"))
+	require.NoError(t, err)
+
+	info, err := r.Lookup("kiosk", "en-US")
+	require.NoError(t, err)
+	assert.Equal(t, "kiosk", info.Name)
+
+	info, err = r.Lookup("", "de")
+	require.NoError(t, err)
+	assert.Equal(t, "kiosk", info.Name)
+
+	info, err = r.Lookup("", "en-IN")
+	require.NoError(t, err)
+	assert.Equal(t, "vosk-model-en-in-0.5", info.Name)
+
+	info, err = r.Lookup("", "")
+	require.NoError(t, err)
+	assert.Equal(t, "vosk-model-small-en-us-0.15", info.Name)
+
+	// the loaded model is preferred among equally good ones
+	_, release, err := r.Acquire("vosk-model-small-en-us-0.15")
+	require.NoError(t, err)
+	defer release()
+	info, err = r.Lookup("", "en-GB")
+	require.NoError(t, err)
+	assert.Equal(t, "vosk-model-small-en-us-0.15", info.Name)
+
+	_, err = r.Lookup("", "fr")
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = r.Lookup("missing", "")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestAcquireShared(t *testing.T) {
+	r, err := New(models(t), WithEngine("models-test"))
+	require.NoError(t, err)
+	defer r.Close()
+
+	before := atomic.LoadInt32(&opened)
+	var wg sync.WaitGroup
+	engines := make([]asr.Engine, 8)
+	releases := make([]func(), 8)
+	for i := range engines {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			var err error
+			engines[i], releases[i], err = r.Acquire("kiosk")
+			assert.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Equal(t, before+1, atomic.LoadInt32(&opened))
+	for _, engine := range engines {
+		assert.Same(t, engines[0].(countingEngine).Engine, engine.(countingEngine).Engine)
+	}
+	assert.Equal(t, 22050.0, engines[0].SampleRate())
+	for _, release := range releases {
+		release()
+	}
+	assert.True(t, r.Loaded("kiosk"))
+
+	_, _, err = r.Acquire("missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestEviction(t *testing.T) {
+	// room for two of the three models
+	r, err := New(models(t), WithEngine("models-test"), WithMemoryBudget(2100))
+	require.NoError(t, err)
+	defer r.Close()
+
+	_, releaseKiosk, err := r.Acquire("kiosk")
+	require.NoError(t, err)
+	_, releaseIn, err := r.Acquire("vosk-model-en-in-0.5")
+	require.NoError(t, err)
+	releaseIn()
+	releaseIn()
+
+	// the idle en-in model makes room, the busy kiosk one stays
+	before := atomic.LoadInt32(&closed)
+	_, releaseUs, err := r.Acquire("vosk-model-small-en-us-0.15")
+	require.NoError(t, err)
+	assert.Equal(t, before+1, atomic.LoadInt32(&closed))
+	assert.True(t, r.Loaded("kiosk"))
+	assert.False(t, r.Loaded("vosk-model-en-in-0.5"))
+	assert.True(t, r.Loaded("vosk-model-small-en-us-0.15"))
+
+	// over the budget while all the loaded models are busy
+	_, releaseIn, err = r.Acquire("vosk-model-en-in-0.5")
+	require.NoError(t, err)
+	assert.True(t, r.Loaded("kiosk"))
+
+	// the least recently used idle model goes first
+	releaseUs()
+	releaseKiosk()
+	assert.False(t, r.Loaded("vosk-model-small-en-us-0.15"))
+	assert.True(t, r.Loaded("kiosk"))
+	releaseIn()
+}
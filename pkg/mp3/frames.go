@@ -0,0 +1,99 @@
+package mp3
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/bhojpur/speech/pkg/mp3/internal/consts"
+	"github.com/bhojpur/speech/pkg/mp3/internal/frame"
+	"github.com/bhojpur/speech/pkg/mp3/internal/frameheader"
+)
+
+// A Frame is an encoded MP3 frame, header included.
+type Frame struct {
+	Data       []byte
+	SampleRate int
+	Channels   int
+	// Samples is the number of samples per channel the frame decodes to.
+	Samples int
+}
+
+// A FrameReader splits an MP3 stream into its frames, without decoding
+// them.
+type FrameReader struct {
+	source  *source
+	started bool
+}
+
+// NewFrameReader returns a FrameReader reading the stream r.
+func NewFrameReader(r io.Reader) *FrameReader {
+	return &FrameReader{
+		source: &source{reader: r},
+	}
+}
+
+// ReadFrame returns the next frame, or io.EOF at the end of the stream.
+// The tags leading the stream and the garbage between frames are skipped.
+func (r *FrameReader) ReadFrame() (*Frame, error) {
+	if !r.started {
+		r.started = true
+		if err := r.source.skipTags(); err != nil {
+			return nil, err
+		}
+	}
+
+	h, _, err := frameheader.Read(r.source, r.source.pos)
+	if err != nil {
+		if _, ok := err.(*consts.UnexpectedEOF); ok {
+			return nil, io.EOF
+		}
+		return nil, err
+	}
+	rate, err := h.SamplingFrequencyValue()
+	if err != nil {
+		return nil, err
+	}
+	size, err := h.FrameSize()
+	if err != nil {
+		return nil, err
+	}
+	if size < 4 {
+		return nil, io.EOF
+	}
+
+	data := make([]byte, size)
+	data[0], data[1], data[2], data[3] = byte(h>>24), byte(h>>16), byte(h>>8), byte(h)
+	if n, err := r.source.ReadFull(data[4:]); n < size-4 {
+		if err == nil {
+			err = io.EOF
+		}
+		return nil, err
+	}
+	return &Frame{
+		Data:       data,
+		SampleRate: rate,
+		Channels:   h.NumberOfChannels(),
+		Samples:    consts.SamplesPerGr * h.Granules(),
+	}, nil
+}
+
+// A FrameDecoder decodes the frames of a stream one at a time, as read by a
+// FrameReader. The frames of a stream joined in the middle may be
+// distorted until the bit reservoir of the decoder fills up.
+type FrameDecoder struct {
+	prev *frame.Frame
+}
+
+// Decode decodes a whole frame into 16-bit little-endian stereo, like the
+// Decoder.
+func (d *FrameDecoder) Decode(data []byte) ([]byte, error) {
+	f, _, err := frame.Read(&source{reader: bytes.NewReader(data)}, 0, d.prev)
+	if err != nil {
+		if _, ok := err.(*consts.UnexpectedEOF); ok {
+			return nil, io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	d.prev = f
+	return f.Decode(), nil
+}
@@ -0,0 +1,52 @@
+package mp3
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+)
+
+func TestFrameReader(t *testing.T) {
+	data, err := os.ReadFile("../../audios/chrono.mp3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := NewDecoder(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pcm, err := io.ReadAll(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewFrameReader(bytes.NewReader(data))
+	var decoder FrameDecoder
+	var decoded []byte
+	samples := 0
+	for {
+		f, err := r.ReadFrame()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if f.SampleRate != d.SampleRate() {
+			t.Errorf("frame sample rate: got %d, want %d", f.SampleRate, d.SampleRate())
+		}
+		samples += f.Samples
+		out, err := decoder.Decode(f.Data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		decoded = append(decoded, out...)
+	}
+	if got, want := int64(samples*4), d.Length(); got != want {
+		t.Errorf("samples: got %d bytes, want %d", got, want)
+	}
+	if !bytes.Equal(decoded, pcm) {
+		t.Errorf("frames decode to %d bytes differing from the %d bytes of the decoder", len(decoded), len(pcm))
+	}
+}
@@ -0,0 +1,170 @@
+package moderation
+
+// Copyright (c) 2018 Bhojpur Consulting Private Limited, India. All rights reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// MaskFilter masks the banned words of a text instead of dropping them, so
+// that the words keep their positions. It suits transcripts whose words
+// carry timestamps.
+type MaskFilter struct {
+	phrases map[string]bool
+	longest int
+}
+
+// NewMaskFilter creates a filter masking the words and phrases of the
+// filter map. The replacements of the map are not used.
+func NewMaskFilter(filterMap FilterMap) *MaskFilter {
+	f := new(MaskFilter)
+	f.SetFilterMap(filterMap)
+	return f
+}
+
+func (f *MaskFilter) SetFilterMap(filterMap FilterMap) {
+	f.phrases = map[string]bool{}
+	f.longest = 0
+	for phrase := range filterMap.Range() {
+		words := strings.Fields(strings.ToLower(phrase))
+		if len(words) == 0 {
+			continue
+		}
+		f.phrases[strings.Join(words, " ")] = true
+		if len(words) > f.longest {
+			f.longest = len(words)
+		}
+	}
+}
+
+func (f *MaskFilter) Moderate(msg Message) string {
+	return strings.Join(f.MaskWords(strings.Fields(msg.Text)), " ")
+}
+
+// MaskWords returns the words with the banned ones masked. Banned phrases
+// spanning several words are masked word by word.
+func (f *MaskFilter) MaskWords(words []string) []string {
+	masked := make([]string, len(words))
+	copy(masked, words)
+	for i := 0; i < len(words); i++ {
+		for n := f.longest; n > 0; n-- {
+			if i+n > len(words) {
+				continue
+			}
+			phrase := strings.ToLower(strings.Join(words[i:i+n], " "))
+			if !f.phrases[phrase] {
+				continue
+			}
+			for k := i; k < i+n; k++ {
+				masked[k] = mask(words[k])
+			}
+			i += n - 1
+			break
+		}
+	}
+	return masked
+}
+
+// mask keeps the first letter of a word and replaces the others with stars.
+func mask(word string) string {
+	runes := []rune(word)
+	for i := 1; i < len(runes); i++ {
+		runes[i] = '*'
+	}
+	return string(runes)
+}
+
+// MaskFilters holds a mask filter per language.
+type MaskFilters struct {
+	filters  map[string]*MaskFilter
+	fallback *MaskFilter
+}
+
+// NewMaskFilters creates the filters with the word list used for the
+// languages without a list of their own.
+func NewMaskFilters(fallback FilterMap) *MaskFilters {
+	return &MaskFilters{
+		filters:  map[string]*MaskFilter{},
+		fallback: NewMaskFilter(fallback),
+	}
+}
+
+// LoadMaskFilters reads the word lists of a directory. Each file is named
+// after a BCP-47 language code with the .txt extension, e.g. hi.txt or
+// en-IN.txt, and holds a banned word or phrase per line. The default word
+// list is used for the other languages.
+func LoadMaskFilters(dir string) (*MaskFilters, error) {
+	filters := NewMaskFilters(DefaultFilterMap)
+	paths, err := filepath.Glob(filepath.Join(dir, "*.txt"))
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range paths {
+		filterMap, err := readWordList(path)
+		if err != nil {
+			return nil, err
+		}
+		filters.Add(strings.TrimSuffix(filepath.Base(path), ".txt"), *filterMap)
+	}
+	return filters, nil
+}
+
+func readWordList(path string) (*FilterMap, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	result := NewFilterMap()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		result.Set(strings.ToLower(line), "")
+	}
+	return result, scanner.Err()
+}
+
+// Add sets the word list of a language.
+func (l *MaskFilters) Add(language string, filterMap FilterMap) {
+	l.filters[strings.ToLower(language)] = NewMaskFilter(filterMap)
+}
+
+// For returns the filter of a language, falling back to its primary
+// language (hi for hi-IN) and then to the default word list.
+func (l *MaskFilters) For(language string) *MaskFilter {
+	language = strings.ToLower(language)
+	if f, ok := l.filters[language]; ok {
+		return f
+	}
+	if i := strings.IndexAny(language, "-_"); i >= 0 {
+		if f, ok := l.filters[language[:i]]; ok {
+			return f
+		}
+	}
+	return l.fallback
+}
@@ -21,6 +21,9 @@ package moderation
 // THE SOFTWARE.
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -69,3 +72,25 @@ func TestName4(t *testing.T) {
 	result = userFilter.Moderate(Message{From: "spin", Text: "Adjust position, velocity, accel?"})
 	assert.Equal(t, "", result)
 }
+
+func TestMaskFilter(t *testing.T) {
+	filter := NewMaskFilter(DefaultFilterMap)
+	assert.Equal(t, "what the h*** is this c***", filter.Moderate(Message{Text: "what the hell is this crap"}))
+	assert.Equal(t, []string{"a", "b***", "j**", "then", "G**", "d***"},
+		filter.MaskWords([]string{"a", "blow", "job", "then", "God", "damn"}))
+	assert.Equal(t, []string{"hello", "there"}, filter.MaskWords([]string{"hello", "there"}))
+}
+
+func TestMaskFilters(t *testing.T) {
+	dir, err := ioutil.TempDir("", "moderation")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "hi.txt"), []byte("# Hindi\nkutta\nbura aadmi\n"), 0644))
+
+	filters, err := LoadMaskFilters(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "k**** b*** a****", filters.For("hi-IN").Moderate(Message{Text: "kutta bura aadmi"}))
+	assert.Equal(t, "hell", filters.For("hi").Moderate(Message{Text: "hell"}))
+	assert.Equal(t, "h***", filters.For("en-US").Moderate(Message{Text: "hell"}))
+	assert.Equal(t, "h***", filters.For("").Moderate(Message{Text: "hell"}))
+}
@@ -0,0 +1,377 @@
+package speaker
+
+// Copyright (c) 2018 Bhojpur Consulting Private Limited, India. All rights reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// It identifies and verifies speakers by the embeddings (x-vectors) the
+// speech recognition engines compute for every utterance. Speakers are
+// enrolled from sample recordings; their averaged embeddings are kept in a
+// JSON database and compared with the embedding of an utterance by cosine
+// similarity.
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/bhojpur/speech/pkg/asr"
+)
+
+// Default similarity thresholds.
+const (
+	DefaultIdentifyThreshold = 0.5
+	DefaultVerifyThreshold   = 0.6
+)
+
+// ErrUnknownSpeaker is returned for speakers which are not enrolled.
+var ErrUnknownSpeaker = errors.New("unknown speaker")
+
+// ErrNoSpeech is returned when a recording yields no speaker embedding.
+var ErrNoSpeech = errors.New("no speech to compute a speaker embedding from")
+
+// Profile is an enrolled speaker.
+type Profile struct {
+	Name string `json:"name"`
+	// Embedding is the mean of the normalized sample embeddings.
+	Embedding []float64 `json:"embedding"`
+	// Samples is the number of embeddings the mean was computed from.
+	Samples int `json:"samples"`
+}
+
+// Match is the outcome of comparing an embedding with a profile.
+type Match struct {
+	Name  string
+	Score float64
+}
+
+// Database keeps the enrolled speakers. It is safe for concurrent use.
+type Database struct {
+	path              string
+	identifyThreshold float64
+	verifyThreshold   float64
+
+	mu       sync.RWMutex
+	profiles map[string]*Profile
+}
+
+// Option configures a Database.
+type Option func(*Database)
+
+// WithIdentifyThreshold sets the similarity an embedding must reach with
+// the best matching profile to be identified.
+func WithIdentifyThreshold(threshold float64) Option {
+	return func(d *Database) {
+		d.identifyThreshold = threshold
+	}
+}
+
+// WithVerifyThreshold sets the similarity an embedding must reach with the
+// claimed profile to be verified.
+func WithVerifyThreshold(threshold float64) Option {
+	return func(d *Database) {
+		d.verifyThreshold = threshold
+	}
+}
+
+// Open loads the database stored in the JSON file path. A missing file is
+// an empty database; the empty path keeps the database in memory only.
+func Open(path string, opts ...Option) (*Database, error) {
+	d := &Database{
+		path:              path,
+		identifyThreshold: DefaultIdentifyThreshold,
+		verifyThreshold:   DefaultVerifyThreshold,
+		profiles:          map[string]*Profile{},
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	if path == "" {
+		return d, nil
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return d, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var profiles []*Profile
+	if err := json.Unmarshal(data, &profiles); err != nil {
+		return nil, fmt.Errorf("invalid speaker database %s: %w", path, err)
+	}
+	for _, p := range profiles {
+		d.profiles[p.Name] = p
+	}
+	return d, nil
+}
+
+// Profiles returns the enrolled speakers sorted by name.
+func (d *Database) Profiles() []Profile {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.sorted()
+}
+
+func (d *Database) sorted() []Profile {
+	profiles := make([]Profile, 0, len(d.profiles))
+	for _, p := range d.profiles {
+		profiles = append(profiles, *p)
+	}
+	sort.Slice(profiles, func(i, j int) bool {
+		return profiles[i].Name < profiles[j].Name
+	})
+	return profiles
+}
+
+// Has reports whether the speaker is enrolled.
+func (d *Database) Has(name string) bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	_, ok := d.profiles[name]
+	return ok
+}
+
+// Enroll adds sample embeddings to the profile of a speaker, creating it
+// if needed, and saves the database.
+func (d *Database) Enroll(name string, embeddings ...[]float64) (Profile, error) {
+	if name == "" {
+		return Profile{}, errors.New("speaker name is required")
+	}
+	if len(embeddings) == 0 {
+		return Profile{}, ErrNoSpeech
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var mean []float64
+	var samples int
+	old, ok := d.profiles[name]
+	if ok {
+		mean = append(mean, old.Embedding...)
+		samples = old.Samples
+	}
+	for _, embedding := range embeddings {
+		if len(mean) == 0 {
+			mean = make([]float64, len(embedding))
+		}
+		if len(embedding) != len(mean) {
+			return Profile{}, fmt.Errorf("embedding of %d dimensions, speaker %q has %d", len(embedding), name, len(mean))
+		}
+		unit := normalize(embedding)
+		samples++
+		for i := range mean {
+			mean[i] += (unit[i] - mean[i]) / float64(samples)
+		}
+	}
+	p := &Profile{Name: name, Embedding: mean, Samples: samples}
+	d.profiles[name] = p
+	if err := d.save(); err != nil {
+		if ok {
+			d.profiles[name] = old
+		} else {
+			delete(d.profiles, name)
+		}
+		return Profile{}, err
+	}
+	return *p, nil
+}
+
+// Remove deletes the profile of a speaker and saves the database.
+func (d *Database) Remove(name string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.profiles[name]; !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownSpeaker, name)
+	}
+	delete(d.profiles, name)
+	return d.save()
+}
+
+// save writes the database atomically. It is called with the lock held.
+func (d *Database) save() error {
+	if d.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(d.sorted(), "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(d.path), filepath.Base(d.path)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), d.path)
+}
+
+// Identify returns the profile most similar to the embedding. It reports
+// false when the best score is below the identification threshold or no
+// speaker is enrolled.
+func (d *Database) Identify(embedding []float64) (Match, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	best := Match{Score: -1}
+	for _, p := range d.profiles {
+		score := Similarity(embedding, p.Embedding)
+		if score > best.Score || (score == best.Score && p.Name < best.Name) {
+			best = Match{Name: p.Name, Score: score}
+		}
+	}
+	if best.Name == "" {
+		return Match{}, false
+	}
+	return best, best.Score >= d.identifyThreshold
+}
+
+// Verify compares the embedding with the profile of the claimed speaker.
+// It reports whether the score reaches the verification threshold.
+func (d *Database) Verify(name string, embedding []float64) (Match, bool, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	p, ok := d.profiles[name]
+	if !ok {
+		return Match{}, false, fmt.Errorf("%w: %q", ErrUnknownSpeaker, name)
+	}
+	match := Match{Name: name, Score: Similarity(embedding, p.Embedding)}
+	return match, match.Score >= d.verifyThreshold, nil
+}
+
+// Similarity returns the cosine similarity of two embeddings, between -1
+// and 1. Embeddings of different dimensions have no similarity.
+func Similarity(a, b []float64) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += a[i] * b[i]
+		na += a[i] * a[i]
+		nb += b[i] * b[i]
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / math.Sqrt(na*nb)
+}
+
+func normalize(v []float64) []float64 {
+	var norm float64
+	for _, x := range v {
+		norm += x * x
+	}
+	norm = math.Sqrt(norm)
+	out := make([]float64, len(v))
+	if norm == 0 {
+		return out
+	}
+	for i, x := range v {
+		out[i] = x / norm
+	}
+	return out
+}
+
+// Embed computes the speaker embedding of a recording of 16-bit mono PCM at
+// the engine's sample rate. The embeddings of its utterances are averaged,
+// weighted by their number of frames.
+func Embed(engine asr.Engine, pcm []byte) ([]float64, error) {
+	rec, err := engine.NewRecognizer(asr.Config{
+		SampleRate: engine.SampleRate(),
+		Speaker:    true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer rec.Close()
+
+	var sum []float64
+	var frames int
+	add := func(res *asr.Result) error {
+		if len(res.Speaker) == 0 {
+			return nil
+		}
+		if sum == nil {
+			sum = make([]float64, len(res.Speaker))
+		}
+		if len(res.Speaker) != len(sum) {
+			return errors.New("speaker embeddings of different dimensions")
+		}
+		weight := res.SpeakerFrames
+		if weight <= 0 {
+			weight = 1
+		}
+		for i, x := range normalize(res.Speaker) {
+			sum[i] += x * float64(weight)
+		}
+		frames += weight
+		return nil
+	}
+
+	const chunkSize = 8192
+	for offset := 0; offset < len(pcm); offset += chunkSize {
+		end := offset + chunkSize
+		if end > len(pcm) {
+			end = len(pcm)
+		}
+		endpoint, err := rec.AcceptWaveform(pcm[offset:end])
+		if err != nil {
+			return nil, err
+		}
+		if !endpoint {
+			continue
+		}
+		res, err := rec.Result()
+		if err != nil {
+			return nil, err
+		}
+		if err := add(res); err != nil {
+			return nil, err
+		}
+	}
+	res, err := rec.FinalResult()
+	if err != nil {
+		return nil, err
+	}
+	if err := add(res); err != nil {
+		return nil, err
+	}
+
+	if frames == 0 {
+		return nil, ErrNoSpeech
+	}
+	for i := range sum {
+		sum[i] /= float64(frames)
+	}
+	return sum, nil
+}
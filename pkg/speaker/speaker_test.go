@@ -0,0 +1,133 @@
+package speaker
+
+// Copyright (c) 2018 Bhojpur Consulting Private Limited, India. All rights reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bhojpur/speech/pkg/asr/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimilarity(t *testing.T) {
+	assert.InDelta(t, 1, Similarity([]float64{1, 2}, []float64{2, 4}), 1e-9)
+	assert.InDelta(t, 0, Similarity([]float64{1, 0}, []float64{0, 3}), 1e-9)
+	assert.InDelta(t, -1, Similarity([]float64{1, 1}, []float64{-1, -1}), 1e-9)
+	assert.Equal(t, 0.0, Similarity([]float64{1}, []float64{1, 0}))
+	assert.Equal(t, 0.0, Similarity([]float64{0, 0}, []float64{1, 0}))
+}
+
+func TestEnrollPersists(t *testing.T) {
+	dir, err := ioutil.TempDir("", "speakers")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "speakers.json")
+
+	db, err := Open(path)
+	require.NoError(t, err)
+	assert.Empty(t, db.Profiles())
+
+	// samples are normalized before averaging
+	p, err := db.Enroll("asha", []float64{2, 0}, []float64{0, 1})
+	require.NoError(t, err)
+	assert.Equal(t, 2, p.Samples)
+	assert.InDeltaSlice(t, []float64{0.5, 0.5}, p.Embedding, 1e-9)
+	p, err = db.Enroll("asha", []float64{0, 3})
+	require.NoError(t, err)
+	assert.Equal(t, 3, p.Samples)
+	assert.InDeltaSlice(t, []float64{1.0 / 3, 2.0 / 3}, p.Embedding, 1e-9)
+
+	_, err = db.Enroll("asha", []float64{1, 2, 3})
+	assert.Error(t, err)
+	_, err = db.Enroll("ravi")
+	assert.ErrorIs(t, err, ErrNoSpeech)
+	_, err = db.Enroll("ravi", []float64{-1, 0})
+	require.NoError(t, err)
+
+	db, err = Open(path)
+	require.NoError(t, err)
+	profiles := db.Profiles()
+	require.Len(t, profiles, 2)
+	assert.Equal(t, "asha", profiles[0].Name)
+	assert.Equal(t, 3, profiles[0].Samples)
+	assert.Equal(t, "ravi", profiles[1].Name)
+
+	require.NoError(t, db.Remove("ravi"))
+	assert.ErrorIs(t, db.Remove("ravi"), ErrUnknownSpeaker)
+	db, err = Open(path)
+	require.NoError(t, err)
+	assert.False(t, db.Has("ravi"))
+	assert.True(t, db.Has("asha"))
+}
+
+func TestIdentifyAndVerify(t *testing.T) {
+	db, err := Open("", WithIdentifyThreshold(0.8), WithVerifyThreshold(0.9))
+	require.NoError(t, err)
+
+	_, ok := db.Identify([]float64{1, 0, 0})
+	assert.False(t, ok)
+
+	_, err = db.Enroll("asha", []float64{1, 0, 0})
+	require.NoError(t, err)
+	_, err = db.Enroll("ravi", []float64{0, 1, 0})
+	require.NoError(t, err)
+
+	match, ok := db.Identify([]float64{0.9, 0.2, 0.1})
+	assert.True(t, ok)
+	assert.Equal(t, "asha", match.Name)
+	assert.Greater(t, match.Score, 0.9)
+
+	match, ok = db.Identify([]float64{0, 0, 1})
+	assert.False(t, ok)
+
+	match, ok, err = db.Verify("ravi", []float64{0.3, 1, 0})
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "ravi", match.Name)
+
+	match, ok, err = db.Verify("ravi", []float64{0.6, 1, 0})
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Less(t, match.Score, 0.9)
+
+	_, _, err = db.Verify("nobody", []float64{1, 0, 0})
+	assert.ErrorIs(t, err, ErrUnknownSpeaker)
+}
+
+func TestEmbed(t *testing.T) {
+	engine := fake.NewEngine(16000,
+		fake.Utterance{Text: "good morning", Seconds: 1, Speaker: []float64{1, 0}},
+		fake.Utterance{Text: "doctor", Seconds: 3, Speaker: []float64{0, 2}},
+	)
+
+	// the longer utterance weighs more
+	embedding, err := Embed(engine, make([]byte, 4*16000*2))
+	require.NoError(t, err)
+	assert.InDeltaSlice(t, []float64{0.25, 0.75}, embedding, 1e-9)
+	assert.Equal(t, 0, engine.Active())
+
+	_, err = Embed(fake.NewEngine(16000, fake.Utterance{Text: "hello", Seconds: 1}), make([]byte, 16000*2))
+	assert.ErrorIs(t, err, ErrNoSpeech)
+}
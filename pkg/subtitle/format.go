@@ -0,0 +1,367 @@
+package subtitle
+
+// Copyright (c) 2018 Bhojpur Consulting Private Limited, India. All rights reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Format of a subtitle file.
+type Format int
+
+const (
+	SRT    Format = iota // SubRip
+	WebVTT               // Web Video Text Tracks
+	TTML                 // Timed Text Markup Language
+)
+
+// String implements the stringer interface.
+func (f Format) String() string {
+	switch f {
+	case SRT:
+		return "srt"
+	case WebVTT:
+		return "vtt"
+	case TTML:
+		return "ttml"
+	default:
+		return fmt.Sprintf("Format(%d)", int(f))
+	}
+}
+
+// Write writes the cues to w in the format.
+func Write(w io.Writer, cues []Cue, format Format) error {
+	var b bytes.Buffer
+	switch format {
+	case SRT, WebVTT:
+		if format == WebVTT {
+			b.WriteString("WEBVTT\n\n")
+		}
+		for i, c := range cues {
+			if format == SRT {
+				fmt.Fprintf(&b, "%d\n", i+1)
+			}
+			fmt.Fprintf(&b, "%s --> %s\n", timestamp(c.Start, format), timestamp(c.End, format))
+			for _, line := range c.Lines {
+				b.WriteString(line)
+				b.WriteByte('\n')
+			}
+			b.WriteByte('\n')
+		}
+	case TTML:
+		b.WriteString(xml.Header)
+		b.WriteString("<tt xmlns=\"http://www.w3.org/ns/ttml\">\n  <body>\n    <div>\n")
+		for _, c := range cues {
+			fmt.Fprintf(&b, "      <p begin=\"%s\" end=\"%s\">", timestamp(c.Start, format), timestamp(c.End, format))
+			for i, line := range c.Lines {
+				if i > 0 {
+					b.WriteString("<br/>")
+				}
+				xml.EscapeText(&b, []byte(line))
+			}
+			b.WriteString("</p>\n")
+		}
+		b.WriteString("    </div>\n  </body>\n</tt>\n")
+	default:
+		return fmt.Errorf("subtitle: unknown format %v", format)
+	}
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// timestamp formats a cue time, SRT separates the milliseconds with a comma.
+func timestamp(d time.Duration, format Format) string {
+	ms := d.Milliseconds()
+	sep := "."
+	if format == SRT {
+		sep = ","
+	}
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d", ms/3600000, ms/60000%60, ms/1000%60, sep, ms%1000)
+}
+
+// Read parses a subtitle file, whose format is detected from its content.
+func Read(r io.Reader) ([]Cue, Format, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, 0, err
+	}
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("WEBVTT")):
+		cues, err := readText(data, WebVTT)
+		return cues, WebVTT, err
+	case bytes.HasPrefix(trimmed, []byte("<")):
+		cues, err := readTTML(data)
+		return cues, TTML, err
+	default:
+		cues, err := readText(data, SRT)
+		return cues, SRT, err
+	}
+}
+
+// readText parses the blocks of SRT and WebVTT files, which are separated
+// by blank lines.
+func readText(data []byte, format Format) ([]Cue, error) {
+	var cues []Cue
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	var block []string
+	first, n := 0, 0
+	flush := func() error {
+		defer func() { block = nil }()
+		if len(block) == 0 {
+			return nil
+		}
+		// the header, comments, styles and regions of WebVTT
+		if format == WebVTT && (strings.HasPrefix(block[0], "WEBVTT") || strings.HasPrefix(block[0], "NOTE") ||
+			block[0] == "STYLE" || block[0] == "REGION") {
+			return nil
+		}
+		// the timing follows the optional identifier
+		i := 0
+		if !strings.Contains(block[0], "-->") {
+			i = 1
+		}
+		if i >= len(block) || !strings.Contains(block[i], "-->") {
+			return fmt.Errorf("subtitle: line %d: missing cue timing", first+1)
+		}
+		start, end, err := parseTiming(block[i])
+		if err != nil {
+			return fmt.Errorf("subtitle: line %d: %w", first+i+1, err)
+		}
+		cues = append(cues, Cue{Start: start, End: end, Lines: block[i+1:]})
+		return nil
+	}
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		n++
+		if strings.TrimSpace(line) == "" {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if len(block) == 0 {
+			first = n - 1
+		}
+		block = append(block, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return cues, nil
+}
+
+// parseTiming parses a "start --> end" line. WebVTT settings may follow.
+func parseTiming(line string) (time.Duration, time.Duration, error) {
+	parts := strings.SplitN(line, "-->", 2)
+	fields := strings.Fields(parts[1])
+	if len(fields) == 0 {
+		return 0, 0, fmt.Errorf("invalid cue timing %q", line)
+	}
+	start, err := parseClock(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return 0, 0, err
+	}
+	end, err := parseClock(fields[0])
+	if err != nil {
+		return 0, 0, err
+	}
+	return start, end, nil
+}
+
+// parseClock parses a [hours:]minutes:seconds[.,]milliseconds time.
+func parseClock(s string) (time.Duration, error) {
+	fields := strings.Split(strings.Replace(s, ",", ".", 1), ":")
+	if len(fields) < 2 || len(fields) > 3 {
+		return 0, fmt.Errorf("invalid timestamp %q", s)
+	}
+	var d time.Duration
+	for i, field := range fields {
+		unit := time.Minute
+		if i == len(fields)-1 {
+			seconds, err := strconv.ParseFloat(field, 64)
+			if err != nil || seconds < 0 {
+				return 0, fmt.Errorf("invalid timestamp %q", s)
+			}
+			d += time.Duration(seconds*1000+0.5) * time.Millisecond
+			break
+		}
+		if i == 0 && len(fields) == 3 {
+			unit = time.Hour
+		}
+		v, err := strconv.Atoi(field)
+		if err != nil || v < 0 {
+			return 0, fmt.Errorf("invalid timestamp %q", s)
+		}
+		d += time.Duration(v) * unit
+	}
+	return d, nil
+}
+
+// defaultFrameRate is the frame rate of TTML documents not declaring one.
+const defaultFrameRate = 30
+
+// readTTML parses the paragraphs of a TTML document. The times of nested
+// elements are not inherited.
+func readTTML(data []byte) ([]Cue, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	frameRate := float64(defaultFrameRate)
+	var cues []Cue
+	var cue *Cue
+	var line strings.Builder
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("subtitle: %w", err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "tt":
+				if rate := attr(t, "frameRate"); rate != "" {
+					if frameRate, err = strconv.ParseFloat(rate, 64); err != nil || frameRate <= 0 {
+						return nil, fmt.Errorf("subtitle: invalid frame rate %q", rate)
+					}
+				}
+			case "p":
+				start, end, err := ttmlTiming(t, frameRate)
+				if err != nil {
+					return nil, err
+				}
+				cue = &Cue{Start: start, End: end}
+				line.Reset()
+			case "br":
+				if cue != nil {
+					cue.Lines = append(cue.Lines, collapse(line.String()))
+					line.Reset()
+				}
+			}
+		case xml.CharData:
+			if cue != nil {
+				line.Write(t)
+			}
+		case xml.EndElement:
+			if t.Name.Local == "p" && cue != nil {
+				cue.Lines = append(cue.Lines, collapse(line.String()))
+				cues = append(cues, *cue)
+				cue = nil
+			}
+		}
+	}
+	return cues, nil
+}
+
+// collapse joins the words of a text by single spaces, the way white space
+// is rendered in TTML.
+func collapse(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// attr returns the value of the attribute with the local name.
+func attr(e xml.StartElement, name string) string {
+	for _, a := range e.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// ttmlTiming returns the begin and end of a paragraph, the end being given
+// either directly or as a duration.
+func ttmlTiming(e xml.StartElement, frameRate float64) (time.Duration, time.Duration, error) {
+	begin, err := ttmlTime(attr(e, "begin"), frameRate)
+	if err != nil {
+		return 0, 0, err
+	}
+	if end := attr(e, "end"); end != "" {
+		d, err := ttmlTime(end, frameRate)
+		return begin, d, err
+	}
+	if dur := attr(e, "dur"); dur != "" {
+		d, err := ttmlTime(dur, frameRate)
+		return begin, begin + d, err
+	}
+	return 0, 0, errors.New("subtitle: paragraph without end")
+}
+
+// ttmlTime parses a TTML clock time, hours:minutes:seconds with a fraction
+// or a frame count, or an offset time such as 1.5s or 200ms.
+func ttmlTime(s string, frameRate float64) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	fields := strings.Split(s, ":")
+	switch len(fields) {
+	case 3:
+		d, err := parseClock(s)
+		if err != nil {
+			return 0, fmt.Errorf("subtitle: %w", err)
+		}
+		return d, nil
+	case 4:
+		clock, err := parseClock(strings.Join(fields[:3], ":"))
+		if err != nil {
+			return 0, fmt.Errorf("subtitle: %w", err)
+		}
+		frames, err := strconv.ParseFloat(fields[3], 64)
+		if err != nil {
+			return 0, fmt.Errorf("subtitle: invalid time %q", s)
+		}
+		return clock + time.Duration(frames/frameRate*float64(time.Second)), nil
+	}
+
+	units := []struct {
+		suffix string
+		unit   float64
+	}{
+		{"ms", float64(time.Millisecond)},
+		{"h", float64(time.Hour)},
+		{"m", float64(time.Minute)},
+		{"s", float64(time.Second)},
+		{"f", float64(time.Second) / frameRate},
+	}
+	for _, u := range units {
+		if strings.HasSuffix(s, u.suffix) {
+			v, err := strconv.ParseFloat(strings.TrimSuffix(s, u.suffix), 64)
+			if err != nil {
+				break
+			}
+			return time.Duration(v * u.unit), nil
+		}
+	}
+	return 0, fmt.Errorf("subtitle: invalid time %q", s)
+}
@@ -0,0 +1,321 @@
+package subtitle
+
+// Copyright (c) 2018 Bhojpur Consulting Private Limited, India. All rights reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package subtitle lays out timed words into captions, and reads and writes
+// them as SRT, WebVTT or TTML files.
+//
+// Cues are limited in characters per line, number of lines and duration,
+// and are shown long enough to be read. They preferably end with a sentence
+// or at a pause of the speaker.
+
+import (
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/bhojpur/speech/pkg/asr"
+)
+
+// Default layout of the cues.
+const (
+	DefaultMaxLineLength = 42
+	DefaultMaxLines      = 2
+	DefaultMinDuration   = time.Second
+	DefaultMaxDuration   = 7 * time.Second
+	DefaultReadingSpeed  = 17 // characters per second
+	DefaultPause         = 500 * time.Millisecond
+)
+
+// maxGap is a silence no cue is shown across.
+const maxGap = 2 * time.Second
+
+// Cue is a caption shown from Start to End.
+type Cue struct {
+	Start time.Duration
+	End   time.Duration
+	Lines []string
+}
+
+// Text returns the lines of the cue joined by spaces.
+func (c Cue) Text() string {
+	return strings.Join(c.Lines, " ")
+}
+
+// Option configures the layout of the cues.
+type Option func(*layout)
+
+// WithMaxLineLength sets the maximum number of characters of a line. A word
+// longer than that still gets a line of its own.
+func WithMaxLineLength(chars int) Option {
+	return func(l *layout) {
+		l.maxLineLength = chars
+	}
+}
+
+// WithMaxLines sets the maximum number of lines of a cue.
+func WithMaxLines(n int) Option {
+	return func(l *layout) {
+		l.maxLines = n
+	}
+}
+
+// WithMinDuration sets the duration a cue is at least shown, unless the next
+// one starts earlier.
+func WithMinDuration(d time.Duration) Option {
+	return func(l *layout) {
+		l.minDuration = d
+	}
+}
+
+// WithMaxDuration sets the maximum duration of a cue.
+func WithMaxDuration(d time.Duration) Option {
+	return func(l *layout) {
+		l.maxDuration = d
+	}
+}
+
+// WithReadingSpeed sets the characters read per second. Cues spoken faster
+// are shown longer, unless the next one starts earlier.
+func WithReadingSpeed(chars float64) Option {
+	return func(l *layout) {
+		l.readingSpeed = chars
+	}
+}
+
+// WithPause sets the silence between two words which is a preferred break.
+func WithPause(d time.Duration) Option {
+	return func(l *layout) {
+		l.pause = d
+	}
+}
+
+// layout holds the constraints of the cues.
+type layout struct {
+	maxLineLength int
+	maxLines      int
+	minDuration   time.Duration
+	maxDuration   time.Duration
+	readingSpeed  float64
+	pause         time.Duration
+}
+
+// Build lays out the words, in order, into cues.
+func Build(words []asr.Word, opts ...Option) []Cue {
+	l := &layout{
+		maxLineLength: DefaultMaxLineLength,
+		maxLines:      DefaultMaxLines,
+		minDuration:   DefaultMinDuration,
+		maxDuration:   DefaultMaxDuration,
+		readingSpeed:  DefaultReadingSpeed,
+		pause:         DefaultPause,
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	var cues []Cue
+	for i := 0; i < len(words); {
+		j := l.end(words, i)
+		texts := make([]string, j-i)
+		for k, w := range words[i:j] {
+			texts[k] = w.Word
+		}
+		cues = append(cues, Cue{
+			Start: words[i].Start,
+			End:   words[j-1].End,
+			Lines: l.lines(texts),
+		})
+		i = j
+	}
+	l.retime(cues)
+	return cues
+}
+
+// end returns the end of the cue starting with word i.
+func (l *layout) end(words []asr.Word, i int) int {
+	for j := i + 1; j < len(words); j++ {
+		gap := words[j].Start - words[j-1].End
+		if gap >= maxGap || (gap >= l.pause && sentenceEnd(words[j-1].Word)) {
+			return j
+		}
+		if !l.fits(words[i : j+1]) {
+			return l.bestBreak(words, i, j)
+		}
+	}
+	return len(words)
+}
+
+// fits reports whether the words make a valid cue.
+func (l *layout) fits(words []asr.Word) bool {
+	if words[len(words)-1].End-words[0].Start > l.maxDuration {
+		return false
+	}
+	texts := make([]string, len(words))
+	for k, w := range words {
+		texts[k] = w.Word
+	}
+	return len(wrap(texts, l.maxLineLength)) <= l.maxLines
+}
+
+// bestBreak returns where to end the cue starting with word i when word j
+// does not fit anymore. It is the strongest boundary leaving at least a third
+// of the text in the cue, so that a cue is not cut short for a weak one, the
+// latest on ties.
+func (l *layout) bestBreak(words []asr.Word, i, j int) int {
+	length := func(k int) int {
+		n := k - i - 1
+		for _, w := range words[i:k] {
+			n += utf8.RuneCountInString(w.Word)
+		}
+		return n
+	}
+	min := length(j) / 3
+	best, score := j, l.score(words[j-1], words[j])
+	for k := j - 1; k > i && length(k) >= min; k-- {
+		if s := l.score(words[k-1], words[k]); s > score {
+			best, score = k, s
+		}
+	}
+	return best
+}
+
+// score rates the boundary between two words.
+func (l *layout) score(prev, next asr.Word) int {
+	s := 0
+	switch {
+	case sentenceEnd(prev.Word):
+		s += 2
+	case clauseEnd(prev.Word):
+		s++
+	}
+	if next.Start-prev.End >= l.pause {
+		s += 2
+	}
+	return s
+}
+
+// lines wraps the words of a cue. Two lines are balanced, and preferably
+// split after a punctuation mark.
+func (l *layout) lines(words []string) []string {
+	lines := wrap(words, l.maxLineLength)
+	if len(lines) != 2 {
+		return lines
+	}
+	best, cost, found := lines, 0, false
+	for s := 1; s < len(words); s++ {
+		first, second := strings.Join(words[:s], " "), strings.Join(words[s:], " ")
+		n1, n2 := utf8.RuneCountInString(first), utf8.RuneCountInString(second)
+		if n1 > l.maxLineLength || n2 > l.maxLineLength {
+			continue
+		}
+		c := n1 - n2
+		if c < 0 {
+			c = -c
+		}
+		if clauseEnd(words[s-1]) || sentenceEnd(words[s-1]) {
+			c -= l.maxLineLength / 4
+		}
+		if !found || c < cost {
+			best, cost, found = []string{first, second}, c, true
+		}
+	}
+	return best
+}
+
+// retime keeps the cues within their minimum and maximum durations, and
+// extends the cues spoken faster than they can be read, without overlapping
+// the next cue.
+func (l *layout) retime(cues []Cue) {
+	for k := range cues {
+		c := &cues[k]
+		need := l.minDuration
+		if l.readingSpeed > 0 {
+			read := time.Duration(float64(utf8.RuneCountInString(c.Text())) / l.readingSpeed * float64(time.Second))
+			if read > need {
+				need = read
+			}
+		}
+		if need > l.maxDuration {
+			need = l.maxDuration
+		}
+		if c.End-c.Start < need {
+			c.End = c.Start + need
+			if k+1 < len(cues) && c.End > cues[k+1].Start {
+				c.End = cues[k+1].Start
+			}
+		}
+		if c.End-c.Start > l.maxDuration {
+			c.End = c.Start + l.maxDuration
+		}
+	}
+}
+
+// wrap fills lines of at most max characters with the words.
+func wrap(words []string, max int) []string {
+	var lines []string
+	var line string
+	for _, w := range words {
+		switch {
+		case line == "":
+			line = w
+		case utf8.RuneCountInString(line)+1+utf8.RuneCountInString(w) <= max:
+			line += " " + w
+		default:
+			lines = append(lines, line)
+			line = w
+		}
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// sentenceEnd reports whether a word ends a sentence, which includes the
+// danda of the Indic scripts.
+func sentenceEnd(word string) bool {
+	switch lastMark(word) {
+	case '.', '?', '!', '…', '।', '॥':
+		return true
+	}
+	return false
+}
+
+// clauseEnd reports whether a word ends a clause.
+func clauseEnd(word string) bool {
+	switch lastMark(word) {
+	case ',', ';', ':', '–', '—':
+		return true
+	}
+	return false
+}
+
+// lastMark returns the last rune of a word, past closing quotes and
+// brackets.
+func lastMark(word string) rune {
+	word = strings.TrimRightFunc(word, func(r rune) bool {
+		return unicode.Is(unicode.Pe, r) || unicode.Is(unicode.Pf, r) || r == '"' || r == '\''
+	})
+	r, _ := utf8.DecodeLastRuneInString(word)
+	return r
+}
@@ -0,0 +1,230 @@
+package subtitle
+
+// Copyright (c) 2018 Bhojpur Consulting Private Limited, India. All rights reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/bhojpur/speech/pkg/asr"
+	"github.com/stretchr/testify/assert"
+)
+
+// timed spreads the words of a text over consecutive slots of the given
+// length, starting at start.
+func timed(text string, start, slot time.Duration) []asr.Word {
+	var words []asr.Word
+	for _, w := range strings.Fields(text) {
+		words = append(words, asr.Word{Word: w, Start: start, End: start + slot})
+		start += slot
+	}
+	return words
+}
+
+func texts(cues []Cue) []string {
+	var out []string
+	for _, c := range cues {
+		out = append(out, c.Text())
+	}
+	return out
+}
+
+func TestBuildLimits(t *testing.T) {
+	words := timed(strings.Repeat("the quick brown fox jumps over the lazy dog ", 20), 0, 250*time.Millisecond)
+	cues := Build(words)
+
+	var all []string
+	for i, c := range cues {
+		assert.LessOrEqual(t, len(c.Lines), DefaultMaxLines)
+		for _, line := range c.Lines {
+			assert.LessOrEqual(t, utf8.RuneCountInString(line), DefaultMaxLineLength)
+		}
+		assert.LessOrEqual(t, c.End-c.Start, DefaultMaxDuration)
+		if i > 0 {
+			assert.LessOrEqual(t, cues[i-1].End, c.Start)
+		}
+		all = append(all, c.Text())
+	}
+	assert.Equal(t, strings.TrimSpace(strings.Repeat("the quick brown fox jumps over the lazy dog ", 20)), strings.Join(all, " "))
+
+	cues = Build(words[:20], WithMaxLineLength(20), WithMaxLines(1))
+	for _, c := range cues {
+		assert.Len(t, c.Lines, 1)
+		assert.LessOrEqual(t, len(c.Lines[0]), 20)
+	}
+
+	cues = Build(timed("one two three four five six", 0, time.Second), WithMaxDuration(2500*time.Millisecond))
+	assert.Equal(t, []string{"one two", "three four", "five six"}, texts(cues))
+}
+
+func TestBuildSentenceBreak(t *testing.T) {
+	words := timed("We went to the market early in the morning. Then we walked along the river until the sun set behind the hills", 0, 200*time.Millisecond)
+	cues := Build(words)
+	assert.Equal(t, "We went to the market early in the morning.", cues[0].Text())
+
+	// a sentence ending at a pause always ends the cue
+	words = append(timed("Yes.", 0, 300*time.Millisecond), timed("Come in.", 900*time.Millisecond, 300*time.Millisecond)...)
+	assert.Equal(t, []string{"Yes.", "Come in."}, texts(Build(words)))
+
+	// but not without a pause
+	words = timed("Yes. Come in.", 0, 300*time.Millisecond)
+	assert.Equal(t, []string{"Yes. Come in."}, texts(Build(words)))
+
+	// the danda ends Hindi sentences
+	words = timed("मैं कल सुबह अपने घर जा रहा हूँ। आप परसों दफ़्तर में मिलिए और सारे ज़रूरी काग़ज़ात अपने साथ लेकर आइए", 0, 200*time.Millisecond)
+	assert.Equal(t, "मैं कल सुबह अपने घर जा रहा हूँ।", Build(words)[0].Text())
+}
+
+func TestBuildPauseBreak(t *testing.T) {
+	first := timed("so I told him that we would be coming", 0, 200*time.Millisecond)
+	second := timed("over tomorrow after the meeting with the rest of the team at the office", 2400*time.Millisecond, 200*time.Millisecond)
+	cues := Build(append(first, second...))
+	assert.Equal(t, "so I told him that we would be coming", cues[0].Text())
+
+	// a long silence always ends the cue
+	cues = Build(append(timed("hello", 0, time.Second), timed("again", 4*time.Second, time.Second)...))
+	assert.Equal(t, []string{"hello", "again"}, texts(cues))
+}
+
+func TestBuildTiming(t *testing.T) {
+	// short cues are shown for the minimum duration
+	cues := Build(append(timed("hi", 0, 200*time.Millisecond), timed("there", 3*time.Second, 200*time.Millisecond)...))
+	assert.Equal(t, time.Second, cues[0].End)
+
+	// or until the next one
+	cues = Build(append(timed("hi.", 0, 200*time.Millisecond), timed("there", 700*time.Millisecond, 200*time.Millisecond)...))
+	assert.Equal(t, 700*time.Millisecond, cues[0].End)
+
+	// fast speech is shown long enough to be read
+	text := "incomprehensibilities notwithstanding"
+	speed := float64(DefaultReadingSpeed)
+	cues = Build(timed(text, 0, 500*time.Millisecond))
+	assert.Equal(t, time.Duration(float64(len(text))/speed*float64(time.Second)), cues[0].End)
+
+	// a single long word is cut at the maximum duration
+	cues = Build([]asr.Word{{Word: "hmmmm", Start: time.Second, End: 20 * time.Second}})
+	assert.Equal(t, time.Second+DefaultMaxDuration, cues[0].End)
+}
+
+func TestLines(t *testing.T) {
+	l := &layout{maxLineLength: 42}
+	assert.Equal(t, []string{"one two three four five six seven", "eight nine ten eleven twelve"},
+		l.lines(strings.Fields("one two three four five six seven eight nine ten eleven twelve")))
+	assert.Equal(t, []string{"I suppose that we could,", "if you like, go there by train"},
+		l.lines(strings.Fields("I suppose that we could, if you like, go there by train")))
+	assert.Equal(t, []string{"short line"}, l.lines([]string{"short", "line"}))
+}
+
+func TestRoundTrip(t *testing.T) {
+	cues := Build(timed("We went to the market early. Then we walked along the river <until> the sun & the moon", 0, 300*time.Millisecond))
+	for _, format := range []Format{SRT, WebVTT, TTML} {
+		var buf bytes.Buffer
+		assert.NoError(t, Write(&buf, cues, format))
+		read, detected, err := Read(&buf)
+		assert.NoError(t, err)
+		assert.Equal(t, format, detected)
+		assert.Equal(t, cues, read, format.String())
+	}
+}
+
+func TestWrite(t *testing.T) {
+	cues := []Cue{{Start: 1500 * time.Millisecond, End: 3723004 * time.Millisecond, Lines: []string{"a < b", "c"}}}
+	var buf bytes.Buffer
+	assert.NoError(t, Write(&buf, cues, SRT))
+	assert.Equal(t, "1\n00:00:01,500 --> 01:02:03,004\na < b\nc\n\n", buf.String())
+
+	buf.Reset()
+	assert.NoError(t, Write(&buf, cues, WebVTT))
+	assert.Equal(t, "WEBVTT\n\n00:00:01.500 --> 01:02:03.004\na < b\nc\n\n", buf.String())
+
+	buf.Reset()
+	assert.NoError(t, Write(&buf, cues, TTML))
+	assert.Contains(t, buf.String(), `<p begin="00:00:01.500" end="01:02:03.004">a &lt; b<br/>c</p>`)
+}
+
+func TestReadSRT(t *testing.T) {
+	cues, format, err := Read(strings.NewReader("\xef\xbb\xbf1\r\n00:00:01,000 --> 00:00:02,500\r\nHello\r\nworld\r\n\r\n\r\n2\r\n00:00:03,000 --> 00:00:04,000\r\nAgain\r\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, SRT, format)
+	assert.Equal(t, []Cue{
+		{Start: time.Second, End: 2500 * time.Millisecond, Lines: []string{"Hello", "world"}},
+		{Start: 3 * time.Second, End: 4 * time.Second, Lines: []string{"Again"}},
+	}, cues)
+
+	_, _, err = Read(strings.NewReader("1\n00:00:01,000 -> 00:00:02,000\nHello\n"))
+	assert.Error(t, err)
+	_, _, err = Read(strings.NewReader("1\n00:00:01,000 --> soon\nHello\n"))
+	assert.Error(t, err)
+}
+
+func TestReadWebVTT(t *testing.T) {
+	cues, format, err := Read(strings.NewReader(`WEBVTT - captions
+Kind: captions
+
+NOTE this is
+a comment
+
+STYLE
+::cue { color: yellow }
+
+intro
+00:01.000 --> 00:02.000 align:start line:0
+<v Anna>Hello</v>
+
+00:00:03.250 --> 00:00:04.000
+Again
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, WebVTT, format)
+	assert.Equal(t, []Cue{
+		{Start: time.Second, End: 2 * time.Second, Lines: []string{"<v Anna>Hello</v>"}},
+		{Start: 3250 * time.Millisecond, End: 4 * time.Second, Lines: []string{"Again"}},
+	}, cues)
+}
+
+func TestReadTTML(t *testing.T) {
+	cues, format, err := Read(strings.NewReader(`<?xml version="1.0" encoding="UTF-8"?>
+<tt xmlns="http://www.w3.org/ns/ttml" xmlns:ttp="http://www.w3.org/ns/ttml#parameter" ttp:frameRate="25">
+  <body>
+    <div>
+      <p begin="1.5s" dur="500ms">Hello
+        <span>big</span> world</p>
+      <p begin="00:00:03:05" end="00:00:04.000">two<br/>lines</p>
+      <p begin="0.1h" end="6m">late</p>
+    </div>
+  </body>
+</tt>`))
+	assert.NoError(t, err)
+	assert.Equal(t, TTML, format)
+	assert.Equal(t, []Cue{
+		{Start: 1500 * time.Millisecond, End: 2 * time.Second, Lines: []string{"Hello big world"}},
+		{Start: 3200 * time.Millisecond, End: 4 * time.Second, Lines: []string{"two", "lines"}},
+		{Start: 6 * time.Minute, End: 6 * time.Minute, Lines: []string{"late"}},
+	}, cues)
+
+	_, _, err = Read(strings.NewReader(`<tt><body><p begin="soon" end="1s">x</p></body></tt>`))
+	assert.Error(t, err)
+	_, _, err = Read(strings.NewReader(`<tt><body><p begin="1s">x</p></body></tt>`))
+	assert.Error(t, err)
+}
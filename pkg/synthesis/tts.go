@@ -28,7 +28,9 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"time"
 
+	"github.com/bhojpur/speech/pkg/metrics"
 	"github.com/bhojpur/speech/pkg/utils/handlers"
 )
 
@@ -118,6 +120,8 @@ func (speech *Speech) downloadIfNotExists(fileName string, text string) error {
 	if err != nil {
 		urlString := fmt.Sprintf("http://translate.google.com/translate_tts?ie=UTF-8&total=1&idx=0&textlen=32&client=tw-ob&q=%s&tl=%s", url.QueryEscape(text), speech.Language)
 		fmt.Println(urlString)
+		start := time.Now()
+		defer metrics.Since(metrics.SynthesisSeconds.WithLabelValues("google"), start)
 		response, err := http.Get(urlString)
 		if err != nil {
 			return err
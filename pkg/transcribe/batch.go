@@ -0,0 +1,481 @@
+package transcribe
+
+// Copyright (c) 2018 Bhojpur Consulting Private Limited, India. All rights reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bhojpur/speech/pkg/asr"
+	"github.com/bhojpur/speech/pkg/audio"
+	"github.com/bhojpur/speech/pkg/subtitle"
+)
+
+// Job is a recording of a batch and the file its transcript is written to.
+type Job struct {
+	Input  string `json:"input"`
+	Output string `json:"output,omitempty"`
+}
+
+// Status of a manifest entry.
+type Status string
+
+const (
+	StatusDone   Status = "done"
+	StatusFailed Status = "failed"
+)
+
+// Entry is a line of the JSONL manifest of a batch.
+type Entry struct {
+	Input  string `json:"input"`
+	Output string `json:"output,omitempty"`
+	Status Status `json:"status"`
+	// Duration of the recording in seconds.
+	Duration float64 `json:"duration_seconds"`
+	// Elapsed is the processing time in seconds.
+	Elapsed float64 `json:"elapsed_seconds"`
+	// RealTimeFactor is the processing time over the duration.
+	RealTimeFactor float64 `json:"real_time_factor"`
+	Error          string  `json:"error,omitempty"`
+}
+
+// BatchOption configures a Batch.
+type BatchOption func(*Batch)
+
+// WithWorkers sets the number of recordings transcribed concurrently, each
+// with its own recognizer of the shared engine.
+func WithWorkers(n int) BatchOption {
+	return func(b *Batch) {
+		if n > 0 {
+			b.workers = n
+		}
+	}
+}
+
+// WithEncoding sets the encoding of all the recordings. By default it is
+// detected for every file, see DetectEncoding.
+func WithEncoding(encoding audio.Encoding) BatchOption {
+	return func(b *Batch) {
+		b.encoding = &encoding
+	}
+}
+
+// WithSampleRate sets the sample rate of the recordings with a raw
+// encoding.
+func WithSampleRate(rate int) BatchOption {
+	return func(b *Batch) {
+		b.config.SampleRate = rate
+	}
+}
+
+// WithAlternatives sets the maximum number of hypotheses per utterance.
+func WithAlternatives(n int) BatchOption {
+	return func(b *Batch) {
+		b.config.MaxAlternatives = n
+	}
+}
+
+// WithWords enables the word timings, which the subtitle formats imply.
+func WithWords() BatchOption {
+	return func(b *Batch) {
+		b.config.Words = true
+	}
+}
+
+// WithFormat sets the format of the transcripts, Text by default, and the
+// layout of the subtitles.
+func WithFormat(format Format, opts ...subtitle.Option) BatchOption {
+	return func(b *Batch) {
+		b.format = format
+		b.subtitles = opts
+	}
+}
+
+// WithProgress sets a function called with the entry of every recording
+// once it is written to the manifest. The calls do not overlap.
+func WithProgress(progress func(Entry)) BatchOption {
+	return func(b *Batch) {
+		b.progress = progress
+	}
+}
+
+// Batch transcribes many recordings with a shared engine.
+type Batch struct {
+	engine    asr.Engine
+	workers   int
+	encoding  *audio.Encoding
+	config    Config
+	format    Format
+	subtitles []subtitle.Option
+	progress  func(Entry)
+}
+
+// NewBatch creates a batch transcribing with the engine, one recording at a
+// time unless WithWorkers is given.
+func NewBatch(engine asr.Engine, opts ...BatchOption) *Batch {
+	b := &Batch{
+		engine:  engine,
+		workers: 1,
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	if b.format.NeedsWords() {
+		b.config.Words = true
+	}
+	return b
+}
+
+// Run transcribes the jobs and appends an entry per recording to the
+// manifest, in the order they complete. A recording which fails is
+// recorded as such and the batch goes on. When the context is done, the
+// recordings in progress are abandoned without entry and Run returns the
+// error of the context. Jobs sharing an output are refused before anything
+// is transcribed.
+func (b *Batch) Run(ctx context.Context, jobs []Job, manifest io.Writer) ([]Entry, error) {
+	if err := checkOutputs(jobs); err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var mu sync.Mutex
+	var entries []Entry
+	var werr error
+	queue := make(chan Job)
+	var wg sync.WaitGroup
+	for i := 0; i < b.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range queue {
+				entry, ok := b.run(ctx, job)
+				if !ok {
+					continue
+				}
+				mu.Lock()
+				if werr == nil {
+					werr = writeEntry(manifest, entry)
+					if werr != nil {
+						cancel()
+					}
+				}
+				entries = append(entries, entry)
+				if b.progress != nil {
+					b.progress(entry)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+feed:
+	for _, job := range jobs {
+		select {
+		case queue <- job:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(queue)
+	wg.Wait()
+
+	if werr != nil {
+		return entries, werr
+	}
+	return entries, ctx.Err()
+}
+
+// run transcribes a recording. It reports false when the context is done.
+func (b *Batch) run(ctx context.Context, job Job) (Entry, bool) {
+	if ctx.Err() != nil {
+		return Entry{}, false
+	}
+	entry := Entry{Input: job.Input, Output: job.Output}
+	start := time.Now()
+	t, err := b.transcribe(ctx, job.Input)
+	if err == nil {
+		err = b.write(job.Output, t)
+	}
+	if ctx.Err() != nil {
+		return Entry{}, false
+	}
+	entry.Elapsed = time.Since(start).Seconds()
+	if err != nil {
+		entry.Status = StatusFailed
+		entry.Error = err.Error()
+		return entry, true
+	}
+	entry.Status = StatusDone
+	entry.Duration = t.Duration.Seconds()
+	if entry.Duration > 0 {
+		entry.RealTimeFactor = entry.Elapsed / entry.Duration
+	}
+	return entry, true
+}
+
+func (b *Batch) transcribe(ctx context.Context, path string) (*Transcript, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	r := bufio.NewReader(&contextReader{ctx: ctx, r: f})
+
+	config := b.config
+	if b.encoding != nil {
+		config.Encoding = *b.encoding
+	} else {
+		header, _ := r.Peek(12)
+		if config.Encoding, err = DetectEncoding(path, header); err != nil {
+			return nil, err
+		}
+	}
+	switch config.Encoding {
+	case audio.Linear16, audio.Mulaw, audio.Alaw:
+	default:
+		// the other encodings carry their own sample rate
+		config.SampleRate = 0
+	}
+	return Transcribe(b.engine, r, config)
+}
+
+// write writes the transcript through a temporary file, so that an output
+// file is always complete.
+func (b *Batch) write(path string, t *Transcript) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if err := Write(f, t, b.format, b.subtitles...); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// contextReader stops reading once the context is done.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r *contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
+
+func writeEntry(w io.Writer, entry Entry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(line, '\n'))
+	return err
+}
+
+// ReadManifest reads the entries of a manifest. Lines which can not be
+// decoded, such as the last one of an interrupted run, are skipped.
+func ReadManifest(r io.Reader) ([]Entry, error) {
+	var entries []Entry
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil || entry.Input == "" {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+// Remaining returns the jobs without a done entry in the manifest, whose
+// output still exists, to resume an interrupted batch. The last entry of a
+// recording prevails.
+func Remaining(jobs []Job, entries []Entry) []Job {
+	done := map[string]bool{}
+	for _, entry := range entries {
+		done[entry.Input] = entry.Status == StatusDone
+	}
+	var remaining []Job
+	for _, job := range jobs {
+		if done[job.Input] {
+			if _, err := os.Stat(job.Output); err == nil {
+				continue
+			}
+		}
+		remaining = append(remaining, job)
+	}
+	return remaining
+}
+
+// Extension returns the file extension of the format.
+func (f Format) Extension() string {
+	if f == Text {
+		return ".txt"
+	}
+	return "." + f.String()
+}
+
+// audioExtensions are the extensions of the recordings found by Walk, along
+// with those of the header-less encodings.
+var audioExtensions = map[string]bool{
+	".wav":  true,
+	".wave": true,
+	".mp3":  true,
+	".aif":  true,
+	".aiff": true,
+	".aifc": true,
+}
+
+// Walk lists the recordings found under dir, in lexical order. The output of
+// a recording has the extension of the format and the same path relative to
+// outDir, or sits next to the recording when outDir is empty. Hidden files
+// and directories are skipped.
+func Walk(dir, outDir string, format Format) ([]Job, error) {
+	var jobs []Job
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path != dir && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		ext := strings.ToLower(filepath.Ext(path))
+		_, raw := extensions[ext]
+		if d.IsDir() || !(audioExtensions[ext] || raw) {
+			return nil
+		}
+		output := path
+		if outDir != "" {
+			rel, err := filepath.Rel(dir, path)
+			if err != nil {
+				return err
+			}
+			output = filepath.Join(outDir, rel)
+		}
+		jobs = append(jobs, Job{
+			Input:  path,
+			Output: strings.TrimSuffix(output, filepath.Ext(output)) + format.Extension(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return jobs, checkOutputs(jobs)
+}
+
+// ReadJobs reads a list of recordings, one per line: either a path or a
+// JSON Job. Relative paths are relative to the directory of the list. The
+// outputs which are not given are named as by Walk, with the path of the
+// recording relative to the list kept in outDir, or next to the recording
+// when outDir is empty. The recordings outside the directory of the list
+// keep their whole path in outDir. Blank lines and lines starting with #
+// are skipped.
+func ReadJobs(path, outDir string, format Format) ([]Job, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	base := filepath.Dir(path)
+	resolve := func(p string) string {
+		if p == "" || filepath.IsAbs(p) {
+			return p
+		}
+		return filepath.Join(base, p)
+	}
+
+	var jobs []Job
+	for n, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		var job Job
+		if strings.HasPrefix(line, "{") {
+			dec := json.NewDecoder(bytes.NewReader([]byte(line)))
+			dec.DisallowUnknownFields()
+			if err := dec.Decode(&job); err != nil || job.Input == "" {
+				return nil, fmt.Errorf("%s:%d: invalid job %q", path, n+1, line)
+			}
+		} else {
+			job.Input = line
+		}
+		job.Input, job.Output = resolve(job.Input), resolve(job.Output)
+		if job.Output == "" {
+			output := job.Input
+			if outDir != "" {
+				rel, err := filepath.Rel(base, job.Input)
+				if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+					rel = strings.TrimPrefix(job.Input, filepath.VolumeName(job.Input))
+				}
+				output = filepath.Join(outDir, rel)
+			}
+			job.Output = strings.TrimSuffix(output, filepath.Ext(output)) + format.Extension()
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, checkOutputs(jobs)
+}
+
+// ErrDuplicateOutput is returned when two jobs would write the same output,
+// such as x.wav and x.mp3 in the same directory.
+var ErrDuplicateOutput = errors.New("duplicate output")
+
+// checkOutputs returns an ErrDuplicateOutput naming the first recordings
+// sharing an output.
+func checkOutputs(jobs []Job) error {
+	inputs := make(map[string]string, len(jobs))
+	for _, job := range jobs {
+		output := filepath.Clean(job.Output)
+		if input, ok := inputs[output]; ok {
+			return fmt.Errorf("%w %s of %s and %s", ErrDuplicateOutput, output, input, job.Input)
+		}
+		inputs[output] = job.Input
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+package transcribe
+
+// Copyright (c) 2018 Bhojpur Consulting Private Limited, India. All rights reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recordings creates a directory of recordings and returns its path.
+func recordings(t *testing.T) string {
+	dir := t.TempDir()
+	bad := wavFile(16000, 1)
+	// switch the format tag to ADPCM
+	bad[20] = 2
+	files := map[string][]byte{
+		"a.wav":         wavFile(16000, 3),
+		"sub/b.WAV":     wavFile(8000, 3),
+		"sub/c.ulaw":    make([]byte, 8000*3),
+		"bad.wav":       bad,
+		"notes.txt":     []byte("not a recording"),
+		".hidden/d.wav": wavFile(16000, 1),
+	}
+	for name, data := range files {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.NoError(t, os.WriteFile(path, data, 0644))
+	}
+	return dir
+}
+
+func TestWalk(t *testing.T) {
+	dir := recordings(t)
+	jobs, err := Walk(dir, "/out", SRT)
+	assert.NoError(t, err)
+	assert.Equal(t, []Job{
+		{Input: filepath.Join(dir, "a.wav"), Output: "/out/a.srt"},
+		{Input: filepath.Join(dir, "bad.wav"), Output: "/out/bad.srt"},
+		{Input: filepath.Join(dir, "sub/b.WAV"), Output: "/out/sub/b.srt"},
+		{Input: filepath.Join(dir, "sub/c.ulaw"), Output: "/out/sub/c.srt"},
+	}, jobs)
+
+	jobs, err = Walk(dir, "", Text)
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "a.txt"), jobs[0].Output)
+
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "a.mp3"), nil, 0644))
+	_, err = Walk(dir, "/out", SRT)
+	assert.ErrorIs(t, err, ErrDuplicateOutput)
+}
+
+func TestBatch(t *testing.T) {
+	dir := recordings(t)
+	out := t.TempDir()
+	jobs, err := Walk(dir, out, SRT)
+	assert.NoError(t, err)
+
+	engine := newEngine()
+	var progress []string
+	batch := NewBatch(engine, WithWorkers(3), WithSampleRate(8000), WithFormat(SRT), WithProgress(func(e Entry) {
+		progress = append(progress, e.Input)
+	}))
+	var manifest bytes.Buffer
+	entries, err := batch.Run(context.Background(), jobs, &manifest)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 4)
+	assert.Len(t, progress, 4)
+	assert.Equal(t, 0, engine.Active())
+
+	read, err := ReadManifest(&manifest)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, entries, read)
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Input, "bad.wav") {
+			assert.Equal(t, StatusFailed, entry.Status)
+			assert.Contains(t, entry.Error, "unsupported audio format")
+			assert.NoFileExists(t, entry.Output)
+			continue
+		}
+		assert.Equal(t, StatusDone, entry.Status, entry.Error)
+		assert.InDelta(t, 3, entry.Duration, 0.01)
+		assert.Greater(t, entry.RealTimeFactor, 0.0)
+		data, err := os.ReadFile(entry.Output)
+		assert.NoError(t, err)
+		assert.Contains(t, string(data), "hello world good morning everyone")
+	}
+}
+
+func TestBatchResume(t *testing.T) {
+	dir := recordings(t)
+	jobs, err := Walk(dir, t.TempDir(), Text)
+	assert.NoError(t, err)
+
+	var manifest bytes.Buffer
+	_, err = NewBatch(newEngine()).Run(context.Background(), jobs[:2], &manifest)
+	assert.NoError(t, err)
+	// an entry cut by the interruption
+	manifest.WriteString(`{"input":"` + jobs[2].Input)
+
+	entries, err := ReadManifest(&manifest)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	remaining := Remaining(jobs, entries)
+	// the failed recording is retried
+	assert.Equal(t, []Job{jobs[1], jobs[2], jobs[3]}, remaining)
+
+	// as well as a done one whose output is gone
+	assert.NoError(t, os.Remove(jobs[0].Output))
+	assert.Equal(t, jobs, Remaining(jobs, entries))
+}
+
+func TestBatchDuplicateOutput(t *testing.T) {
+	jobs := []Job{
+		{Input: "a/rec001.wav", Output: "out/rec001.txt"},
+		{Input: "b/rec001.wav", Output: "out/./rec001.txt"},
+	}
+	var manifest bytes.Buffer
+	_, err := NewBatch(newEngine()).Run(context.Background(), jobs, &manifest)
+	assert.ErrorIs(t, err, ErrDuplicateOutput)
+	assert.Zero(t, manifest.Len())
+}
+
+func TestBatchCancel(t *testing.T) {
+	jobs, err := Walk(recordings(t), t.TempDir(), Text)
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var manifest bytes.Buffer
+	entries, err := NewBatch(newEngine()).Run(ctx, jobs, &manifest)
+	assert.Equal(t, context.Canceled, err)
+	assert.Empty(t, entries)
+	assert.Zero(t, manifest.Len())
+}
+
+func TestReadJobs(t *testing.T) {
+	dir := t.TempDir()
+	list := filepath.Join(dir, "list.txt")
+	assert.NoError(t, os.WriteFile(list, []byte(`# clinic recordings
+a.wav
+
+/data/b.mp3
+{"input": "c.aiff", "output": "texts/c.json"}
+`), 0644))
+
+	jobs, err := ReadJobs(list, "", JSON)
+	assert.NoError(t, err)
+	assert.Equal(t, []Job{
+		{Input: filepath.Join(dir, "a.wav"), Output: filepath.Join(dir, "a.json")},
+		{Input: "/data/b.mp3", Output: "/data/b.json"},
+		{Input: filepath.Join(dir, "c.aiff"), Output: filepath.Join(dir, "texts/c.json")},
+	}, jobs)
+
+	jobs, err = ReadJobs(list, "/out", JSON)
+	assert.NoError(t, err)
+	assert.Equal(t, "/out/a.json", jobs[0].Output)
+	assert.Equal(t, "/out/data/b.json", jobs[1].Output)
+
+	assert.NoError(t, os.WriteFile(list, []byte("a/rec001.wav\nb/rec001.wav\n"), 0644))
+	jobs, err = ReadJobs(list, "/out", JSON)
+	assert.NoError(t, err)
+	assert.Equal(t, "/out/a/rec001.json", jobs[0].Output)
+	assert.Equal(t, "/out/b/rec001.json", jobs[1].Output)
+
+	assert.NoError(t, os.WriteFile(list, []byte(`{"input": "a.wav", "output": "out.json"}
+{"input": "b.wav", "output": "out.json"}
+`), 0644))
+	_, err = ReadJobs(list, "", JSON)
+	assert.ErrorIs(t, err, ErrDuplicateOutput)
+
+	assert.NoError(t, os.WriteFile(list, []byte(`{"path": "a.wav"}`), 0644))
+	_, err = ReadJobs(list, "", JSON)
+	assert.Error(t, err)
+}
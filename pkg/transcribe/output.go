@@ -0,0 +1,171 @@
+package transcribe
+
+// Copyright (c) 2018 Bhojpur Consulting Private Limited, India. All rights reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/bhojpur/speech/pkg/asr"
+	"github.com/bhojpur/speech/pkg/subtitle"
+)
+
+// Format of a written transcript.
+type Format int
+
+const (
+	Text   Format = iota // best hypothesis of each utterance, one per line
+	JSON                 // all hypotheses with their word timings
+	SRT                  // SubRip subtitles
+	WebVTT               // WebVTT subtitles
+	TTML                 // TTML subtitles
+)
+
+// String implements the stringer interface.
+func (f Format) String() string {
+	switch f {
+	case Text:
+		return "text"
+	case JSON:
+		return "json"
+	case SRT:
+		return "srt"
+	case WebVTT:
+		return "vtt"
+	case TTML:
+		return "ttml"
+	default:
+		return fmt.Sprintf("Format(%d)", int(f))
+	}
+}
+
+// ParseFormat returns the format named s, as returned by String.
+func ParseFormat(s string) (Format, error) {
+	for _, f := range []Format{Text, JSON, SRT, WebVTT, TTML} {
+		if strings.EqualFold(s, f.String()) {
+			return f, nil
+		}
+	}
+	if strings.EqualFold(s, "webvtt") {
+		return WebVTT, nil
+	}
+	return 0, fmt.Errorf("unknown transcript format %q", s)
+}
+
+// NeedsWords reports whether the format is built from the word timings.
+func (f Format) NeedsWords() bool {
+	return f == SRT || f == WebVTT || f == TTML
+}
+
+// ErrNoTimings is returned when subtitles are written from a transcript
+// without word timings.
+var ErrNoTimings = errors.New("subtitles require word timings")
+
+// Write writes the transcript to w in the format. The options lay out the
+// subtitles.
+func Write(w io.Writer, t *Transcript, format Format, opts ...subtitle.Option) error {
+	switch format {
+	case Text:
+		_, err := io.WriteString(w, t.Text())
+		return err
+	case JSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(jsonTranscriptOf(t))
+	case SRT, WebVTT, TTML:
+		cues, err := Cues(t, opts...)
+		if err != nil {
+			return err
+		}
+		return subtitle.Write(w, cues, subtitleFormats[format])
+	default:
+		return fmt.Errorf("unknown transcript format %v", format)
+	}
+}
+
+// subtitleFormats maps the subtitle formats.
+var subtitleFormats = map[Format]subtitle.Format{
+	SRT:    subtitle.SRT,
+	WebVTT: subtitle.WebVTT,
+	TTML:   subtitle.TTML,
+}
+
+// jsonTranscript is the JSON form of a transcript, with times in seconds.
+type jsonTranscript struct {
+	Duration float64      `json:"duration"`
+	Results  []jsonResult `json:"results"`
+}
+
+type jsonResult struct {
+	Alternatives []jsonAlternative `json:"alternatives"`
+}
+
+type jsonAlternative struct {
+	Text       string     `json:"text"`
+	Confidence float64    `json:"confidence"`
+	Words      []jsonWord `json:"words,omitempty"`
+}
+
+type jsonWord struct {
+	Word       string  `json:"word"`
+	Start      float64 `json:"start"`
+	End        float64 `json:"end"`
+	Confidence float64 `json:"confidence"`
+}
+
+func jsonTranscriptOf(t *Transcript) *jsonTranscript {
+	out := &jsonTranscript{
+		Duration: t.Duration.Seconds(),
+		Results:  []jsonResult{},
+	}
+	for _, res := range t.Results {
+		var r jsonResult
+		for _, alt := range res.Alternatives {
+			a := jsonAlternative{Text: alt.Text, Confidence: alt.Confidence}
+			for _, w := range alt.Words {
+				a.Words = append(a.Words, jsonWord{
+					Word:       w.Word,
+					Start:      w.Start.Seconds(),
+					End:        w.End.Seconds(),
+					Confidence: w.Confidence,
+				})
+			}
+			r.Alternatives = append(r.Alternatives, a)
+		}
+		out.Results = append(out.Results, r)
+	}
+	return out
+}
+
+// Cues lays out the best hypotheses of the utterances into subtitles.
+func Cues(t *Transcript, opts ...subtitle.Option) ([]subtitle.Cue, error) {
+	var words []asr.Word
+	for _, res := range t.Results {
+		if len(res.Alternatives[0].Words) == 0 {
+			return nil, ErrNoTimings
+		}
+		words = append(words, res.Alternatives[0].Words...)
+	}
+	return subtitle.Build(words, opts...), nil
+}
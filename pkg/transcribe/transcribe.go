@@ -0,0 +1,172 @@
+package transcribe
+
+// Copyright (c) 2018 Bhojpur Consulting Private Limited, India. All rights reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package transcribe turns recordings in any of the supported audio formats
+// into transcripts, and writes the transcripts as plain text, JSON or
+// subtitles.
+
+import (
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/bhojpur/speech/pkg/asr"
+	"github.com/bhojpur/speech/pkg/audio"
+)
+
+// chunkSize is the number of bytes of input decoded at once.
+const chunkSize = 32 << 10
+
+// Config of a transcription.
+type Config struct {
+	// Encoding of the input, see DetectEncoding.
+	Encoding audio.Encoding
+	// SampleRate of the input, required for the raw encodings. For the
+	// other encodings it is checked against the header when non-zero.
+	SampleRate int
+	// MaxAlternatives is the maximum number of hypotheses per utterance.
+	MaxAlternatives int
+	// Words enables the word timings.
+	Words bool
+}
+
+// Transcript is the recognized text of a recording.
+type Transcript struct {
+	// Duration of the recording.
+	Duration time.Duration
+	// Results holds the utterances which were recognized, in order. Each
+	// has at least one alternative.
+	Results []*asr.Result
+}
+
+// Text returns the best hypotheses of the utterances, one per line.
+func (t *Transcript) Text() string {
+	var b strings.Builder
+	for _, res := range t.Results {
+		b.WriteString(res.Alternatives[0].Text)
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// Transcribe recognizes the audio read from r with a new recognizer of the
+// engine. The audio is resampled to the sample rate of the engine.
+func Transcribe(engine asr.Engine, r io.Reader, config Config) (*Transcript, error) {
+	rate := int(engine.SampleRate())
+	dec, err := audio.NewDecoder(config.Encoding, config.SampleRate, rate)
+	if err != nil {
+		return nil, err
+	}
+	rec, err := engine.NewRecognizer(asr.Config{
+		SampleRate:      engine.SampleRate(),
+		MaxAlternatives: config.MaxAlternatives,
+		Words:           config.Words,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer rec.Close()
+
+	t := &Transcript{}
+	var samples int64
+	accept := func(pcm []byte) error {
+		if len(pcm) == 0 {
+			return nil
+		}
+		samples += int64(len(pcm) / 2)
+		res, err := asr.Accept(rec, pcm, false)
+		if err != nil || res == nil {
+			return err
+		}
+		t.Results = append(t.Results, res)
+		return nil
+	}
+
+	buf := make([]byte, chunkSize)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			pcm, derr := dec.Write(buf[:n])
+			if derr != nil {
+				dec.Close()
+				return nil, derr
+			}
+			if err := accept(pcm); err != nil {
+				return nil, err
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			dec.Close()
+			return nil, err
+		}
+	}
+	pcm, err := dec.Close()
+	if err != nil {
+		return nil, err
+	}
+	if err := accept(pcm); err != nil {
+		return nil, err
+	}
+
+	// the recognizer may hold several utterances once flushed
+	results, err := asr.Drain(rec)
+	if err != nil {
+		return nil, err
+	}
+	t.Results = append(t.Results, results...)
+	t.Duration = time.Duration(float64(samples) / float64(rate) * float64(time.Second))
+	return t, nil
+}
+
+// ErrUnknownEncoding is returned by DetectEncoding for header-less input
+// without a known file extension.
+var ErrUnknownEncoding = errors.New("unknown audio encoding")
+
+// extensions maps the file extensions of the header-less encodings.
+var extensions = map[string]audio.Encoding{
+	".raw":   audio.Linear16,
+	".pcm":   audio.Linear16,
+	".s16":   audio.Linear16,
+	".ulaw":  audio.Mulaw,
+	".mulaw": audio.Mulaw,
+	".ul":    audio.Mulaw,
+	".alaw":  audio.Alaw,
+	".al":    audio.Alaw,
+}
+
+// DetectEncoding returns the encoding of a file from its name for the
+// header-less encodings, or else from its first bytes. The extension comes
+// first since raw samples may look like an MP3 frame sync.
+func DetectEncoding(name string, header []byte) (audio.Encoding, error) {
+	if encoding, ok := extensions[strings.ToLower(filepath.Ext(name))]; ok {
+		return encoding, nil
+	}
+	if encoding, ok := audio.Detect(header); ok {
+		return encoding, nil
+	}
+	return 0, ErrUnknownEncoding
+}
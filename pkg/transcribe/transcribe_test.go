@@ -0,0 +1,165 @@
+package transcribe
+
+// Copyright (c) 2018 Bhojpur Consulting Private Limited, India. All rights reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bhojpur/speech/pkg/asr/fake"
+	"github.com/bhojpur/speech/pkg/audio"
+	"github.com/bhojpur/speech/pkg/subtitle"
+	"github.com/bhojpur/speech/pkg/wave"
+	"github.com/stretchr/testify/assert"
+)
+
+func newEngine() *fake.Engine {
+	return fake.NewEngine(16000,
+		fake.Utterance{Text: "hello world", Seconds: 1, Alternatives: []string{"yellow world"}},
+		fake.Utterance{Text: "good morning everyone", Seconds: 1.5},
+	)
+}
+
+// wavFile builds a WAV file of silence.
+func wavFile(sampleRate int, seconds float64) []byte {
+	n := int(float64(sampleRate) * seconds)
+	var buf bytes.Buffer
+	wave.NewWriter(&buf, uint32(n), 1, uint32(sampleRate), 16).Write(make([]byte, n*2))
+	return buf.Bytes()
+}
+
+func TestTranscribe(t *testing.T) {
+	engine := newEngine()
+	tr, err := Transcribe(engine, bytes.NewReader(wavFile(8000, 3)), Config{Encoding: audio.Wav})
+	assert.NoError(t, err)
+	assert.Equal(t, "hello world\ngood morning everyone\n", tr.Text())
+	assert.InDelta(t, 3, tr.Duration.Seconds(), 0.01)
+	assert.Len(t, tr.Results[0].Alternatives, 1)
+	assert.Empty(t, tr.Results[0].Alternatives[0].Words)
+	assert.Equal(t, 0, engine.Active())
+}
+
+func TestTranscribeRaw(t *testing.T) {
+	_, err := Transcribe(newEngine(), bytes.NewReader(make([]byte, 100)), Config{Encoding: audio.Mulaw})
+	assert.Error(t, err)
+
+	tr, err := Transcribe(newEngine(), bytes.NewReader(make([]byte, 4000)), Config{Encoding: audio.Mulaw, SampleRate: 8000})
+	assert.NoError(t, err)
+	// the final result holds the words heard so far
+	assert.Equal(t, "hello\n", tr.Text())
+}
+
+func TestWriteJSON(t *testing.T) {
+	tr, err := Transcribe(newEngine(), bytes.NewReader(wavFile(16000, 1)), Config{
+		Encoding:        audio.Wav,
+		MaxAlternatives: 2,
+		Words:           true,
+	})
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	assert.NoError(t, Write(&buf, tr, JSON))
+	var out jsonTranscript
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &out))
+	assert.Equal(t, 1.0, out.Duration)
+	assert.Len(t, out.Results, 1)
+	alts := out.Results[0].Alternatives
+	assert.Len(t, alts, 2)
+	assert.Equal(t, "yellow world", alts[1].Text)
+	assert.Equal(t, jsonWord{Word: "world", Start: 0.5, End: 1, Confidence: 1}, alts[0].Words[1])
+}
+
+func TestWriteSubtitles(t *testing.T) {
+	tr, err := Transcribe(newEngine(), bytes.NewReader(wavFile(16000, 3)), Config{Encoding: audio.Wav, Words: true})
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	assert.NoError(t, Write(&buf, tr, SRT))
+	assert.Equal(t, "1\n00:00:00,000 --> 00:00:02,500\nhello world good morning everyone\n\n", buf.String())
+
+	buf.Reset()
+	assert.NoError(t, Write(&buf, tr, WebVTT, subtitle.WithMaxLineLength(12)))
+	assert.Equal(t, "WEBVTT\n\n00:00:00.000 --> 00:00:02.000\nhello world\ngood morning\n\n"+
+		"00:00:02.000 --> 00:00:03.000\neveryone\n\n", buf.String())
+
+	buf.Reset()
+	assert.NoError(t, Write(&buf, tr, TTML))
+	cues, format, err := subtitle.Read(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, subtitle.TTML, format)
+	assert.Equal(t, []subtitle.Cue{{Start: 0, End: 2500 * time.Millisecond, Lines: []string{"hello world good morning everyone"}}}, cues)
+
+	tr.Results[0].Alternatives[0].Words = nil
+	assert.Equal(t, ErrNoTimings, Write(&buf, tr, SRT))
+}
+
+func TestSubtitleLines(t *testing.T) {
+	text := strings.Repeat("lorem ipsum dolor sit amet ", 4)
+	engine := fake.NewEngine(16000, fake.Utterance{Text: text, Seconds: 20})
+	tr, err := Transcribe(engine, bytes.NewReader(wavFile(16000, 20)), Config{Encoding: audio.Wav, Words: true})
+	assert.NoError(t, err)
+
+	cues, err := Cues(tr)
+	assert.NoError(t, err)
+	assert.Len(t, cues, 3)
+	for _, c := range cues {
+		assert.LessOrEqual(t, len(c.Lines), subtitle.DefaultMaxLines)
+		for _, line := range c.Lines {
+			assert.LessOrEqual(t, len(line), subtitle.DefaultMaxLineLength)
+		}
+		assert.LessOrEqual(t, c.End-c.Start, subtitle.DefaultMaxDuration)
+	}
+	assert.Equal(t, time.Duration(0), cues[0].Start)
+	assert.Equal(t, 20*time.Second, cues[2].End)
+}
+
+func TestDetectEncoding(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		header   []byte
+		encoding audio.Encoding
+	}{
+		{"a.wav", wavFile(16000, 0), audio.Wav},
+		{"a.bin", wavFile(16000, 0), audio.Wav},
+		{"a.RAW", []byte{0xff, 0xfb}, audio.Linear16},
+		{"a.ulaw", nil, audio.Mulaw},
+		{"a.alaw", nil, audio.Alaw},
+	} {
+		encoding, err := DetectEncoding(tc.name, tc.header)
+		assert.NoError(t, err)
+		assert.Equal(t, tc.encoding, encoding, tc.name)
+	}
+	_, err := DetectEncoding("a.bin", []byte{1, 2, 3})
+	assert.Equal(t, ErrUnknownEncoding, err)
+}
+
+func TestParseFormat(t *testing.T) {
+	for _, f := range []Format{Text, JSON, SRT, WebVTT, TTML} {
+		parsed, err := ParseFormat(f.String())
+		assert.NoError(t, err)
+		assert.Equal(t, f, parsed)
+	}
+	_, err := ParseFormat("sbv")
+	assert.Error(t, err)
+}
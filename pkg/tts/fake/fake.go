@@ -0,0 +1,118 @@
+package fake
+
+// Copyright (c) 2018 Bhojpur Consulting Private Limited, India. All rights reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// It implements a fake speech synthesis engine, which speaks every word as
+// a tone lasting as long as the word would at the speaking rate. It allows
+// services and tests to run without the native synthesis libraries.
+
+import (
+	"math"
+	"strings"
+
+	"github.com/bhojpur/speech/pkg/tts"
+)
+
+func init() {
+	tts.Register("fake", Open)
+}
+
+// SampleRate is the sample rate of the synthesized audio.
+const SampleRate = 16000
+
+// chunk is the number of samples passed at once to the callback.
+const chunk = SampleRate / 10
+
+// Voices are the voices of the engine.
+var Voices = []tts.Voice{
+	{Name: "english-us", Languages: []string{"en-us", "en"}, Identifier: "en-us", Gender: tts.Male},
+	{Name: "english-us-f", Languages: []string{"en-us", "en"}, Identifier: "en-us+f", Gender: tts.Female},
+	{Name: "hindi", Languages: []string{"hi"}, Identifier: "hi", Gender: tts.Male},
+}
+
+// Engine synthesizes tones.
+type Engine struct{}
+
+// Open creates an engine, the path is ignored.
+func Open(path string) (tts.Engine, error) {
+	return &Engine{}, nil
+}
+
+// Name implements tts.Engine.
+func (e *Engine) Name() string {
+	return "fake"
+}
+
+// SampleRate implements tts.Engine.
+func (e *Engine) SampleRate() int {
+	return SampleRate
+}
+
+// Voices implements tts.Engine.
+func (e *Engine) Voices(spec tts.VoiceSpec) ([]tts.Voice, error) {
+	return tts.Select(Voices, spec), nil
+}
+
+// Synthesize implements tts.Engine. Every word is a tone at a frequency
+// rising with the pitch, followed by the word gap.
+func (e *Engine) Synthesize(text string, spec tts.VoiceSpec, params tts.Parameters, fn func(samples []int16) error) (tts.Voice, error) {
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return tts.Voice{}, tts.ErrEmptyText
+	}
+	voice, err := tts.Choose(Voices, spec)
+	if err != nil {
+		return tts.Voice{}, err
+	}
+	if params.Rate <= 0 {
+		params.Rate = tts.DefaultParameters.Rate
+	}
+
+	word := SampleRate * 60 / params.Rate
+	gap := SampleRate * params.WordGap / 100
+	frequency := 100 + 4*float64(params.Pitch)
+	amplitude := 0.3 * math.MaxInt16 * float64(params.Volume) / 100
+
+	var samples []int16
+	for range words {
+		for i := 0; i < word; i++ {
+			samples = append(samples, int16(amplitude*math.Sin(2*math.Pi*frequency*float64(i)/SampleRate)))
+		}
+		samples = append(samples, make([]int16, gap)...)
+		for len(samples) >= chunk {
+			if err := fn(samples[:chunk]); err != nil {
+				return tts.Voice{}, err
+			}
+			samples = samples[chunk:]
+		}
+	}
+	if len(samples) > 0 {
+		if err := fn(samples); err != nil {
+			return tts.Voice{}, err
+		}
+	}
+	return voice, nil
+}
+
+// Close implements tts.Engine.
+func (e *Engine) Close() error {
+	return nil
+}
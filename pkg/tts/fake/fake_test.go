@@ -0,0 +1,75 @@
+package fake
+
+// Copyright (c) 2018 Bhojpur Consulting Private Limited, India. All rights reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bhojpur/speech/pkg/tts"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSynthesize(t *testing.T) {
+	engine, err := tts.Open("fake", "")
+	require.NoError(t, err)
+	defer engine.Close()
+
+	var chunks, samples int
+	params := tts.DefaultParameters
+	params.Rate = 120
+	voice, err := engine.Synthesize("hello world", tts.VoiceSpec{Language: "en", Gender: tts.Female}, params, func(s []int16) error {
+		chunks++
+		samples += len(s)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "english-us-f", voice.Name)
+	assert.Equal(t, SampleRate, samples)
+	assert.Equal(t, 10, chunks)
+
+	stop := errors.New("stop")
+	_, err = engine.Synthesize("hello", tts.VoiceSpec{}, params, func([]int16) error { return stop })
+	assert.Equal(t, stop, err)
+
+	_, err = engine.Synthesize(" ", tts.VoiceSpec{}, params, nil)
+	assert.Equal(t, tts.ErrEmptyText, err)
+	_, err = engine.Synthesize("hello", tts.VoiceSpec{Language: "fr"}, params, nil)
+	assert.Equal(t, tts.ErrNoVoice, err)
+}
+
+func TestVoices(t *testing.T) {
+	engine := &Engine{}
+	voices, err := engine.Voices(tts.VoiceSpec{Language: "hi"})
+	require.NoError(t, err)
+	require.Len(t, voices, 1)
+	assert.Equal(t, "hindi", voices[0].Name)
+
+	voices, err = engine.Voices(tts.VoiceSpec{Name: "en-us+f"})
+	require.NoError(t, err)
+	require.Len(t, voices, 1)
+	assert.Equal(t, "english-us-f", voices[0].Name)
+
+	voices, err = engine.Voices(tts.VoiceSpec{})
+	require.NoError(t, err)
+	assert.Len(t, voices, len(Voices))
+}
@@ -0,0 +1,247 @@
+package tts
+
+// Copyright (c) 2018 Bhojpur Consulting Private Limited, India. All rights reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// It defines a common abstraction over the speech synthesis engines, so
+// that services can be written once and run on top of eSpeak or a fake
+// engine used in tests.
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Gender of a voice.
+type Gender int
+
+const (
+	// Unspecified or none.
+	Unspecified Gender = iota
+	// Male voice.
+	Male
+	// Female voice.
+	Female
+)
+
+// Voice describes a voice of an engine.
+type Voice struct {
+	// Name selects the voice.
+	Name string
+	// Languages spoken by the voice, in order of preference.
+	Languages []string
+	// Identifier of the voice in the engine.
+	Identifier string
+	Gender     Gender
+	// Age in years, 0 if unknown.
+	Age int
+}
+
+// VoiceSpec selects a voice by name, or else the voice matching best the
+// language, gender and age. Variant selects the n-th best matching voice.
+type VoiceSpec struct {
+	Name     string
+	Language string
+	Gender   Gender
+	Age      int
+	Variant  int
+}
+
+// Parameters modulate the voice.
+type Parameters struct {
+	// Rate is the speaking rate in words per minute.
+	Rate int
+	// Pitch is the base pitch, from 0 to 100.
+	Pitch int
+	// Volume from 0 (silence) to 200, 100 being the normal volume.
+	Volume int
+	// Range is the pitch range, from 0 (monotone) to 100.
+	Range int
+	// WordGap is the pause between words in units of 10 ms.
+	WordGap int
+	// SSML reads the text as SSML.
+	SSML bool
+}
+
+// DefaultParameters are the parameters of a normal voice.
+var DefaultParameters = Parameters{
+	Rate:   175,
+	Pitch:  50,
+	Volume: 100,
+	Range:  50,
+}
+
+// Engine is a speech synthesizer.
+type Engine interface {
+	// Name returns the name the engine was registered with.
+	Name() string
+	// SampleRate returns the sample rate of the synthesized audio.
+	SampleRate() int
+	// Voices lists the voices matching the specification, best first. The
+	// empty specification lists all the voices.
+	Voices(spec VoiceSpec) ([]Voice, error)
+	// Synthesize synthesizes text with the voice of the specification. The
+	// 16-bit mono samples are passed to fn as soon as they are produced; an
+	// error returned by fn stops the synthesis and is returned. It returns
+	// the voice used.
+	Synthesize(text string, spec VoiceSpec, params Parameters, fn func(samples []int16) error) (Voice, error)
+	// Close releases the engine.
+	Close() error
+}
+
+// OpenFunc opens the engine with the data found at path, the empty path
+// selecting the engine's default data.
+type OpenFunc func(path string) (Engine, error)
+
+// ErrUnknownEngine is returned by Open for engines never registered.
+var ErrUnknownEngine = errors.New("unknown speech synthesis engine")
+
+// ErrNoVoice is returned when no voice matches a specification.
+var ErrNoVoice = errors.New("no matching voice")
+
+// ErrEmptyText is returned when synthesizing an empty text.
+var ErrEmptyText = errors.New("text is empty")
+
+var (
+	enginesMu sync.RWMutex
+	engines   = map[string]OpenFunc{}
+)
+
+// Register makes an engine available by name. It is meant to be called from
+// the init function of the package implementing the engine.
+func Register(name string, open OpenFunc) {
+	enginesMu.Lock()
+	defer enginesMu.Unlock()
+	if open == nil {
+		panic("tts: Register open function is nil")
+	}
+	if _, dup := engines[name]; dup {
+		panic("tts: Register called twice for engine " + name)
+	}
+	engines[name] = open
+}
+
+// Engines returns the sorted names of the registered engines.
+func Engines() []string {
+	enginesMu.RLock()
+	defer enginesMu.RUnlock()
+	names := make([]string, 0, len(engines))
+	for name := range engines {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Open opens the named engine with the data at path.
+func Open(name, path string) (Engine, error) {
+	enginesMu.RLock()
+	open, ok := engines[name]
+	enginesMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownEngine, name)
+	}
+	return open(path)
+}
+
+// Select returns the voices matching the specification, best first: the
+// voice of the name, or else the voices speaking the language, those of the
+// gender and closest in age first. It is meant for engines without their
+// own voice selection.
+func Select(voices []Voice, spec VoiceSpec) []Voice {
+	if spec.Name != "" {
+		for _, v := range voices {
+			if v.Name == spec.Name || v.Identifier == spec.Name {
+				return []Voice{v}
+			}
+		}
+		return nil
+	}
+
+	type candidate struct {
+		voice Voice
+		score int
+	}
+	var candidates []candidate
+	for _, v := range voices {
+		rank := languageRank(v, spec.Language)
+		if rank < 0 {
+			continue
+		}
+		score := -rank * 1000
+		if spec.Gender != Unspecified && v.Gender == spec.Gender {
+			score += 500
+		}
+		if spec.Age > 0 && v.Age > 0 {
+			score -= abs(spec.Age - v.Age)
+		}
+		candidates = append(candidates, candidate{v, score})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].score > candidates[j].score })
+	selected := make([]Voice, len(candidates))
+	for i, c := range candidates {
+		selected[i] = c.voice
+	}
+	return selected
+}
+
+// Choose returns the voice of the specification among the voices, the
+// Variant-th best match.
+func Choose(voices []Voice, spec VoiceSpec) (Voice, error) {
+	selected := Select(voices, spec)
+	if spec.Variant < 0 || spec.Variant >= len(selected) {
+		return Voice{}, ErrNoVoice
+	}
+	return selected[spec.Variant], nil
+}
+
+// languageRank returns the position of the language among the languages of
+// the voice, or -1 when the voice does not speak it. A language matches
+// its regional variants, "en" matching "en-us".
+func languageRank(v Voice, language string) int {
+	if language == "" {
+		return 0
+	}
+	for i, l := range v.Languages {
+		if equalLanguage(l, language) {
+			return i
+		}
+	}
+	return -1
+}
+
+func equalLanguage(voice, language string) bool {
+	voice, language = normalizeLanguage(voice), normalizeLanguage(language)
+	return voice == language || strings.HasPrefix(voice, language+"-")
+}
+
+func normalizeLanguage(code string) string {
+	return strings.ToLower(strings.ReplaceAll(code, "_", "-"))
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,386 @@
+package vad
+
+// Copyright (c) 2018 Bhojpur Consulting Private Limited, India. All rights reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"encoding/binary"
+	"math"
+	"math/cmplx"
+	"time"
+)
+
+const (
+	// DefaultFrameDuration is the duration of the analysed frames.
+	DefaultFrameDuration = 20 * time.Millisecond
+	// DefaultMinSpeech is how long speech must last to start a segment.
+	DefaultMinSpeech = 100 * time.Millisecond
+	// DefaultMinSilence is how long silence must last to end a segment.
+	DefaultMinSilence = 500 * time.Millisecond
+	// DefaultHangover is the silence kept at the end of a segment.
+	DefaultHangover = 200 * time.Millisecond
+	// DefaultEnergyThreshold is how far above the noise floor, in dB, the
+	// energy of a speech frame is.
+	DefaultEnergyThreshold = 12.0
+	// DefaultMaxFlatness is the spectral flatness above which a frame sounds
+	// like noise rather than voice.
+	DefaultMaxFlatness = 0.4
+	// DefaultMaxZeroCrossingRate is the rate of zero crossings per sample
+	// above which a frame sounds like noise rather than voice.
+	DefaultMaxZeroCrossingRate = 0.35
+)
+
+// minEnergy is the energy, in dBFS, below which frames are silent whatever
+// the noise floor. The noise floor starts from it, so that streams starting
+// with speech are heard.
+const minEnergy = -55.0
+
+// EventType is the kind of an Event.
+type EventType int
+
+const (
+	// SpeechStart marks the start of a speech segment.
+	SpeechStart EventType = iota + 1
+	// SpeechEnd marks the end of a speech segment.
+	SpeechEnd
+)
+
+func (t EventType) String() string {
+	switch t {
+	case SpeechStart:
+		return "speech start"
+	case SpeechEnd:
+		return "speech end"
+	}
+	return "unknown"
+}
+
+// Event is a change of the voice activity.
+type Event struct {
+	Type EventType
+	// Offset is the position of the event from the start of the stream.
+	Offset time.Duration
+}
+
+// Option configures a Detector.
+type Option func(*Detector)
+
+// WithFrameDuration sets the duration of the analysed frames.
+func WithFrameDuration(d time.Duration) Option {
+	return func(v *Detector) {
+		v.frameDuration = d
+	}
+}
+
+// WithMinSpeech sets how long speech must last to start a segment. Shorter
+// noises, such as clicks, are ignored.
+func WithMinSpeech(d time.Duration) Option {
+	return func(v *Detector) {
+		v.minSpeech = d
+	}
+}
+
+// WithMinSilence sets how long silence must last to end a segment. Shorter
+// pauses are part of the speech.
+func WithMinSilence(d time.Duration) Option {
+	return func(v *Detector) {
+		v.minSilence = d
+	}
+}
+
+// WithHangover sets the silence kept at the end of a segment, so that the
+// fading end of the last word is not cut.
+func WithHangover(d time.Duration) Option {
+	return func(v *Detector) {
+		v.hangover = d
+	}
+}
+
+// WithEnergyThreshold sets how far above the noise floor, in dB, the energy
+// of a speech frame is.
+func WithEnergyThreshold(db float64) Option {
+	return func(v *Detector) {
+		v.energyThreshold = db
+	}
+}
+
+// WithMaxFlatness sets the spectral flatness, between 0 for a pure tone and
+// 1 for white noise, above which a frame is not voiced.
+func WithMaxFlatness(flatness float64) Option {
+	return func(v *Detector) {
+		v.maxFlatness = flatness
+	}
+}
+
+// WithMaxZeroCrossingRate sets the rate of zero crossings per sample above
+// which a frame is not voiced.
+func WithMaxZeroCrossingRate(rate float64) Option {
+	return func(v *Detector) {
+		v.maxZeroCrossingRate = rate
+	}
+}
+
+// Detector detects speech in a stream of mono 16-bit PCM. Frames are speech
+// when their energy rises above the noise floor and either their spectrum is
+// not flat or they do not cross zero too often, which tells voice from
+// noise. A segment starts after a minimum duration of speech frames and ends
+// after a minimum duration of silence. A Detector is not safe for concurrent
+// use.
+type Detector struct {
+	sampleRate          int
+	frameDuration       time.Duration
+	minSpeech           time.Duration
+	minSilence          time.Duration
+	hangover            time.Duration
+	energyThreshold     float64
+	maxFlatness         float64
+	maxZeroCrossingRate float64
+
+	frameSize int
+	window    []float64
+	spectrum  []complex128
+	frame     []float64 // samples of the incomplete frame
+	odd       []byte    // first byte of a sample split between writes
+
+	frames     int64   // frames analysed
+	noise      float64 // noise floor in dBFS
+	speaking   bool
+	run        int   // speech frames in a row while silent
+	quiet      int   // silent frames in a row while speaking
+	lastSpeech int64 // last speech frame
+}
+
+// New creates a detector for audio at the given sample rate.
+func New(sampleRate int, opts ...Option) *Detector {
+	v := &Detector{
+		sampleRate:          sampleRate,
+		frameDuration:       DefaultFrameDuration,
+		minSpeech:           DefaultMinSpeech,
+		minSilence:          DefaultMinSilence,
+		hangover:            DefaultHangover,
+		energyThreshold:     DefaultEnergyThreshold,
+		maxFlatness:         DefaultMaxFlatness,
+		maxZeroCrossingRate: DefaultMaxZeroCrossingRate,
+		noise:               minEnergy,
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+
+	v.frameSize = int(int64(sampleRate) * int64(v.frameDuration) / int64(time.Second))
+	if v.frameSize < 2 {
+		v.frameSize = 2
+	}
+	v.window = make([]float64, v.frameSize)
+	for i := range v.window {
+		v.window[i] = 0.5 - 0.5*math.Cos(2*math.Pi*float64(i)/float64(v.frameSize-1))
+	}
+	size := 1
+	for size < v.frameSize {
+		size <<= 1
+	}
+	v.spectrum = make([]complex128, size)
+	v.frame = make([]float64, 0, v.frameSize)
+	return v
+}
+
+// Speaking reports whether a speech segment is in progress.
+func (v *Detector) Speaking() bool {
+	return v.speaking
+}
+
+// Write analyses samples and returns the events they cause.
+func (v *Detector) Write(samples []int16) []Event {
+	var events []Event
+	for _, s := range samples {
+		v.frame = append(v.frame, float64(s)/32768)
+		if len(v.frame) == v.frameSize {
+			if e, ok := v.process(v.frame); ok {
+				events = append(events, e)
+			}
+			v.frame = v.frame[:0]
+		}
+	}
+	return events
+}
+
+// WritePCM analyses 16-bit little-endian PCM and returns the events it
+// causes.
+func (v *Detector) WritePCM(pcm []byte) []Event {
+	if v.odd != nil {
+		pcm = append(v.odd, pcm...)
+		v.odd = nil
+	}
+	samples := make([]int16, len(pcm)/2)
+	for i := range samples {
+		samples[i] = int16(binary.LittleEndian.Uint16(pcm[2*i:]))
+	}
+	if len(pcm)%2 == 1 {
+		v.odd = []byte{pcm[len(pcm)-1]}
+	}
+	return v.Write(samples)
+}
+
+// Flush ends the segment in progress, if any, at the end of the stream.
+func (v *Detector) Flush() []Event {
+	if !v.speaking {
+		return nil
+	}
+	v.speaking = false
+	v.quiet = 0
+	return []Event{{Type: SpeechEnd, Offset: v.end()}}
+}
+
+// Reset forgets the stream analysed so far, but for the noise floor.
+func (v *Detector) Reset() {
+	v.frame = v.frame[:0]
+	v.odd = nil
+	v.frames = 0
+	v.speaking = false
+	v.run = 0
+	v.quiet = 0
+	v.lastSpeech = 0
+}
+
+// process classifies a frame and advances the segment state.
+func (v *Detector) process(frame []float64) (Event, bool) {
+	index := v.frames
+	v.frames++
+	speech := v.classify(frame)
+
+	if !v.speaking {
+		if !speech {
+			v.run = 0
+			return Event{}, false
+		}
+		v.run++
+		v.lastSpeech = index
+		if v.duration(int64(v.run)) < v.minSpeech {
+			return Event{}, false
+		}
+		v.speaking = true
+		v.quiet = 0
+		return Event{Type: SpeechStart, Offset: v.duration(index + 1 - int64(v.run))}, true
+	}
+
+	if speech {
+		v.quiet = 0
+		v.lastSpeech = index
+		return Event{}, false
+	}
+	v.quiet++
+	if v.duration(int64(v.quiet)) < v.minSilence {
+		return Event{}, false
+	}
+	v.speaking = false
+	v.run = 0
+	return Event{Type: SpeechEnd, Offset: v.end()}, true
+}
+
+// end returns the end of the current segment, the hangover after its last
+// speech frame.
+func (v *Detector) end() time.Duration {
+	end := v.duration(v.lastSpeech+1) + v.hangover
+	if now := v.duration(v.frames); end > now {
+		end = now
+	}
+	return end
+}
+
+// classify tells whether a frame is speech, and follows the noise floor on
+// the frames which are not.
+func (v *Detector) classify(frame []float64) bool {
+	var power float64
+	crossings := 0
+	for i, s := range frame {
+		power += s * s
+		if i > 0 && (s >= 0) != (frame[i-1] >= 0) {
+			crossings++
+		}
+	}
+	energy := 10 * math.Log10(power/float64(len(frame))+1e-10)
+	speech := energy > minEnergy && energy > v.noise+v.energyThreshold
+	if speech {
+		zcr := float64(crossings) / float64(len(frame)-1)
+		speech = v.flatness(frame) <= v.maxFlatness || zcr <= v.maxZeroCrossingRate
+	}
+	if !speech {
+		// the floor drops at once and rises slowly
+		if energy < v.noise {
+			v.noise = energy
+		} else {
+			v.noise += 0.05 * (energy - v.noise)
+		}
+	}
+	return speech
+}
+
+// flatness returns the spectral flatness of a frame, the ratio of the
+// geometric mean of its power spectrum to its arithmetic mean.
+func (v *Detector) flatness(frame []float64) float64 {
+	for i := range v.spectrum {
+		v.spectrum[i] = 0
+	}
+	for i, s := range frame {
+		v.spectrum[i] = complex(s*v.window[i], 0)
+	}
+	fft(v.spectrum)
+
+	bins := v.spectrum[1 : len(v.spectrum)/2+1]
+	var logSum, sum float64
+	for _, bin := range bins {
+		p := real(bin)*real(bin) + imag(bin)*imag(bin) + 1e-12
+		logSum += math.Log(p)
+		sum += p
+	}
+	n := float64(len(bins))
+	return math.Exp(logSum/n) / (sum / n)
+}
+
+func (v *Detector) duration(frames int64) time.Duration {
+	return time.Duration(frames) * v.frameDuration
+}
+
+// fft computes the discrete Fourier transform of x in place. The length of
+// x is a power of two.
+func fft(x []complex128) {
+	n := len(x)
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j ^= bit
+		if i < j {
+			x[i], x[j] = x[j], x[i]
+		}
+	}
+	for size := 2; size <= n; size <<= 1 {
+		step := cmplx.Exp(complex(0, -2*math.Pi/float64(size)))
+		for start := 0; start < n; start += size {
+			w := complex(1, 0)
+			for k := 0; k < size/2; k++ {
+				a, b := x[start+k], x[start+k+size/2]*w
+				x[start+k], x[start+k+size/2] = a+b, a-b
+				w *= step
+			}
+		}
+	}
+}
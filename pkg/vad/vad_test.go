@@ -0,0 +1,121 @@
+package vad
+
+// Copyright (c) 2018 Bhojpur Consulting Private Limited, India. All rights reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const rate = 16000
+
+func silence(seconds float64) []int16 {
+	return make([]int16, int(seconds*rate))
+}
+
+// voice is a vowel-like sound, a 150 Hz fundamental with fading harmonics.
+func voice(seconds float64) []int16 {
+	samples := make([]int16, int(seconds*rate))
+	for i := range samples {
+		var s float64
+		for h := 1; h <= 10; h++ {
+			s += math.Sin(2*math.Pi*150*float64(h)*float64(i)/rate) / float64(h)
+		}
+		samples[i] = int16(s * 5000)
+	}
+	return samples
+}
+
+func noise(seconds float64) []int16 {
+	r := rand.New(rand.NewSource(1))
+	samples := make([]int16, int(seconds*rate))
+	for i := range samples {
+		samples[i] = int16(r.Intn(20000) - 10000)
+	}
+	return samples
+}
+
+func concat(parts ...[]int16) []int16 {
+	var all []int16
+	for _, part := range parts {
+		all = append(all, part...)
+	}
+	return all
+}
+
+func TestSegments(t *testing.T) {
+	v := New(rate)
+	events := v.Write(concat(silence(1), voice(1), silence(0.2), voice(0.5), silence(1)))
+	assert.Equal(t, []Event{
+		{Type: SpeechStart, Offset: time.Second},
+		// the short pause is bridged, the end keeps the hangover
+		{Type: SpeechEnd, Offset: 2700*time.Millisecond + DefaultHangover},
+	}, events)
+	assert.False(t, v.Speaking())
+}
+
+func TestFlush(t *testing.T) {
+	v := New(rate)
+	// a stream may start with speech
+	events := v.Write(voice(0.5))
+	assert.Equal(t, []Event{{Type: SpeechStart, Offset: 0}}, events)
+	assert.True(t, v.Speaking())
+	assert.Equal(t, []Event{{Type: SpeechEnd, Offset: 500 * time.Millisecond}}, v.Flush())
+	assert.Nil(t, v.Flush())
+}
+
+func TestIgnoresNoiseAndClicks(t *testing.T) {
+	v := New(rate)
+	assert.Empty(t, v.Write(concat(silence(0.5), noise(1), silence(0.5), voice(0.05), silence(1))))
+
+	// voice is still heard over a background noise
+	background := noise(1.5)
+	for i := range background {
+		background[i] /= 30
+	}
+	speech := concat(silence(0.5), voice(0.5), silence(0.5))
+	for i := range speech {
+		speech[i] += background[i]
+	}
+	v = New(rate, WithMinSilence(300*time.Millisecond), WithHangover(0))
+	events := v.Write(speech)
+	assert.Equal(t, []Event{
+		{Type: SpeechStart, Offset: 500 * time.Millisecond},
+		{Type: SpeechEnd, Offset: time.Second},
+	}, events)
+}
+
+func TestWritePCM(t *testing.T) {
+	samples := concat(silence(0.5), voice(0.5))
+	pcm := make([]byte, 2*len(samples))
+	for i, s := range samples {
+		pcm[2*i] = byte(s)
+		pcm[2*i+1] = byte(uint16(s) >> 8)
+	}
+	v := New(rate)
+	// frames may span the writes
+	events := append(v.WritePCM(pcm[:1001]), v.WritePCM(pcm[1001:])...)
+	assert.Equal(t, []Event{{Type: SpeechStart, Offset: 500 * time.Millisecond}}, events)
+}
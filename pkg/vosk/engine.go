@@ -0,0 +1,290 @@
+package vosk
+
+// Copyright (c) 2018 Bhojpur Consulting Private Limited, India. All rights reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/bhojpur/speech/pkg/asr"
+	"github.com/bhojpur/speech/pkg/models"
+)
+
+func init() {
+	asr.Register("vosk", Open)
+}
+
+// Engine adapts a VoskModel to the asr.Engine interface.
+type Engine struct {
+	model      *VoskModel
+	spkModel   *VoskSpkModel
+	sampleRate float64
+
+	endpointerMode EndpointerMode
+	delays         *endpointerDelays
+}
+
+// endpointerDelays are the arguments of VoskRecognizer.SetEndpointerDelays.
+type endpointerDelays struct {
+	startMax, end, max time.Duration
+}
+
+// Open loads the Vosk model found in the directory path, along with the
+// speaker model described by its metadata, if any.
+func Open(path string) (asr.Engine, error) {
+	info, err := models.ReadInfo(path)
+	if err != nil {
+		return nil, err
+	}
+	model, err := NewModel(path)
+	if err != nil {
+		return nil, err
+	}
+	engine := NewEngine(model, info.SampleRate)
+	if info.SpeakerModel != "" {
+		spkModel, err := NewSpkModel(info.SpeakerModel)
+		if err != nil {
+			engine.Close()
+			return nil, err
+		}
+		engine.SetSpeakerModel(spkModel)
+	}
+	return engine, nil
+}
+
+// NewEngine wraps an already loaded model. The engine takes the ownership of
+// the model and frees it on Close.
+func NewEngine(model *VoskModel, sampleRate float64) *Engine {
+	return &Engine{
+		model:      model,
+		sampleRate: sampleRate,
+	}
+}
+
+// Model returns the underlying Vosk model.
+func (e *Engine) Model() *VoskModel {
+	return e.model
+}
+
+// SetSpeakerModel enables speaker embeddings. The engine takes the
+// ownership of the model and frees it on Close.
+func (e *Engine) SetSpeakerModel(spkModel *VoskSpkModel) {
+	e.spkModel = spkModel
+}
+
+// SetEndpointerMode scales the delays ending the utterances of the
+// recognizers created afterwards.
+func (e *Engine) SetEndpointerMode(mode EndpointerMode) {
+	e.endpointerMode = mode
+}
+
+// SetEndpointerDelays sets the delays ending the utterances of the
+// recognizers created afterwards, overriding the endpointer mode. See
+// VoskRecognizer.SetEndpointerDelays.
+func (e *Engine) SetEndpointerDelays(startMax, end, max time.Duration) {
+	e.delays = &endpointerDelays{startMax: startMax, end: end, max: max}
+}
+
+// HasWord implements asr.Vocabulary.
+func (e *Engine) HasWord(word string) bool {
+	return e.model.FindWord([]byte(word)) >= 0
+}
+
+// Name implements asr.Engine.
+func (e *Engine) Name() string {
+	return "vosk"
+}
+
+// SampleRate implements asr.Engine.
+func (e *Engine) SampleRate() float64 {
+	return e.sampleRate
+}
+
+// NewRecognizer implements asr.Engine.
+func (e *Engine) NewRecognizer(config asr.Config) (asr.Recognizer, error) {
+	sampleRate := config.SampleRate
+	if sampleRate <= 0 {
+		sampleRate = e.sampleRate
+	}
+	if config.Speaker && e.spkModel == nil {
+		return nil, asr.ErrNoSpeakerModel
+	}
+	var rec *VoskRecognizer
+	var err error
+	switch {
+	case len(config.Grammar) > 0:
+		var grammar []byte
+		grammar, err = json.Marshal(config.Grammar)
+		if err != nil {
+			return nil, err
+		}
+		rec, err = NewRecognizerGrm(e.model, sampleRate, grammar)
+		if err == nil && config.Speaker {
+			rec.SetSpkModel(e.spkModel)
+		}
+	case config.Speaker:
+		rec, err = NewRecognizerSpk(e.model, sampleRate, e.spkModel)
+	default:
+		rec, err = NewRecognizer(e.model, sampleRate)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if config.MaxAlternatives > 1 {
+		rec.SetMaxAlternatives(config.MaxAlternatives)
+	}
+	if config.Words {
+		rec.SetWords(1)
+	}
+	if e.endpointerMode != EndpointerDefault {
+		rec.SetEndpointerMode(e.endpointerMode)
+	}
+	if e.delays != nil {
+		rec.SetEndpointerDelays(e.delays.startMax, e.delays.end, e.delays.max)
+	}
+	return &engineRecognizer{rec: rec}, nil
+}
+
+// Close implements asr.Engine.
+func (e *Engine) Close() error {
+	if e.spkModel != nil {
+		e.spkModel.Free()
+	}
+	e.model.Free()
+	return nil
+}
+
+// engineRecognizer adapts a VoskRecognizer to the asr.Recognizer interface.
+type engineRecognizer struct {
+	rec *VoskRecognizer
+}
+
+func (r *engineRecognizer) AcceptWaveform(pcm []byte) (bool, error) {
+	switch r.rec.AcceptWaveform(pcm) {
+	case 0:
+		return false, nil
+	case 1:
+		return true, nil
+	default:
+		return false, errors.New("vosk: failed to accept waveform")
+	}
+}
+
+func (r *engineRecognizer) Result() (*asr.Result, error) {
+	res, err := r.rec.Result()
+	if err != nil {
+		return nil, err
+	}
+	return convertResult(res), nil
+}
+
+func (r *engineRecognizer) PartialResult() (*asr.Result, error) {
+	partial, err := r.rec.PartialResult()
+	if err != nil {
+		return nil, err
+	}
+	res := &asr.Result{}
+	if partial.Partial != "" {
+		res.Alternatives = []asr.Alternative{{Text: partial.Partial, Words: words(partial.Words)}}
+	}
+	return res, nil
+}
+
+func (r *engineRecognizer) FinalResult() (*asr.Result, error) {
+	res, err := r.rec.FinalResult()
+	if err != nil {
+		return nil, err
+	}
+	return convertResult(res), nil
+}
+
+func (r *engineRecognizer) Reset() error {
+	r.rec.Reset()
+	return nil
+}
+
+func (r *engineRecognizer) Close() error {
+	r.rec.Free()
+	return nil
+}
+
+func convertResult(res *Result) *asr.Result {
+	result := &asr.Result{
+		Final:         true,
+		Speaker:       res.Speaker,
+		SpeakerFrames: res.SpeakerFrames,
+	}
+	if len(res.Alternatives) > 0 {
+		for _, alt := range res.Alternatives {
+			if alt.Text == "" {
+				continue
+			}
+			result.Alternatives = append(result.Alternatives, asr.Alternative{
+				Text:       alt.Text,
+				Confidence: alt.Confidence,
+				Words:      words(alt.Words),
+			})
+		}
+	} else if res.Text != "" {
+		result.Alternatives = []asr.Alternative{{
+			Text:       res.Text,
+			Confidence: averageConfidence(res.Words),
+			Words:      words(res.Words),
+		}}
+	}
+	return result
+}
+
+func words(in []Word) []asr.Word {
+	if len(in) == 0 {
+		return nil
+	}
+	out := make([]asr.Word, len(in))
+	for i, w := range in {
+		out[i] = asr.Word{
+			Word:       w.Word,
+			Start:      seconds(w.Start),
+			End:        seconds(w.End),
+			Confidence: w.Conf,
+		}
+	}
+	return out
+}
+
+// averageConfidence returns the mean word confidence of a result, which is
+// the closest thing Vosk provides to an utterance confidence when
+// alternatives are disabled.
+func averageConfidence(words []Word) float64 {
+	if len(words) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, w := range words {
+		sum += w.Conf
+	}
+	return sum / float64(len(words))
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(math.Round(s * float64(time.Second)))
+}
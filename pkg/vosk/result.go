@@ -0,0 +1,79 @@
+package vosk
+
+// Copyright (c) 2018 Bhojpur Consulting Private Limited, India. All rights reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Word is a recognized word, with its times in seconds from the start of
+// the stream.
+type Word struct {
+	Word  string  `json:"word"`
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+	Conf  float64 `json:"conf"`
+}
+
+// Alternative is a hypothesis of the n-best list of a result.
+type Alternative struct {
+	Text       string  `json:"text"`
+	Confidence float64 `json:"confidence"`
+	Words      []Word  `json:"result"`
+}
+
+// Result is a final result of Result or FinalResult. Words are set when
+// enabled with SetWords, Alternatives, instead of Text and Words, when
+// enabled with SetMaxAlternatives, and Speaker, the x-vector of the
+// utterance, when the recognizer has a speaker model.
+type Result struct {
+	Text          string        `json:"text"`
+	Words         []Word        `json:"result"`
+	Alternatives  []Alternative `json:"alternatives"`
+	Speaker       []float64     `json:"spk"`
+	SpeakerFrames int           `json:"spk_frames"`
+}
+
+// PartialResult is the hypothesis of an utterance being recognized. Words
+// are set when enabled with SetPartialWords.
+type PartialResult struct {
+	Partial string `json:"partial"`
+	Words   []Word `json:"partial_result"`
+}
+
+// ParseResult parses a final result in JSON.
+func ParseResult(data []byte) (*Result, error) {
+	res := &Result{}
+	if err := json.Unmarshal(data, res); err != nil {
+		return nil, fmt.Errorf("vosk: invalid result: %v", err)
+	}
+	return res, nil
+}
+
+// ParsePartialResult parses a partial result in JSON.
+func ParsePartialResult(data []byte) (*PartialResult, error) {
+	res := &PartialResult{}
+	if err := json.Unmarshal(data, res); err != nil {
+		return nil, fmt.Errorf("vosk: invalid partial result: %v", err)
+	}
+	return res, nil
+}
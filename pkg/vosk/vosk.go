@@ -26,6 +26,22 @@ package vosk
 // #include <vosk_api.h>
 import "C"
 
+import (
+	"errors"
+	"runtime"
+	"time"
+	"unsafe"
+)
+
+// ErrClosed is returned by the functions given an object already freed.
+var ErrClosed = errors.New("vosk: object already freed")
+
+// The objects wrapping the C library free it when they are garbage
+// collected, as a safety net: they should be freed with Free as soon as
+// they are not needed anymore, since the garbage collector does not see the
+// memory of the library. A model is reference counted by the library, and
+// stays loaded while the recognizers created from it are alive.
+
 // VoskModel contains a reference to the C VoskModel
 type VoskModel struct {
 	model *C.struct_VoskModel
@@ -33,26 +49,41 @@ type VoskModel struct {
 
 // NewModel creates a new VoskModel instance
 func NewModel(modelPath string) (*VoskModel, error) {
-	internal := C.vosk_model_new(C.CString(modelPath))
+	cpath := C.CString(modelPath)
+	defer C.free(unsafe.Pointer(cpath))
+	internal := C.vosk_model_new(cpath)
+	if internal == nil {
+		return nil, errors.New("vosk: failed to load model " + modelPath)
+	}
 	model := &VoskModel{model: internal}
+	runtime.SetFinalizer(model, freeModel)
 	return model, nil
 }
 
+// Free releases the model. It does nothing when the model is already
+// freed.
 func (m *VoskModel) Free() {
+	if m.model == nil {
+		return
+	}
 	C.vosk_model_free(m.model)
+	m.model = nil
+	runtime.SetFinalizer(m, nil)
 }
 
 func freeModel(model *VoskModel) {
-	C.vosk_model_free(model.model)
+	model.Free()
 }
 
 // FindWord checks if a word can be recognized by the model.
 // Returns the word symbol if the word exists inside the model or
 // -1 otherwise.
 func (m *VoskModel) FindWord(word []byte) int {
-	cbuf := C.CBytes(word)
-	defer C.free(cbuf)
-	i := C.vosk_model_find_word(m.model, (*C.char)(cbuf))
+	// the word must be NUL terminated
+	cstr := C.CString(string(word))
+	defer C.free(unsafe.Pointer(cstr))
+	i := C.vosk_model_find_word(m.model, cstr)
+	runtime.KeepAlive(m)
 	return int(i)
 }
 
@@ -63,17 +94,30 @@ type VoskSpkModel struct {
 
 // NewSpkModel creates a new VoskSpkModel instance
 func NewSpkModel(spkModelPath string) (*VoskSpkModel, error) {
-	internal := C.vosk_spk_model_new(C.CString(spkModelPath))
+	cpath := C.CString(spkModelPath)
+	defer C.free(unsafe.Pointer(cpath))
+	internal := C.vosk_spk_model_new(cpath)
+	if internal == nil {
+		return nil, errors.New("vosk: failed to load speaker model " + spkModelPath)
+	}
 	spkModel := &VoskSpkModel{spkModel: internal}
+	runtime.SetFinalizer(spkModel, freeSpkModel)
 	return spkModel, nil
 }
 
 func freeSpkModel(model *VoskSpkModel) {
-	C.vosk_spk_model_free(model.spkModel)
+	model.Free()
 }
 
+// Free releases the speaker model. It does nothing when the model is
+// already freed.
 func (s *VoskSpkModel) Free() {
+	if s.spkModel == nil {
+		return
+	}
 	C.vosk_spk_model_free(s.spkModel)
+	s.spkModel = nil
+	runtime.SetFinalizer(s, nil)
 }
 
 // VoskRecognizer contains a reference to the C VoskRecognizer
@@ -82,49 +126,121 @@ type VoskRecognizer struct {
 }
 
 func freeRecognizer(recognizer *VoskRecognizer) {
-	C.vosk_recognizer_free(recognizer.rec)
+	recognizer.Free()
 }
 
+// Free releases the recognizer. It does nothing when the recognizer is
+// already freed.
 func (r *VoskRecognizer) Free() {
+	if r.rec == nil {
+		return
+	}
 	C.vosk_recognizer_free(r.rec)
+	r.rec = nil
+	runtime.SetFinalizer(r, nil)
+}
+
+// newRecognizer wraps a recognizer returned by the library, NULL on
+// failure.
+func newRecognizer(internal *C.struct_VoskRecognizer) (*VoskRecognizer, error) {
+	if internal == nil {
+		return nil, errors.New("vosk: failed to create recognizer")
+	}
+	rec := &VoskRecognizer{rec: internal}
+	runtime.SetFinalizer(rec, freeRecognizer)
+	return rec, nil
 }
 
 // NewRecognizer creates a new VoskRecognizer instance
 func NewRecognizer(model *VoskModel, sampleRate float64) (*VoskRecognizer, error) {
+	if model == nil || model.model == nil {
+		return nil, ErrClosed
+	}
 	internal := C.vosk_recognizer_new(model.model, C.float(sampleRate))
-	rec := &VoskRecognizer{rec: internal}
-	return rec, nil
+	runtime.KeepAlive(model)
+	return newRecognizer(internal)
 }
 
 // NewRecognizerSpk creates a new VoskRecognizer instance with a speaker model.
 func NewRecognizerSpk(model *VoskModel, sampleRate float64, spkModel *VoskSpkModel) (*VoskRecognizer, error) {
+	if model == nil || model.model == nil || spkModel == nil || spkModel.spkModel == nil {
+		return nil, ErrClosed
+	}
 	internal := C.vosk_recognizer_new_spk(model.model, C.float(sampleRate), spkModel.spkModel)
-	rec := &VoskRecognizer{rec: internal}
-	return rec, nil
+	runtime.KeepAlive(model)
+	runtime.KeepAlive(spkModel)
+	return newRecognizer(internal)
 }
 
 // NewRecognizerGrm creates a new VoskRecognizer instance with the phrase list.
 func NewRecognizerGrm(model *VoskModel, sampleRate float64, grammer []byte) (*VoskRecognizer, error) {
-	cbuf := C.CBytes(grammer)
-	defer C.free(cbuf)
-	internal := C.vosk_recognizer_new_grm(model.model, C.float(sampleRate), (*C.char)(cbuf))
-	rec := &VoskRecognizer{rec: internal}
-	return rec, nil
+	if model == nil || model.model == nil {
+		return nil, ErrClosed
+	}
+	// the grammar must be NUL terminated
+	cstr := C.CString(string(grammer))
+	defer C.free(unsafe.Pointer(cstr))
+	internal := C.vosk_recognizer_new_grm(model.model, C.float(sampleRate), cstr)
+	runtime.KeepAlive(model)
+	return newRecognizer(internal)
 }
 
 // SetSpkModel adds a speaker model to an already initialized recognizer.
 func (r *VoskRecognizer) SetSpkModel(spkModel *VoskSpkModel) {
 	C.vosk_recognizer_set_spk_model(r.rec, spkModel.spkModel)
+	runtime.KeepAlive(r)
+	runtime.KeepAlive(spkModel)
 }
 
 // SetMaxAlternatives configures the recognizer to output n-best results.
 func (r *VoskRecognizer) SetMaxAlternatives(maxAlternatives int) {
 	C.vosk_recognizer_set_max_alternatives(r.rec, C.int(maxAlternatives))
+	runtime.KeepAlive(r)
 }
 
 // SetWords enables words with times in the ouput.
 func (r *VoskRecognizer) SetWords(words int) {
 	C.vosk_recognizer_set_words(r.rec, C.int(words))
+	runtime.KeepAlive(r)
+}
+
+// SetPartialWords enables words with times in the partial results.
+func (r *VoskRecognizer) SetPartialWords(partialWords int) {
+	C.vosk_recognizer_set_partial_words(r.rec, C.int(partialWords))
+	runtime.KeepAlive(r)
+}
+
+// SetNLSML switches the results to NLSML, which only the Raw methods
+// return.
+func (r *VoskRecognizer) SetNLSML(nlsml int) {
+	C.vosk_recognizer_set_nlsml(r.rec, C.int(nlsml))
+	runtime.KeepAlive(r)
+}
+
+// EndpointerMode scales the delays of the endpointer ending the
+// utterances.
+type EndpointerMode int
+
+const (
+	EndpointerDefault  EndpointerMode = C.VOSK_EP_ANSWER_DEFAULT
+	EndpointerShort    EndpointerMode = C.VOSK_EP_ANSWER_SHORT
+	EndpointerLong     EndpointerMode = C.VOSK_EP_ANSWER_LONG
+	EndpointerVeryLong EndpointerMode = C.VOSK_EP_ANSWER_VERY_LONG
+)
+
+// SetEndpointerMode scales the delays ending the utterances, shorter
+// delays suiting short answers.
+func (r *VoskRecognizer) SetEndpointerMode(mode EndpointerMode) {
+	C.vosk_recognizer_set_endpointer_mode(r.rec, C.VoskEndpointerMode(mode))
+	runtime.KeepAlive(r)
+}
+
+// SetEndpointerDelays sets the silence ending an utterance without speech
+// (startMax), the silence ending an utterance after speech (end), and the
+// longest utterance (max).
+func (r *VoskRecognizer) SetEndpointerDelays(startMax, end, max time.Duration) {
+	C.vosk_recognizer_set_endpointer_delays(r.rec, C.float(startMax.Seconds()), C.float(end.Seconds()), C.float(max.Seconds()))
+	runtime.KeepAlive(r)
 }
 
 // AcceptWaveform accepts and processes a new chunk of the voice data.
@@ -132,28 +248,197 @@ func (r *VoskRecognizer) AcceptWaveform(buffer []byte) int {
 	cbuf := C.CBytes(buffer)
 	defer C.free(cbuf)
 	i := C.vosk_recognizer_accept_waveform(r.rec, (*C.char)(cbuf), C.int(len(buffer)))
+	runtime.KeepAlive(r)
+	return int(i)
+}
+
+// AcceptWaveformInt16 is AcceptWaveform for 16-bit samples.
+func (r *VoskRecognizer) AcceptWaveformInt16(samples []int16) int {
+	if len(samples) == 0 {
+		return r.AcceptWaveform(nil)
+	}
+	cbuf := C.CBytes(unsafe.Slice((*byte)(unsafe.Pointer(&samples[0])), 2*len(samples)))
+	defer C.free(cbuf)
+	i := C.vosk_recognizer_accept_waveform_s(r.rec, (*C.short)(cbuf), C.int(len(samples)))
+	runtime.KeepAlive(r)
+	return int(i)
+}
+
+// AcceptWaveformFloat32 is AcceptWaveform for samples scaled as 16-bit
+// integers, between -32768 and 32767.
+func (r *VoskRecognizer) AcceptWaveformFloat32(samples []float32) int {
+	if len(samples) == 0 {
+		return r.AcceptWaveform(nil)
+	}
+	cbuf := C.CBytes(unsafe.Slice((*byte)(unsafe.Pointer(&samples[0])), 4*len(samples)))
+	defer C.free(cbuf)
+	i := C.vosk_recognizer_accept_waveform_f(r.rec, (*C.float)(cbuf), C.int(len(samples)))
+	runtime.KeepAlive(r)
 	return int(i)
 }
 
 // Result returns a speech recognition result.
-func (r *VoskRecognizer) Result() []byte {
-	return []byte(C.GoString(C.vosk_recognizer_result(r.rec)))
+func (r *VoskRecognizer) Result() (*Result, error) {
+	return ParseResult(r.RawResult())
 }
 
 // PartialResult returns a partial speech recognition result.
-func (r *VoskRecognizer) PartialResult() []byte {
-	return []byte(C.GoString(C.vosk_recognizer_partial_result(r.rec)))
+func (r *VoskRecognizer) PartialResult() (*PartialResult, error) {
+	return ParsePartialResult(r.RawPartialResult())
 }
 
 // FinalResult returns a speech recognition result. Same as result, but doesn't wait
 // for silence.
-func (r *VoskRecognizer) FinalResult() []byte {
+func (r *VoskRecognizer) FinalResult() (*Result, error) {
+	return ParseResult(r.RawFinalResult())
+}
+
+// RawResult returns the result as the library formats it, JSON or NLSML.
+func (r *VoskRecognizer) RawResult() []byte {
+	defer runtime.KeepAlive(r)
+	return []byte(C.GoString(C.vosk_recognizer_result(r.rec)))
+}
+
+// RawPartialResult returns the partial result as the library formats it.
+func (r *VoskRecognizer) RawPartialResult() []byte {
+	defer runtime.KeepAlive(r)
+	return []byte(C.GoString(C.vosk_recognizer_partial_result(r.rec)))
+}
+
+// RawFinalResult returns the final result as the library formats it.
+func (r *VoskRecognizer) RawFinalResult() []byte {
+	defer runtime.KeepAlive(r)
 	return []byte(C.GoString(C.vosk_recognizer_final_result(r.rec)))
 }
 
 // Reset resets the recognizer.
 func (r *VoskRecognizer) Reset() {
 	C.vosk_recognizer_reset(r.rec)
+	runtime.KeepAlive(r)
+}
+
+// VoskBatchModel contains a reference to the C VoskBatchModel, which
+// recognizes many streams at once on the GPU.
+type VoskBatchModel struct {
+	model *C.struct_VoskBatchModel
+}
+
+// NewBatchModel creates a new VoskBatchModel instance. The library loads
+// the model found in the model directory of the working directory, and
+// fails unless it is built with CUDA.
+func NewBatchModel() (*VoskBatchModel, error) {
+	internal := C.vosk_batch_model_new()
+	if internal == nil {
+		return nil, errors.New("vosk: failed to load batch model")
+	}
+	model := &VoskBatchModel{model: internal}
+	runtime.SetFinalizer(model, freeBatchModel)
+	return model, nil
+}
+
+func freeBatchModel(model *VoskBatchModel) {
+	model.Free()
+}
+
+// Free releases the batch model. It does nothing when the model is already
+// freed.
+func (m *VoskBatchModel) Free() {
+	if m.model == nil {
+		return
+	}
+	C.vosk_batch_model_free(m.model)
+	m.model = nil
+	runtime.SetFinalizer(m, nil)
+}
+
+// Wait waits for the audio accepted by the recognizers to be processed.
+func (m *VoskBatchModel) Wait() {
+	C.vosk_batch_model_wait(m.model)
+	runtime.KeepAlive(m)
+}
+
+// VoskBatchRecognizer contains a reference to the C VoskBatchRecognizer
+type VoskBatchRecognizer struct {
+	rec *C.struct_VoskBatchRecognizer
+}
+
+// NewBatchRecognizer creates a new VoskBatchRecognizer instance
+func NewBatchRecognizer(model *VoskBatchModel, sampleRate float64) (*VoskBatchRecognizer, error) {
+	if model == nil || model.model == nil {
+		return nil, ErrClosed
+	}
+	internal := C.vosk_batch_recognizer_new(model.model, C.float(sampleRate))
+	runtime.KeepAlive(model)
+	if internal == nil {
+		return nil, errors.New("vosk: failed to create batch recognizer")
+	}
+	rec := &VoskBatchRecognizer{rec: internal}
+	runtime.SetFinalizer(rec, freeBatchRecognizer)
+	return rec, nil
+}
+
+func freeBatchRecognizer(recognizer *VoskBatchRecognizer) {
+	recognizer.Free()
+}
+
+// Free releases the batch recognizer. It does nothing when the recognizer
+// is already freed.
+func (r *VoskBatchRecognizer) Free() {
+	if r.rec == nil {
+		return
+	}
+	C.vosk_batch_recognizer_free(r.rec)
+	r.rec = nil
+	runtime.SetFinalizer(r, nil)
+}
+
+// AcceptWaveform queues a new chunk of the voice data.
+func (r *VoskBatchRecognizer) AcceptWaveform(buffer []byte) {
+	cbuf := C.CBytes(buffer)
+	defer C.free(cbuf)
+	C.vosk_batch_recognizer_accept_waveform(r.rec, (*C.char)(cbuf), C.int(len(buffer)))
+	runtime.KeepAlive(r)
+}
+
+// SetNLSML switches the results to NLSML.
+func (r *VoskBatchRecognizer) SetNLSML(nlsml int) {
+	C.vosk_batch_recognizer_set_nlsml(r.rec, C.int(nlsml))
+	runtime.KeepAlive(r)
+}
+
+// FinishStream tells that no more audio comes.
+func (r *VoskBatchRecognizer) FinishStream() {
+	C.vosk_batch_recognizer_finish_stream(r.rec)
+	runtime.KeepAlive(r)
+}
+
+// FrontResult returns the oldest result not popped yet, as the library
+// formats it, empty when there is none.
+func (r *VoskBatchRecognizer) FrontResult() []byte {
+	defer runtime.KeepAlive(r)
+	return []byte(C.GoString(C.vosk_batch_recognizer_front_result(r.rec)))
+}
+
+// Front parses the oldest result not popped yet. It returns nil when there
+// is none.
+func (r *VoskBatchRecognizer) Front() (*Result, error) {
+	data := r.FrontResult()
+	if len(data) == 0 {
+		return nil, nil
+	}
+	return ParseResult(data)
+}
+
+// Pop discards the oldest result.
+func (r *VoskBatchRecognizer) Pop() {
+	C.vosk_batch_recognizer_pop(r.rec)
+	runtime.KeepAlive(r)
+}
+
+// PendingChunks returns the number of chunks waiting to be processed.
+func (r *VoskBatchRecognizer) PendingChunks() int {
+	defer runtime.KeepAlive(r)
+	return int(C.vosk_batch_recognizer_get_pending_chunks(r.rec))
 }
 
 // SetLogLevel sets the log level for Kaldi messages.
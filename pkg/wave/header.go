@@ -0,0 +1,100 @@
+package wave
+
+// Copyright (c) 2018 Bhojpur Consulting Private Limited, India. All rights reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// ReadHeader reads a RIFF WAVE header from a stream. It skips the chunks
+// other than "fmt " and stops at the beginning of the "data" chunk, so the
+// samples can be read from r afterwards. Unlike Reader, it does not need
+// io.ReaderAt and thus works on sockets and pipes.
+//
+// It returns io.ErrUnexpectedEOF when r ends before the "data" chunk.
+// WAVE_FORMAT_EXTENSIBLE headers are reported with their sub-format.
+func ReadHeader(r io.Reader) (format *WavFormat, dataSize uint32, err error) {
+	var riffHeader [12]byte
+	if err = readFull(r, riffHeader[:]); err != nil {
+		return
+	}
+	if string(riffHeader[0:4]) != "RIFF" || string(riffHeader[8:12]) != "WAVE" {
+		err = errors.New("Given bytes is not a WAVE format")
+		return
+	}
+
+	for {
+		var chunkHeader [8]byte
+		if err = readFull(r, chunkHeader[:]); err != nil {
+			return
+		}
+		id := string(chunkHeader[0:4])
+		size := binary.LittleEndian.Uint32(chunkHeader[4:8])
+
+		if id == "data" {
+			if format == nil {
+				err = errors.New("Format chunk is not found")
+				return
+			}
+			dataSize = size
+			return
+		}
+
+		if size%2 == 1 {
+			size++
+		}
+
+		if id != "fmt " {
+			if _, err = io.CopyN(io.Discard, r, int64(size)); err != nil {
+				err = io.ErrUnexpectedEOF
+				return
+			}
+			continue
+		}
+
+		if size < 16 {
+			err = errors.New("Format chunk is too short")
+			return
+		}
+		chunk := make([]byte, size)
+		if err = readFull(r, chunk); err != nil {
+			return
+		}
+		format = new(WavFormat)
+		if err = binary.Read(bytes.NewReader(chunk), binary.LittleEndian, format); err != nil {
+			return
+		}
+		if format.AudioFormat == AudioFormatExtensible && len(chunk) >= 26 {
+			format.AudioFormat = binary.LittleEndian.Uint16(chunk[24:26])
+		}
+	}
+}
+
+func readFull(r io.Reader, p []byte) error {
+	_, err := io.ReadFull(r, p)
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
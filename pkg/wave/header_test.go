@@ -0,0 +1,68 @@
+package wave
+
+// Copyright (c) 2018 Bhojpur Consulting Private Limited, India. All rights reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestReadHeader(t *testing.T) {
+	buf := new(bytes.Buffer)
+	writer := NewWriter(buf, 2, 1, 8000, 16)
+	samples := make([]Sample, 2)
+	samples[0].Values[0] = 1
+	samples[1].Values[0] = -1
+	if err := writer.WriteSamples(samples); err != nil {
+		t.Fatal(err)
+	}
+
+	data := buf.Bytes()
+	r := bytes.NewReader(data)
+	format, dataSize, err := ReadHeader(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, AudioFormatPCM, int(format.AudioFormat))
+	assert.Equal(t, 1, int(format.NumChannels))
+	assert.Equal(t, 8000, int(format.SampleRate))
+	assert.Equal(t, 16, int(format.BitsPerSample))
+	assert.Equal(t, 4, int(dataSize))
+
+	rest, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.DeepEqual(t, []byte{1, 0, 0xff, 0xff}, rest)
+
+	for i := 0; i < 44; i++ {
+		_, _, err = ReadHeader(bytes.NewReader(data[:i]))
+		assert.Equal(t, io.ErrUnexpectedEOF, err)
+	}
+
+	_, _, err = ReadHeader(bytes.NewReader([]byte("RIFF\x00\x00\x00\x00AIFF")))
+	assert.ErrorContains(t, err, "not a WAVE")
+}
@@ -29,6 +29,9 @@ const (
 	AudioFormatIEEEFloat = 3
 	AudioFormatALaw      = 6
 	AudioFormatMULaw     = 7
+
+	// AudioFormatExtensible is replaced by the sub-format by ReadHeader.
+	AudioFormatExtensible = 0xFFFE
 )
 
 type WavFormat struct {